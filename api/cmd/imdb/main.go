@@ -0,0 +1,69 @@
+// Command imdb seeds the catalog from the IMDb non-commercial datasets,
+// title.basics.tsv.gz, name.basics.tsv.gz and title.principals.tsv.gz, found
+// in a directory. Films, actors and their parts are saved in batches with the
+// line of the file each batch ends at, so an interrupted import is resumed
+// from there. Films and actors which are already in the catalog are kept
+// and get their IMDb IDs.
+//
+//	imdb -dir /data/imdb -batch 1000
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"kinoteka/internal/storage"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory with the IMDb files")
+	batchSize := flag.Int("batch", 1000, "rows saved in one transaction")
+	reset := flag.Bool("reset", false, "forget the progress of earlier imports and read the files from the start")
+	flag.Parse()
+
+	if *batchSize <= 0 {
+		log.Fatal("batch must be a positive number")
+	}
+
+	name := os.Getenv("PG_NAME")
+	user := os.Getenv("PG_USER")
+	pass := os.Getenv("PG_PASSWORD")
+	host := os.Getenv("PG_HOST")
+
+	url := fmt.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=disable", user, pass, host, name)
+
+	conn, err := sqlx.Connect("postgres", url)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	imdb := service.NewIMDbService(storage.NewIMDbStorage(conn), *batchSize)
+
+	if *reset {
+		if err := imdb.ResetIMDbProgress(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, file := range []string{domain.IMDbTitles, domain.IMDbNames, domain.IMDbPrincipals} {
+		f, err := os.Open(filepath.Join(*dir, file))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		report, err := imdb.ImportIMDb(file, f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("%s: resumed after line %d, read %d, skipped %d, saved %d",
+			report.File, report.Resumed, report.Read, report.Skipped, report.Saved)
+	}
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"io/fs"
 	handler2 "kinoteka/internal/handler"
 	"kinoteka/internal/service"
 	"kinoteka/internal/storage"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 )
 
 // @title Kinoteka API
@@ -36,12 +39,110 @@ func main() {
 		log.Fatal(err)
 	}
 
-	storages := storage.NewStorage(conn)
-	services := service.NewService(storages)
+	fuzzyThreshold := 0.5
+	if threshold := os.Getenv("FUZZY_THRESHOLD"); threshold != "" {
+		fuzzyThreshold, err = strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if fuzzyThreshold < 0 || fuzzyThreshold > 1 {
+			log.Fatalf("FUZZY_THRESHOLD must be from 0 to 1, got %s", threshold)
+		}
+	}
+
+	maxImageSize := int64(10 << 20)
+	if size := os.Getenv("MAX_IMAGE_SIZE"); size != "" {
+		maxImageSize, err = strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if maxImageSize <= 0 {
+			log.Fatalf("MAX_IMAGE_SIZE must be positive, got %s", size)
+		}
+	}
+
+	maxPathDepth := 6
+	if depth := os.Getenv("MAX_PATH_DEPTH"); depth != "" {
+		maxPathDepth, err = strconv.Atoi(depth)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if maxPathDepth <= 0 {
+			log.Fatalf("MAX_PATH_DEPTH must be positive, got %s", depth)
+		}
+	}
+
+	trashRetention := 30 * 24 * time.Hour
+	if retention := os.Getenv("TRASH_RETENTION"); retention != "" {
+		trashRetention, err = time.ParseDuration(retention)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if trashRetention <= 0 {
+			log.Fatalf("TRASH_RETENTION must be positive, got %s", retention)
+		}
+	}
+
+	// With REQUIRE_IF_MATCH set, films and actors can only be updated or
+	// deleted by clients which tell the version they changed.
+	var requireVersion bool
+	if require := os.Getenv("REQUIRE_IF_MATCH"); require != "" {
+		requireVersion, err = strconv.ParseBool(require)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Uploaded images are kept in MEDIA_DIR and served under /media/ unless
+	// MEDIA_URL points to another server exposing the directory.
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	mediaURL := os.Getenv("MEDIA_URL")
+	if mediaURL == "" {
+		mediaURL = "/media"
+	}
+	blobs := storage.NewLocalBlobStore(mediaDir, mediaURL)
+
+	storages := storage.NewStorage(conn, blobs)
+	services := service.NewService(storages, service.Config{
+		FuzzyThreshold: fuzzyThreshold,
+		MaxImageSize:   maxImageSize,
+		MaxPathDepth:   maxPathDepth,
+		TrashRetention: trashRetention,
+		RequireVersion: requireVersion,
+	})
 
 	handler := handler2.New(services)
 
 	handler.RegisterHandlers()
+	http.Handle("GET /media/", http.StripPrefix("/media/", http.FileServer(filesOnly{http.Dir(mediaDir)})))
 
 	http.ListenAndServe(":8080", nil)
 }
+
+// filesOnly serves the files of a directory but not listings of it. The
+// directories are said not to exist, so they are answered with 404.
+type filesOnly struct {
+	http.FileSystem
+}
+
+func (fsys filesOnly) Open(name string) (http.File, error) {
+	f, err := fsys.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, fs.ErrNotExist
+	}
+
+	return f, nil
+}
@@ -44,15 +44,52 @@ const docTemplate = `{
                         "description": "Include films information",
                         "name": "withFilms",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of actors",
+                        "name": "withTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the list the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the list the client has, without films",
+                        "name": "If-Modified-Since",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "without films",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Actor"
+                            "$ref": "#/definitions/ActorPage"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Tag of the list"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Last change of the actors"
                             }
                         }
                     },
@@ -63,8 +100,17 @@ const docTemplate = `{
                             "items": {
                                 "$ref": "#/definitions/ActorFilm"
                             }
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Tag of the list"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request"
                     },
@@ -82,7 +128,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create actor. You must have admin role.",
+                "description": "Create actor. You must have admin role.\nThe created actor is returned with their ID, and Location tells where to get them.",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,7 +153,20 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Actor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the created actor"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the created actor, like /actor/1"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -137,13 +196,40 @@ const docTemplate = `{
                 ],
                 "summary": "Get actor by ID",
                 "operationId": "get-actor-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the actor the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the actor the client has",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/Actor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the actor, to be sent as If-Match"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Last change of the actor"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
                         "description": "Bad Request"
                     },
@@ -158,7 +244,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update actor by ID. You must have admin role.",
+                "description": "Update actor by ID. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -179,6 +265,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/Actor"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -188,6 +280,12 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
                     "default": {
                         "description": ""
                     }
@@ -199,7 +297,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete actor by ID. You must have admin role.",
+                "description": "Move actor to the trash. Their credits are kept until they are purged. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,6 +309,14 @@ const docTemplate = `{
                 ],
                 "summary": "Delete actor by ID",
                 "operationId": "delete-actor-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "204": {
                         "description": "No Content"
@@ -218,103 +324,98 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request"
                     },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
                     "default": {
                         "description": ""
                     }
                 }
-            }
-        },
-        "/film": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get list of films. You can search by title, actor name, surname",
+                "description": "Change some fields of actor by ID with a JSON merge patch (RFC 7396) sent as application/merge-patch+json. Fields left out are kept, null clears a field, nullable fields like information are replaced as a whole, valid unless Valid is given. Members the actor has no field for, and read-only ones like id or photo, are refused. The patched actor must be valid. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
                 ],
-                "summary": "Get List of films",
-                "operationId": "get-list-films",
+                "summary": "Patch actor by ID",
+                "operationId": "patch-actor-by-id",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Search by title",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "desc",
-                            "asc"
-                        ],
-                        "type": "string",
-                        "description": "Sort list by desc or asc",
-                        "name": "sort",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "rating",
-                            "title",
-                            "year"
-                        ],
-                        "type": "string",
-                        "description": "sort by params",
-                        "name": "orderBy",
-                        "in": "query"
+                        "description": "Fields of the actor to change",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Actor"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Search by actor",
-                        "name": "actor",
-                        "in": "query"
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Film"
-                            }
-                        }
-                    },
-                    "210": {
-                        "description": "",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ActorFilm"
+                            "$ref": "#/definitions/Actor"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the patched actor"
                             }
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "500": {
-                        "description": "Internal Server Error"
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
                     },
                     "default": {
                         "description": ""
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/actor/{id}/films": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create Film. You must have admin role.",
+                "description": "Get a page of the films of actor by ID with the parts the actor plays in them. Films go by year unless another order is asked for.",
                 "consumes": [
                     "application/json"
                 ],
@@ -322,45 +423,76 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
                 ],
-                "summary": "Create Film",
-                "operationId": "create-film",
+                "summary": "Get films of actor",
+                "operationId": "get-actor-films",
                 "parameters": [
                     {
-                        "description": "Film",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Film"
-                        }
+                        "enum": [
+                            "year",
+                            "title",
+                            "rating",
+                            "billing"
+                        ],
+                        "type": "string",
+                        "description": "Sort by params",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort list by desc or asc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of films",
+                        "name": "withTotal",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/FilmCreditPage"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
-                    "500": {
-                        "description": "Internal Server Error"
-                    },
                     "default": {
                         "description": ""
                     }
                 }
             }
         },
-        "/film/{id}": {
+        "/actor/{id}/path/{otherId}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Get Film by ID",
+                "description": "Get the shortest chain of films linking two actors, each step is a film the previous actor plays in together with the next one. The chain is searched up to a max number of films.",
                 "consumes": [
                     "application/json"
                 ],
@@ -368,139 +500,177 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
+                ],
+                "summary": "Get path between actors",
+                "operationId": "get-actor-path",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the other actor",
+                        "name": "otherId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Film by ID",
-                "operationId": "get-film-by-id",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Actor"
+                            "$ref": "#/definitions/ActorPath"
                         }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "default": {
                         "description": ""
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/actor/{id}/photo": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update Film by ID. You must have admin role.",
+                "description": "Upload photo of actor by ID as JPEG, PNG, GIF or WebP. Thumbnails are made from it. The version of the actor grows, so ETags read before the upload no longer match. You must have admin role.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
                 ],
-                "summary": "Update Film by ID",
-                "operationId": "update-film-by-id",
+                "summary": "Upload Photo",
+                "operationId": "upload-actor-photo",
                 "parameters": [
                     {
-                        "description": "Film",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Film"
-                        }
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Image"
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
                     "default": {
                         "description": ""
                     }
                 }
-            },
+            }
+        },
+        "/actor/{id}/restore": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Add actors to film by id. You must have admin role.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Take actor out of the trash with their credits. You must have admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
                 ],
-                "summary": "Add actors to film by id",
-                "operationId": "add-actor-to-film-by-id",
+                "summary": "Restore actor",
+                "operationId": "restore-actor",
                 "parameters": [
                     {
-                        "description": "Array of actor's id",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Data"
-                        }
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request"
                     },
+                    "404": {
+                        "description": "Not Found"
+                    },
                     "default": {
                         "description": ""
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/actor/{id}/revisions": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete Film by ID. You must have admin role.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the revisions of actor by ID, the latest first. Every update saves one, the first is how the actor was before their first update. Each revision tells the fields which changed since the revision before it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "films"
+                    "actors"
                 ],
-                "summary": "Delete Film by ID",
-                "operationId": "delete-film-by-id",
+                "summary": "Get revisions of actor",
+                "operationId": "get-actor-revisions",
                 "parameters": [
                     {
-                        "description": "Film",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Film"
-                        }
+                        "type": "integer",
+                        "description": "Actor ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ActorRevision"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request"
@@ -511,36 +681,296 @@ const docTemplate = `{
                 }
             }
         },
-        "/sign-in": {
-            "post": {
-                "description": "login",
-                "consumes": [
-                    "application/json"
+        "/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Get the changes of films and actors, the latest first. Every entry tells who made the change, in which request, and the fields it changed with their values before and after it. You must have admin role.\nActions are create, update, delete, restore, revert, cast_add, cast_replace, cast_remove, genre_add, poster, photo and purge. Cast changes are told as a change of the cast field of the film, added genres as one of its genres field and uploads as one of the poster or photo field.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sign"
+                    "audit"
                 ],
-                "summary": "SignIn",
-                "operationId": "login",
+                "summary": "Get audit log",
+                "operationId": "get-audit",
                 "parameters": [
                     {
-                        "description": "credentials",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
+                        "enum": [
+                            "film",
+                            "actor"
+                        ],
+                        "type": "string",
+                        "description": "Kind of entity",
+                        "name": "entityType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID, needs entityType",
+                        "name": "entityId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the user who made the changes",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes made at or after, like 2006-01-02T15:04:05Z or 2006-01-02",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Changes made before, like 2006-01-02T15:04:05Z or 2006-01-02",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of entries",
+                        "name": "withTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/signInInput"
+                            "$ref": "#/definitions/AuditPage"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/export/actors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Export all actors in order of IDs. You must have admin role.\nActors are written as they are read from the database. Credits go to the last CSV column as a JSON array.\nA CSV or NDJSON export can be imported back as new actors: id and credits are skipped by imports.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export actors",
+                "operationId": "export-actors",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Format of the file, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the films of every actor",
+                        "name": "withCredits",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/TokenResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExportActor"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/export/films": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Export films matching the filters of the film list, in order of IDs. As in the film list, if no title or actor matches, similar ones do. You must have admin role.\nFilms are written as they are read from the database. Credits go to the last CSV column as a JSON array.\nA CSV or NDJSON export can be imported back as new films: id, rating, votes and credits are skipped by imports.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export films",
+                "operationId": "export-films",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Format of the file, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include the cast of every film",
+                        "name": "withCredits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by actor name, surname or patronymic",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal year",
+                        "name": "yearFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal year",
+                        "name": "yearTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal user rating",
+                        "name": "minRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximal user rating",
+                        "name": "maxRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only films with or without user votes",
+                        "name": "hasRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal runtime in minutes",
+                        "name": "minRuntime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal runtime in minutes",
+                        "name": "maxRuntime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or after the date, YYYY-MM-DD",
+                        "name": "releasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or before the date, YYYY-MM-DD",
+                        "name": "releasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Production country, ISO 3166-1 alpha-2 code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Original language, ISO 639 code",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only films which are or aren't in the watchlist of the user",
+                        "name": "inWatchlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated genre names",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "or",
+                            "and"
+                        ],
+                        "type": "string",
+                        "description": "Film must have all (and) or any (or) of the genres",
+                        "name": "genreMode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExportFilm"
+                            }
                         }
                     },
                     "400": {
@@ -555,71 +985,2615 @@ const docTemplate = `{
                 }
             }
         },
-        "/sign-up": {
-            "post": {
-                "description": "create account",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "sign"
-                ],
-                "summary": "SignUp",
-                "operationId": "create-account",
-                "parameters": [
-                    {
-                        "description": "account info",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/signUpInput"
-                        }
+        "/film": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of films. All filters can be combined.\nIf nothing matches title or actor exactly, films with a similar title or actor are returned\nwith the similarity of every film and \"fuzzy\": true. They are ordered by similarity unless orderBy is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Get List of films",
+                "operationId": "get-list-films",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by title",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by actor name, surname or patronymic",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal year",
+                        "name": "yearFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal year",
+                        "name": "yearTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimal user rating",
+                        "name": "minRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximal user rating",
+                        "name": "maxRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only films with or without user votes",
+                        "name": "hasRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimal runtime in minutes",
+                        "name": "minRuntime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximal runtime in minutes",
+                        "name": "maxRuntime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or after the date, YYYY-MM-DD",
+                        "name": "releasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Released on or before the date, YYYY-MM-DD",
+                        "name": "releasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Production country, ISO 3166-1 alpha-2 code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Original language, ISO 639 code",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only films which are or aren't in the watchlist of the user",
+                        "name": "inWatchlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated genre names",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "or",
+                            "and"
+                        ],
+                        "type": "string",
+                        "description": "Film must have all (and) or any (or) of the genres",
+                        "name": "genreMode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort list by desc or asc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "rating",
+                            "title",
+                            "year",
+                            "id",
+                            "runtime",
+                            "releaseDate"
+                        ],
+                        "type": "string",
+                        "description": "sort by params",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of films",
+                        "name": "withTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the list the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the list the client has",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/FilmPage"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Tag of the list"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Last change of the films or actors, not sent with inWatchlist"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create Film. Admins set editorialRating, rating and votes are made up from user votes. You must have admin role.\nThe created film is returned with its ID, and Location tells where to get it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Create Film",
+                "operationId": "create-film",
+                "parameters": [
+                    {
+                        "description": "Film",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the created film"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Path of the created film, like /film/1"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get Film by ID with its genres and cast. Cast is ordered by billing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Get Film by ID",
+                "operationId": "get-film-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of the film the client has",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified of the film the client has",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the film, to be sent as If-Match"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Last change of the film or its cast"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update Film by ID. Admins set editorialRating, rating and votes are made up from user votes. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Update Film by ID",
+                "operationId": "update-film-by-id",
+                "parameters": [
+                    {
+                        "description": "Film",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add actors to film by id. You must have admin role.\nCredit type is one of lead, supporting, cameo, voice. Billing is the position in the credits.\nIf an actor doesn't exist or is in the cast already, no actor is added and the errors tell why for each.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Add actors to film by id",
+                "operationId": "add-actor-to-film-by-id",
+                "parameters": [
+                    {
+                        "description": "Array of actor's id",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Data"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CastErrorResponse"
+                        }
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Move Film to the trash. Its cast, genres and votes are kept until it is purged; it is taken off watchlists and favorites. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Delete Film by ID",
+                "operationId": "delete-film-by-id",
+                "parameters": [
+                    {
+                        "description": "Film",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change some fields of Film by ID with a JSON merge patch (RFC 7396) sent as application/merge-patch+json. Fields left out are kept, null clears a field, nullable fields like information are replaced as a whole, valid unless Valid is given. Members the film has no field for, and read-only ones like id, rating or cast, are refused. The patched film must be valid. You must have admin role.\nWith the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Patch Film by ID",
+                "operationId": "patch-film-by-id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields of the film to change",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETags of the versions which may be changed, weak ones never match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Film"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version and tag of the patched film"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "412": {
+                        "description": "Precondition Failed"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
+                    "428": {
+                        "description": "Precondition Required"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/actors": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a page of the cast of film by ID with the parts the actors play. Actors go by billing unless another order is asked for.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Get actors of film",
+                "operationId": "get-film-actors",
+                "parameters": [
+                    {
+                        "enum": [
+                            "billing",
+                            "name",
+                            "birthday"
+                        ],
+                        "type": "string",
+                        "description": "Sort by params",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort list by desc or asc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of actors",
+                        "name": "withTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CastPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replace the whole cast of film by ID. You must have admin role.\nIf an actor doesn't exist or is credited twice, the cast is left as it was and the errors tell why for each.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Replace actors of film",
+                "operationId": "replace-film-actors",
+                "parameters": [
+                    {
+                        "description": "Cast",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Data"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/CastErrorResponse"
+                        }
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/actors/{actorId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove actor from the cast of film by ID. You must have admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Remove actor from film",
+                "operationId": "remove-actor-from-film",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/CastErrorResponse"
+                        }
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/genres": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add genres to film by id. You must have admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Add genres to film by id",
+                "operationId": "add-genre-to-film-by-id",
+                "parameters": [
+                    {
+                        "description": "Array of genre's id",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/GenresData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/poster": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload poster of film by ID as JPEG, PNG, GIF or WebP. Thumbnails are made from it. The version of the film grows, so ETags read before the upload no longer match. You must have admin role.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Upload Poster",
+                "operationId": "upload-film-poster",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Image"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large"
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/rating": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Rate film by ID from 0 to 10. A user has one vote per film, rating again replaces it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Rate film",
+                "operationId": "rate-film",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vote",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Vote"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/FilmRating"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Take Film out of the trash with its cast. You must have admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Restore Film",
+                "operationId": "restore-film",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the revisions of Film by ID, the latest first. Every update saves one, the first is how the film was before its first update. Each revision tells the fields which changed since the revision before it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Get revisions of Film",
+                "operationId": "get-film-revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/FilmRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/revisions/{rev}/revert": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Bring Film back to the revision. The film as it becomes is saved as a new revision. You must have admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "films"
+                ],
+                "summary": "Revert Film to revision",
+                "operationId": "revert-film",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/film/{id}/similar": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the films most similar to the film by shared cast, by the votes of users who liked both and by release year, best first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get similar films",
+                "operationId": "get-similar-films",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of films, 20 by default, up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Recommendation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/genre": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get list of genres",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Get List of genres",
+                "operationId": "get-list-genre",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Genre"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create genre. You must have admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Create genre",
+                "operationId": "create-genre",
+                "parameters": [
+                    {
+                        "description": "Genre",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/genre/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get genre by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Get genre by ID",
+                "operationId": "get-genre-by-id",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update genre by ID. You must have admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Update genre by ID",
+                "operationId": "update-genre-by-id",
+                "parameters": [
+                    {
+                        "description": "Genre",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Genre"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete genre by ID. Films of the genre are kept. You must have admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Delete genre by ID",
+                "operationId": "delete-genre-by-id",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Import films, actors or credits from a CSV or NDJSON file sent as the body. You must have admin role.\nCSV columns of films are title, year, information, editorialRating, runtimeMinutes, releaseDate, countries and originalLanguage; of actors name, surname, patronymic, birthday, sex and information; of credits filmId, actorId, character, billing and type. NDJSON rows are the same as the bodies of the create requests.\nExported films and actors can be imported back, their id, rating, votes and credits are skipped.\nAll rows are saved in one transaction, if a row is wrong nothing is saved. In a dry run the rows are checked without saving them. The report tells the errors of each row and the IDs of the created films or actors.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Import catalog data",
+                "operationId": "import-data",
+                "parameters": [
+                    {
+                        "enum": [
+                            "films",
+                            "actors",
+                            "credits"
+                        ],
+                        "type": "string",
+                        "description": "Kind of data",
+                        "name": "kind",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Format of the file, taken from Content-Type if not set",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only check the rows",
+                        "name": "dryRun",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ImportReport"
+                        }
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/me/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the films the user watched, the latest viewings first. Every viewing of a film but the first one is a rewatch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Get watched history",
+                "operationId": "get-history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include total count of viewings",
+                        "name": "withTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ViewingPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Log that the user watched a film on a day, today if watchedOn isn't set. A film can be logged again when it is rewatched. The film is taken off the watchlist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "Log viewing",
+                "operationId": "add-viewing",
+                "parameters": [
+                    {
+                        "description": "Viewing",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Viewing"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Viewing"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/me/recommendations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get films for the user to watch next, best first. Films are picked by the actors they share with the films the user liked, by the votes of users who liked the same films and by release year. Users who haven't liked a film yet get the films rated best. Films the user rated are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get recommendations",
+                "operationId": "get-recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max number of films, 20 by default, up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Recommendation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/me/{list}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get films in the watchlist or favorites of the user with the time they were added at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Get personal list",
+                "operationId": "get-list",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites"
+                        ],
+                        "type": "string",
+                        "description": "List",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "addedAt",
+                            "title",
+                            "year",
+                            "rating"
+                        ],
+                        "type": "string",
+                        "description": "Sort by params",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "desc",
+                            "asc"
+                        ],
+                        "type": "string",
+                        "description": "Sort list by desc or asc, the films added last go first by default",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ListEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add film to the watchlist or favorites of the user. Adding it again keeps the time it was first added at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Add film to personal list",
+                "operationId": "add-to-list",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites"
+                        ],
+                        "type": "string",
+                        "description": "List",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Film",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ListData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/me/{list}/{filmId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove film from the watchlist or favorites of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "lists"
+                ],
+                "summary": "Remove film from personal list",
+                "operationId": "remove-from-list",
+                "parameters": [
+                    {
+                        "enum": [
+                            "watchlist",
+                            "favorites"
+                        ],
+                        "type": "string",
+                        "description": "List",
+                        "name": "list",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Film ID",
+                        "name": "filmId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "404": {
+                        "description": "Not Found"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Search films by title and information and actors by name and information.\nRussian word forms are matched, so \"брата\" finds \"Брат\". Hits are ordered by rank.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Full-text search",
+                "operationId": "search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of hits",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SearchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/sign-in": {
+            "post": {
+                "description": "login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sign"
+                ],
+                "summary": "SignIn",
+                "operationId": "login",
+                "parameters": [
+                    {
+                        "description": "credentials",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/signInInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/sign-up": {
+            "post": {
+                "description": "create account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sign"
+                ],
+                "summary": "SignUp",
+                "operationId": "create-account",
+                "parameters": [
+                    {
+                        "description": "account info",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/signUpInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "integer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get deleted films and actors, the last deleted first. You must have admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get trash",
+                "operationId": "get-trash",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TrashItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "500": {
+                        "description": "Internal Server Error"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete for good the films and actors which were deleted before the retention window, with their credits, votes and images. You must have admin role.\nA longer window can be given, a shorter one is refused.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge trash",
+                "operationId": "purge-trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purge items deleted longer ago than this, like 720h",
+                        "name": "olderThan",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PurgeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request"
+                    },
+                    "default": {
+                        "description": ""
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Actor": {
+            "type": "object",
+            "properties": {
+                "birthday": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patronymic": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "photo": {
+                    "$ref": "#/definitions/Image"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "ActorCredit": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "type": "integer"
+                },
+                "character": {
+                    "type": "string"
+                },
+                "filmId": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "ActorFilm": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/Actor"
+                },
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FilmCredit"
+                    }
+                }
+            }
+        },
+        "ActorPage": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Actor"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ActorPath": {
+            "type": "object",
+            "properties": {
+                "degrees": {
+                    "type": "integer"
+                },
+                "start": {
+                    "$ref": "#/definitions/PathActor"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PathStep"
+                    }
+                }
+            }
+        },
+        "ActorRevision": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "$ref": "#/definitions/Actor"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/Diff"
+                },
+                "rev": {
+                    "type": "integer"
+                }
+            }
+        },
+        "AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/Diff"
+                },
+                "entityId": {
+                    "type": "integer"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "AuditPage": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditEntry"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "CastErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CreditError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "CastMember": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "type": "integer"
+                },
+                "birthday": {
+                    "type": "string"
+                },
+                "character": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patronymic": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "photo": {
+                    "$ref": "#/definitions/Image"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "CastPage": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CastMember"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Credit": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "billing": {
+                    "type": "integer"
+                },
+                "character": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreditError": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "Data": {
+            "type": "object",
+            "properties": {
+                "actors": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Credit"
+                    }
+                }
+            }
+        },
+        "Diff": {
+            "type": "object",
+            "additionalProperties": {
+                "$ref": "#/definitions/FieldChange"
+            }
+        },
+        "ExportActor": {
+            "type": "object",
+            "properties": {
+                "birthday": {
+                    "type": "string"
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ActorCredit"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patronymic": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "photo": {
+                    "$ref": "#/definitions/Image"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExportFilm": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CastMember"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Credit"
+                    }
+                },
+                "editorialRating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "originalLanguage": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "poster": {
+                    "$ref": "#/definitions/Image"
+                },
+                "rating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "releaseDate": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "runtimeMinutes": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userRating": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                }
+            }
+        },
+        "Film": {
+            "type": "object",
+            "properties": {
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CastMember"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "editorialRating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "originalLanguage": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "poster": {
+                    "$ref": "#/definitions/Image"
+                },
+                "rating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "releaseDate": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "runtimeMinutes": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "userRating": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FilmCredit": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "type": "integer"
+                },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CastMember"
+                    }
+                },
+                "character": {
+                    "type": "string"
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "editorialRating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Genre"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "information": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "originalLanguage": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "poster": {
+                    "$ref": "#/definitions/Image"
+                },
+                "rating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "releaseDate": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "runtimeMinutes": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userRating": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FilmCreditPage": {
+            "type": "object",
+            "properties": {
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FilmCredit"
+                    }
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FilmPage": {
+            "type": "object",
+            "properties": {
+                "films": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Film"
+                    }
+                },
+                "fuzzy": {
+                    "type": "boolean"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FilmRating": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "userRating": {
+                    "type": "integer"
+                },
+                "votes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "FilmRevision": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "diff": {
+                    "$ref": "#/definitions/Diff"
+                },
+                "film": {
+                    "$ref": "#/definitions/Film"
+                },
+                "rev": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Genre": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "GenresData": {
+            "type": "object",
+            "properties": {
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "Image": {
+            "type": "object",
+            "properties": {
+                "thumbnails": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "ImportReport": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ImportRow"
                     }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "type": "integer"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request"
-                    },
-                    "500": {
-                        "description": "Internal Server Error"
-                    },
-                    "default": {
-                        "description": ""
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ImportRow": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "Actor": {
+        },
+        "ListData": {
             "type": "object",
             "properties": {
-                "birthday": {
+                "filmId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ListEntry": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
                     "type": "string"
                 },
+                "cast": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CastMember"
+                    }
+                },
+                "countries": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "editorialRating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Genre"
+                    }
+                },
                 "id": {
                     "type": "integer"
                 },
                 "information": {
                     "$ref": "#/definitions/sql.NullString"
                 },
-                "name": {
+                "originalLanguage": {
+                    "$ref": "#/definitions/sql.NullString"
+                },
+                "poster": {
+                    "$ref": "#/definitions/Image"
+                },
+                "rating": {
+                    "$ref": "#/definitions/sql.NullFloat64"
+                },
+                "releaseDate": {
+                    "$ref": "#/definitions/sql.NullTime"
+                },
+                "runtimeMinutes": {
+                    "$ref": "#/definitions/sql.NullInt64"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "title": {
                     "type": "string"
                 },
-                "patronymic": {
-                    "$ref": "#/definitions/sql.NullString"
+                "userRating": {
+                    "type": "integer"
                 },
-                "sex": {
+                "votes": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PathActor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
                 "surname": {
@@ -627,56 +3601,166 @@ const docTemplate = `{
                 }
             }
         },
-        "ActorFilm": {
+        "PathFilm": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "PathStep": {
             "type": "object",
             "properties": {
                 "actor": {
-                    "$ref": "#/definitions/Actor"
+                    "$ref": "#/definitions/PathActor"
                 },
-                "films": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/Film"
-                    }
+                "film": {
+                    "$ref": "#/definitions/PathFilm"
                 }
             }
         },
-        "Data": {
+        "PurgeReport": {
             "type": "object",
             "properties": {
                 "actors": {
+                    "type": "integer"
+                },
+                "deletedBefore": {
+                    "type": "string"
+                },
+                "films": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Recommendation": {
+            "type": "object",
+            "properties": {
+                "explanation": {
+                    "type": "string"
+                },
+                "filmId": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "SearchHit": {
+            "type": "object",
+            "properties": {
+                "headline": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "SearchResult": {
+            "type": "object",
+            "properties": {
+                "hits": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/SearchHit"
                     }
                 }
             }
         },
-        "Film": {
+        "TokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "TrashItem": {
             "type": "object",
             "properties": {
+                "deletedAt": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "information": {
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "Viewing": {
+            "type": "object",
+            "properties": {
+                "filmId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
                     "$ref": "#/definitions/sql.NullString"
                 },
-                "rating": {
-                    "$ref": "#/definitions/sql.NullFloat64"
+                "rewatch": {
+                    "type": "boolean"
                 },
                 "title": {
                     "type": "string"
                 },
-                "year": {
-                    "type": "integer"
+                "watchedOn": {
+                    "type": "string"
                 }
             }
         },
-        "TokenResponse": {
+        "ViewingPage": {
             "type": "object",
             "properties": {
-                "token": {
+                "nextCursor": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "viewings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Viewing"
+                    }
+                }
+            }
+        },
+        "Vote": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "type": "integer"
                 }
             }
         },
@@ -726,6 +3810,18 @@ const docTemplate = `{
                 }
             }
         },
+        "sql.NullInt64": {
+            "type": "object",
+            "properties": {
+                "int64": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "Valid is true if Int64 is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
         "sql.NullString": {
             "type": "object",
             "properties": {
@@ -737,6 +3833,18 @@ const docTemplate = `{
                     "type": "boolean"
                 }
             }
+        },
+        "sql.NullTime": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
         }
     },
     "securityDefinitions": {
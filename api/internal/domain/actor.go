@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+// Actor is an actor of the catalog. Version grows with every update of the
+// actor, UpdatedAt is when they were changed last.
 type Actor struct {
 	ID          int64          `json:"id"`
 	Name        string         `json:"name"`
@@ -13,11 +15,15 @@ type Actor struct {
 	Birthday    time.Time      `json:"birthday"`
 	Sex         string         `json:"sex"`
 	Information sql.NullString `json:"information"`
+	PhotoKey    sql.NullString `json:"-" db:"photo_key"`
+	Version     int64          `json:"-"`
+	UpdatedAt   time.Time      `json:"-" db:"updated_at"`
+	Photo       *Image         `json:"photo,omitempty" db:"-"`
 } // @name Actor
 
 type ActorFilm struct {
-	Actor Actor  `db:"actors"`
-	Films []Film `db:"films"`
+	Actor Actor        `db:"actors"`
+	Films []FilmCredit `db:"films"`
 } // @name ActorFilm
 
 func (a *Actor) IsValid() bool {
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	AuditCreate      = "create"
+	AuditUpdate      = "update"
+	AuditDelete      = "delete"
+	AuditRestore     = "restore"
+	AuditRevert      = "revert"
+	AuditCastAdd     = "cast_add"
+	AuditCastReplace = "cast_replace"
+	AuditCastRemove  = "cast_remove"
+	AuditGenreAdd    = "genre_add"
+	AuditPoster      = "poster"
+	AuditPhoto       = "photo"
+	AuditPurge       = "purge"
+)
+
+// Kinds of entities recorded in the audit log.
+const (
+	AuditFilm  = "film"
+	AuditActor = "actor"
+)
+
+// Editor is the user who changes the catalog and the request they change it
+// by.
+type Editor struct {
+	UserID    int64
+	RequestID string
+}
+
+// FieldChange is the value of a field before and after a change. The side
+// on which the field doesn't exist, as before a film is created, is left
+// out.
+type FieldChange struct {
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+} // @name FieldChange
+
+// Diff holds the changes of the fields of an entity by their JSON names.
+type Diff map[string]FieldChange // @name Diff
+
+func (d Diff) Value() (driver.Value, error) {
+	if d == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(d)
+}
+
+func (d *Diff) Scan(src any) error {
+	data, ok := src.([]byte)
+	if !ok {
+		return errors.New("diff must be read from jsonb")
+	}
+	return json.Unmarshal(data, d)
+}
+
+// NewDiff compares the JSON forms of two states of an entity and returns the
+// fields which differ. Nil means the entity didn't exist.
+func NewDiff(before, after any) (Diff, error) {
+	b, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	d := Diff{}
+	for name, value := range b {
+		if !bytes.Equal(value, a[name]) {
+			d[name] = FieldChange{Before: value, After: a[name]}
+		}
+	}
+	for name, value := range a {
+		if _, ok := b[name]; !ok {
+			d[name] = FieldChange{After: value}
+		}
+	}
+
+	return d, nil
+}
+
+func jsonFields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(data, &fields)
+
+	return fields, err
+}
+
+// AuditEntry is a change of a film or an actor made by an editor.
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"userId" db:"user_id"`
+	RequestID  string    `json:"requestId,omitempty" db:"request_id"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entityType" db:"entity_type"`
+	EntityID   int64     `json:"entityId" db:"entity_id"`
+	Diff       Diff      `json:"diff"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+} // @name AuditEntry
+
+// AuditQuery holds the criteria the audit log is searched by. Zero values
+// mean the criterion isn't applied. From is inclusive, To is exclusive.
+type AuditQuery struct {
+	EntityType string
+	EntityID   int64
+	UserID     int64
+	From       time.Time
+	To         time.Time
+	Page       PageRequest
+}
+
+// AuditPage is a page of the audit log, the latest entries first.
+type AuditPage struct {
+	Entries    []AuditEntry `json:"entries"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Total      *int64       `json:"total,omitempty"`
+} // @name AuditPage
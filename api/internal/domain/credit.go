@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	CreditLead       = "lead"
+	CreditSupporting = "supporting"
+	CreditCameo      = "cameo"
+	CreditVoice      = "voice"
+)
+
+// Part is what an actor plays in a film. Billing is the position in the
+// credits starting from 1, 0 means it's not known.
+type Part struct {
+	Character string `json:"character,omitempty" db:"character_name"`
+	Billing   int    `json:"billing,omitempty" db:"billing_order"`
+	Type      string `json:"type" db:"credit_type"`
+} // @name Part
+
+func (p *Part) IsValid() bool {
+	switch p.Type {
+	case CreditLead, CreditSupporting, CreditCameo, CreditVoice:
+		return p.Billing >= 0
+	default:
+		return false
+	}
+}
+
+// Credit adds an actor to the cast of a film.
+type Credit struct {
+	ActorID int64 `json:"actorId"`
+	Part
+} // @name Credit
+
+// CastMember is an actor in the cast of a film.
+type CastMember struct {
+	Actor
+	Part
+} // @name CastMember
+
+// FilmCredit is a film in the filmography of an actor.
+type FilmCredit struct {
+	Film
+	Part
+} // @name FilmCredit
+
+// Reasons why the credit of an actor can't be saved or removed.
+const (
+	CreditNotValid       = "not_valid"
+	CreditUnknownActor   = "unknown_actor"
+	CreditAlreadyPresent = "already_present"
+	CreditNotInCast      = "not_in_cast"
+)
+
+// CreditError tells why the credit of an actor can't be saved or removed.
+type CreditError struct {
+	ActorID int64  `json:"actorId"`
+	Reason  string `json:"reason"`
+} // @name CreditError
+
+// CreditErrors is returned when some credits of a cast change are wrong.
+// Nothing is changed then.
+type CreditErrors []CreditError
+
+func (e CreditErrors) Error() string {
+	reasons := make([]string, 0, len(e))
+	for _, err := range e {
+		reasons = append(reasons, fmt.Sprintf("actor with id = %d: %s", err.ActorID, err.Reason))
+	}
+	return "credits can't be saved: " + strings.Join(reasons, ", ")
+}
@@ -0,0 +1,27 @@
+package domain
+
+// Formats of exported files.
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportJSON   = "json"
+)
+
+// ExportFilm is an exported film with its cast, when credits are asked for.
+type ExportFilm struct {
+	Film
+	Credits []Credit `json:"credits,omitempty"`
+} // @name ExportFilm
+
+// ExportActor is an exported actor with the parts the actor plays, when
+// credits are asked for.
+type ExportActor struct {
+	Actor
+	Credits []ActorCredit `json:"credits,omitempty"`
+} // @name ExportActor
+
+// ActorCredit is a part an actor plays in a film.
+type ActorCredit struct {
+	FilmID int64 `json:"filmId"`
+	Part
+} // @name ActorCredit
@@ -1,15 +1,101 @@
 package domain
 
-import "database/sql"
+import (
+	"database/sql"
+	"github.com/lib/pq"
+	"regexp"
+	"time"
+)
 
+// Film is a film of the catalog. Rating is the average of the votes of
+// users, EditorialRating is the one admins set. Version grows with every
+// update of the film, UpdatedAt is when it or its cast was changed last.
 type Film struct {
-	ID          int64           `json:"id"`
-	Title       string          `json:"title"`
-	Year        int             `json:"year"`
-	Information sql.NullString  `json:"information"`
-	Rating      sql.NullFloat64 `json:"rating"`
+	ID               int64           `json:"id"`
+	Title            string          `json:"title"`
+	Year             int             `json:"year"`
+	Information      sql.NullString  `json:"information"`
+	Rating           sql.NullFloat64 `json:"rating"`
+	Votes            int64           `json:"votes"`
+	EditorialRating  sql.NullFloat64 `json:"editorialRating" db:"editorial_rating"`
+	UserRating       *int            `json:"userRating,omitempty" db:"user_rating"`
+	RuntimeMinutes   sql.NullInt64   `json:"runtimeMinutes" db:"runtime_minutes"`
+	ReleaseDate      sql.NullTime    `json:"releaseDate" db:"release_date"`
+	Countries        pq.StringArray  `json:"countries" swaggertype:"array,string"`
+	OriginalLanguage sql.NullString  `json:"originalLanguage" db:"original_language"`
+	PosterKey        sql.NullString  `json:"-" db:"poster_key"`
+	Version          int64           `json:"-"`
+	UpdatedAt        time.Time       `json:"-" db:"updated_at"`
+	Poster           *Image          `json:"poster,omitempty" db:"-"`
+	Similarity       *float64        `json:"similarity,omitempty"`
+	Genres           []Genre         `json:"genres,omitempty" db:"-"`
+	Cast             []CastMember    `json:"cast,omitempty" db:"-"`
 } // @name Film
 
+var (
+	// countryCode is an ISO 3166-1 alpha-2 code like RU or US.
+	countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+	// languageCode is an ISO 639-1 or ISO 639-2 code like ru or eng.
+	languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+)
+
 func (f *Film) IsValid() bool {
-	return f.ID >= 0 && f.Year > 1000 && f.Title != ""
+	if f.ID < 0 || f.Year <= 1000 || f.Title == "" {
+		return false
+	}
+	if f.EditorialRating.Valid && (f.EditorialRating.Float64 < 0 || f.EditorialRating.Float64 > 10) {
+		return false
+	}
+	if f.RuntimeMinutes.Valid && f.RuntimeMinutes.Int64 <= 0 {
+		return false
+	}
+	if f.ReleaseDate.Valid && f.ReleaseDate.Time.Year() <= 1000 {
+		return false
+	}
+	for _, country := range f.Countries {
+		if !countryCode.MatchString(country) {
+			return false
+		}
+	}
+	if f.OriginalLanguage.Valid && !languageCode.MatchString(f.OriginalLanguage.String) {
+		return false
+	}
+
+	return true
+}
+
+// FilmQuery holds the criteria films are listed by. Zero values mean the
+// criterion isn't applied.
+type FilmQuery struct {
+	Title     string
+	Actor     string
+	YearFrom  int
+	YearTo    int
+	MinRating *float64
+	MaxRating *float64
+	HasRating *bool
+	// MinRuntime and MaxRuntime are in minutes.
+	MinRuntime   int
+	MaxRuntime   int
+	ReleasedFrom time.Time
+	ReleasedTo   time.Time
+	Country      string
+	Language     string
+	// Genres are matched by name. A film has to belong to all of them when
+	// AllGenres is set and to any of them otherwise.
+	Genres    []string
+	AllGenres bool
+	OrderBy   string
+	Desc      bool
+	Page      PageRequest
+	// UserID is the user films are listed for, their own ratings are
+	// returned with the films. InWatchlist keeps the films which are or
+	// aren't in their watchlist.
+	UserID      int64
+	InWatchlist *bool
+
+	// Fuzzy makes title and actor match by trigram word similarity instead
+	// of by substring.
+	Fuzzy          bool
+	FuzzyThreshold float64
 }
@@ -0,0 +1,10 @@
+package domain
+
+type Genre struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+} // @name Genre
+
+func (g *Genre) IsValid() bool {
+	return g.ID >= 0 && g.Name != ""
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+var (
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrImageTypeUnsupported = errors.New("image type is not supported, use JPEG, PNG, GIF or WebP")
+)
+
+// ThumbnailWidths are the widths, in pixels, thumbnails of uploaded images
+// are made in. Images narrower than a thumbnail aren't scaled up.
+var ThumbnailWidths = map[string]int{
+	"small":  92,
+	"medium": 185,
+	"large":  500,
+}
+
+// Image is an uploaded poster or photo. Thumbnails maps the names of
+// ThumbnailWidths to their URLs.
+type Image struct {
+	URL        string            `json:"url"`
+	Thumbnails map[string]string `json:"thumbnails"`
+} // @name Image
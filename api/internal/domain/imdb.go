@@ -0,0 +1,46 @@
+package domain
+
+// Files of the IMDb non-commercial datasets. Titles and names must be
+// imported before the principals linking them.
+const (
+	IMDbTitles     = "title.basics.tsv.gz"
+	IMDbNames      = "name.basics.tsv.gz"
+	IMDbPrincipals = "title.principals.tsv.gz"
+)
+
+// IMDbFilm is a film read from the IMDb titles. RuntimeMinutes is 0 when
+// it isn't known.
+type IMDbFilm struct {
+	IMDbID         string
+	Title          string
+	Year           int
+	RuntimeMinutes int
+}
+
+// IMDbActor is an actor read from the IMDb names. IMDb only knows the year
+// an actor was born in.
+type IMDbActor struct {
+	IMDbID    string
+	Name      string
+	Surname   string
+	BirthYear int
+	Sex       string
+}
+
+// IMDbCredit is a part read from the IMDb principals.
+type IMDbCredit struct {
+	FilmIMDbID  string
+	ActorIMDbID string
+	Part
+}
+
+// IMDbReport tells how an IMDb file was imported. Lines up to Resumed were
+// imported before and are skipped. Saved doesn't count the rows which were
+// already in the database.
+type IMDbReport struct {
+	File    string
+	Resumed int64
+	Read    int64
+	Skipped int64
+	Saved   int64
+}
@@ -0,0 +1,40 @@
+package domain
+
+// Kinds of catalog data that can be imported.
+const (
+	ImportFilms   = "films"
+	ImportActors  = "actors"
+	ImportCredits = "credits"
+)
+
+// Formats of imported files.
+const (
+	ImportCSV    = "csv"
+	ImportNDJSON = "ndjson"
+)
+
+// ImportCredit links an actor to a film in an import of credits.
+type ImportCredit struct {
+	FilmID int64 `json:"filmId"`
+	Credit
+} // @name ImportCredit
+
+// ImportRow tells what became of a row of an imported file. Line counts from
+// 1 and includes the header of a CSV file. ID is the ID of the created film
+// or actor, in a dry run it is the one the row would get.
+type ImportRow struct {
+	Line   int      `json:"line"`
+	ID     int64    `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+} // @name ImportRow
+
+// ImportReport tells what became of each row of an imported file. Rows are
+// only saved when all of them are right and it's not a dry run.
+type ImportReport struct {
+	Kind      string      `json:"kind"`
+	DryRun    bool        `json:"dryRun"`
+	Committed bool        `json:"committed"`
+	Total     int         `json:"total"`
+	Failed    int         `json:"failed"`
+	Rows      []ImportRow `json:"rows"`
+} // @name ImportReport
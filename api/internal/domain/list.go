@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Personal lists of films a user keeps.
+const (
+	ListWatchlist = "watchlist"
+	ListFavorites = "favorites"
+)
+
+func IsList(name string) bool {
+	return name == ListWatchlist || name == ListFavorites
+}
+
+// ListEntry is a film in a personal list with the time it was added at.
+type ListEntry struct {
+	Film
+	AddedAt time.Time `json:"addedAt" db:"added_at"`
+} // @name ListEntry
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PageRequest describes one page of a keyset-paginated list.
+type PageRequest struct {
+	Limit     int
+	Cursor    string
+	WithTotal bool
+}
+
+// Cursor points at the last row of a page. It remembers the sort it was
+// issued for, so it can't be replayed against a different ordering.
+type Cursor struct {
+	OrderBy string `json:"o,omitempty"`
+	Desc    bool   `json:"d,omitempty"`
+	Fuzzy   bool   `json:"f,omitempty"`
+	Value   string `json:"v,omitempty"`
+	ID      int64  `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("cursor is not valid")
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, errors.New("cursor is not valid")
+	}
+
+	return c, nil
+}
+
+// FilmPage is a page of films. Fuzzy is set when nothing matched the title
+// or actor exactly and the films were found by similarity. UpdatedAt is the
+// last change of the catalog the page was read from, zero for pages which
+// depend on a watchlist.
+type FilmPage struct {
+	Films      []Film    `json:"films"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Total      *int64    `json:"total,omitempty"`
+	Fuzzy      bool      `json:"fuzzy,omitempty"`
+	UpdatedAt  time.Time `json:"-"`
+} // @name FilmPage
+
+// ActorPage is a page of actors. UpdatedAt is the last change of the actors
+// the page was read from.
+type ActorPage struct {
+	Actors     []Actor   `json:"actors"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Total      *int64    `json:"total,omitempty"`
+	UpdatedAt  time.Time `json:"-"`
+} // @name ActorPage
+
+// CreditQuery asks for a page of the films of an actor or of the cast of a
+// film.
+type CreditQuery struct {
+	OrderBy string
+	Desc    bool
+	Page    PageRequest
+}
+
+// FilmCreditPage is a page of the filmography of an actor.
+type FilmCreditPage struct {
+	Films      []FilmCredit `json:"films"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Total      *int64       `json:"total,omitempty"`
+} // @name FilmCreditPage
+
+// CastPage is a page of the cast of a film.
+type CastPage struct {
+	Actors     []CastMember `json:"actors"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Total      *int64       `json:"total,omitempty"`
+} // @name CastPage
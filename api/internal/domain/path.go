@@ -0,0 +1,32 @@
+package domain
+
+import "errors"
+
+// ErrNoActorPath is returned when two actors aren't linked by films within
+// the max depth.
+var ErrNoActorPath = errors.New("actors aren't linked within max depth")
+
+// ActorPath is the shortest chain of films linking two actors. Every step is
+// a film the previous actor plays in together with the actor of the step.
+type ActorPath struct {
+	Degrees int        `json:"degrees"`
+	Start   PathActor  `json:"start"`
+	Steps   []PathStep `json:"steps"`
+} // @name ActorPath
+
+type PathStep struct {
+	Film  PathFilm  `json:"film"`
+	Actor PathActor `json:"actor"`
+} // @name PathStep
+
+type PathActor struct {
+	ID      int64  `json:"id"`
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+} // @name PathActor
+
+type PathFilm struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+} // @name PathFilm
@@ -0,0 +1,20 @@
+package domain
+
+import "database/sql"
+
+// Vote is the rating a user gives to a film, from 0 to 10.
+type Vote struct {
+	Rating int `json:"rating"`
+} // @name Vote
+
+func (v *Vote) IsValid() bool {
+	return v.Rating >= 0 && v.Rating <= 10
+}
+
+// FilmRating is the rating of a film made up from the votes of users.
+// UserRating is the vote of the user who asked for it.
+type FilmRating struct {
+	Rating     sql.NullFloat64 `json:"rating"`
+	Votes      int64           `json:"votes"`
+	UserRating *int            `json:"userRating,omitempty" db:"user_rating"`
+} // @name FilmRating
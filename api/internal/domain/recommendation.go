@@ -0,0 +1,31 @@
+package domain
+
+// Recommendation is a film recommended to a user or similar to another film.
+// Explanation tells why it was picked.
+type Recommendation struct {
+	FilmID      int64   `json:"filmId"`
+	Title       string  `json:"title"`
+	Year        int     `json:"year"`
+	Score       float64 `json:"score"`
+	Explanation string  `json:"explanation"`
+} // @name Recommendation
+
+// FilmSummary is the part of a film recommendations are made from.
+type FilmSummary struct {
+	ID    int64
+	Title string
+	Year  int
+}
+
+// CastLink tells that an actor plays in a film.
+type CastLink struct {
+	FilmID  int64 `db:"film_id"`
+	ActorID int64 `db:"actor_id"`
+}
+
+// UserVote is the rating a user gave to a film.
+type UserVote struct {
+	UserID int64 `db:"user_id"`
+	FilmID int64 `db:"film_id"`
+	Rating int
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// FilmRevision is a version of the editable fields of a film. Every update
+// saves one, the first is how the film was before its first update. Diff
+// tells the fields which changed since the revision before it.
+type FilmRevision struct {
+	Rev       int64     `json:"rev"`
+	CreatedAt time.Time `json:"createdAt"`
+	Film      Film      `json:"film"`
+	Diff      Diff      `json:"diff,omitempty"`
+} // @name FilmRevision
+
+// ActorRevision is a version of the editable fields of an actor, saved the
+// same way as FilmRevision.
+type ActorRevision struct {
+	Rev       int64     `json:"rev"`
+	CreatedAt time.Time `json:"createdAt"`
+	Actor     Actor     `json:"actor"`
+	Diff      Diff      `json:"diff,omitempty"`
+} // @name ActorRevision
@@ -0,0 +1,20 @@
+package domain
+
+const (
+	SearchHitFilm  = "film"
+	SearchHitActor = "actor"
+)
+
+// SearchHit is a film or an actor found by full-text search. Headline holds
+// the matched text with the found words wrapped in <b></b>.
+type SearchHit struct {
+	Type     string  `json:"type"`
+	ID       int64   `json:"id"`
+	Title    string  `json:"title"`
+	Headline string  `json:"headline"`
+	Rank     float64 `json:"rank"`
+} // @name SearchHit
+
+type SearchResult struct {
+	Hits []SearchHit `json:"hits"`
+} // @name SearchResult
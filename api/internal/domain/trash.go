@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	TrashFilm  = "film"
+	TrashActor = "actor"
+)
+
+// TrashItem is a deleted film or actor. It can be restored until it is
+// purged.
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deletedAt" db:"deleted_at"`
+} // @name TrashItem
+
+// PurgeReport tells how many films and actors were deleted for good.
+type PurgeReport struct {
+	DeletedBefore time.Time `json:"deletedBefore"`
+	Films         int64     `json:"films"`
+	Actors        int64     `json:"actors"`
+} // @name PurgeReport
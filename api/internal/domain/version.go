@@ -0,0 +1,31 @@
+package domain
+
+import "errors"
+
+var (
+	ErrVersionMismatch = errors.New("version doesn't match, it was changed since it was read")
+	ErrVersionRequired = errors.New("version must be given to change it")
+)
+
+// AnyVersion asks to change a film or an actor whatever its version is, yet
+// tells that the client knows about versions.
+const AnyVersion int64 = -1
+
+// Versions are the versions a film or an actor may be of to be changed, as
+// listed by If-Match. Nil Versions aren't given and match any version, an
+// empty list matches none.
+type Versions []int64
+
+// Match tells whether a film or an actor of version may be changed.
+func (v Versions) Match(version int64) bool {
+	if v == nil {
+		return true
+	}
+	for _, listed := range v {
+		if listed == AnyVersion || listed == version {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Viewing is a film a user watched on a day. Rewatch is set for every
+// viewing of a film but the first one.
+type Viewing struct {
+	ID        int64          `json:"id"`
+	FilmID    int64          `json:"filmId" db:"film_id"`
+	Title     string         `json:"title"`
+	WatchedOn time.Time      `json:"watchedOn" db:"watched_on"`
+	Note      sql.NullString `json:"note"`
+	Rewatch   bool           `json:"rewatch"`
+} // @name Viewing
+
+func (v *Viewing) IsValid() bool {
+	if v.FilmID <= 0 || v.WatchedOn.Year() <= 1000 {
+		return false
+	}
+	// A day ahead is allowed for the users whose day has already begun.
+	if v.WatchedOn.After(time.Now().AddDate(0, 0, 1)) {
+		return false
+	}
+
+	return !v.Note.Valid || len([]rune(v.Note.String)) <= 1000
+}
+
+type ViewingPage struct {
+	Viewings   []Viewing `json:"viewings"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Total      *int64    `json:"total,omitempty"`
+} // @name ViewingPage
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"kinoteka/internal/service"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type ActorHandler struct {
@@ -23,8 +25,16 @@ type ActorHandler struct {
 // @Accept  json
 // @Produce  json
 // @Param withFilms query boolean false "Include films information" Enums(true,false)
-// @Success 200 {object} []domain.Actor "without films"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of actors"
+// @Param If-None-Match header string false "ETag of the list the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the list the client has, without films"
+// @Success 200 {object} domain.ActorPage "without films"
 // @Success 210 {object} []domain.ActorFilm "with films"
+// @Header 200,210 {string} ETag "Tag of the list"
+// @Header 200 {string} Last-Modified "Last change of the actors"
+// @Success 304
 // @Failure 400
 // @Failure 500
 // @Failure default
@@ -43,11 +53,20 @@ func (a *ActorHandler) actorsList(w http.ResponseWriter, req *http.Request) {
 			return
 		}
 
+		if notModified(w, req, etag(0, jsonData), time.Time{}) {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(210)
-		fmt.Fprintf(w, string(jsonData))
+		w.Write(jsonData)
 	} else {
-		actors, err := a.ser.Actor.GetActors()
+		page, err := parsePageRequest(req)
+		if err != nil {
+			newErrorResponse(w, err, "Wrong page params", http.StatusBadRequest)
+			return
+		}
+
+		actors, err := a.ser.Actor.GetActorsPage(page)
 		if err != nil {
 			newErrorResponse(w, err, "Can't get actors", http.StatusBadRequest)
 			return
@@ -58,8 +77,11 @@ func (a *ActorHandler) actorsList(w http.ResponseWriter, req *http.Request) {
 			return
 		}
 
+		if notModified(w, req, etag(0, jsonData), actors.UpdatedAt) {
+			return
+		}
 		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, string(jsonData))
+		w.Write(jsonData)
 	}
 }
 
@@ -67,11 +89,14 @@ func (a *ActorHandler) actorsList(w http.ResponseWriter, req *http.Request) {
 // @Security ApiKeyAuth
 // @Tags actors
 // @Description Create actor. You must have admin role.
+// @Description The created actor is returned with their ID, and Location tells where to get them.
 // @ID create-actor
 // @Accept  json
 // @Produce  json
 // @Param input body domain.Actor true "Actor"
-// @Success 201
+// @Success 201 {object} domain.Actor
+// @Header 201 {string} Location "Path of the created actor, like /actor/1"
+// @Header 201 {string} ETag "Version and tag of the created actor"
 // @Failure 400
 // @Failure default
 // @Router /actor [POST]
@@ -91,13 +116,23 @@ func (a *ActorHandler) createActor(w http.ResponseWriter, req *http.Request) {
 		newErrorResponse(w, err, "Can't decode actor from json", http.StatusBadRequest)
 		return
 	}
-	err = a.ser.Actor.CreateActor(actor)
+	actor, err = a.ser.Actor.CreateActor(editor(req), actor)
 	if err != nil {
 		newErrorResponse(w, err, "Can't create actor", http.StatusBadRequest)
 		return
 	}
 
+	jsonData, err := json.Marshal(actor)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse actor to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/actor/%d", actor.ID))
+	w.Header().Set("ETag", etag(actor.Version, jsonData))
 	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonData)
 }
 
 // @Summary Get actor by ID
@@ -107,7 +142,12 @@ func (a *ActorHandler) createActor(w http.ResponseWriter, req *http.Request) {
 // @ID get-actor-by-id
 // @Accept  json
 // @Produce  json
+// @Param If-None-Match header string false "ETag of the actor the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the actor the client has"
 // @Success 200 {object} domain.Actor
+// @Header 200 {string} ETag "Version and tag of the actor, to be sent as If-Match"
+// @Header 200 {string} Last-Modified "Last change of the actor"
+// @Success 304
 // @Failure 400
 // @Failure default
 // @Router /actor/{id} [GET]
@@ -130,20 +170,218 @@ func (a *ActorHandler) getActor(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if notModified(w, req, etag(actor.Version, jsonData), actor.UpdatedAt) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Patch actor by ID
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Change some fields of actor by ID with a JSON merge patch (RFC 7396) sent as application/merge-patch+json. Fields left out are kept, null clears a field, nullable fields like information are replaced as a whole, valid unless Valid is given. Members the actor has no field for, and read-only ones like id or photo, are refused. The patched actor must be valid. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.
+// @ID patch-actor-by-id
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Param input body domain.Actor true "Fields of the actor to change"
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
+// @Success 200 {object} domain.Actor
+// @Header 200 {string} ETag "Version and tag of the patched actor"
+// @Failure 400
+// @Failure 412
+// @Failure 415
+// @Failure 428
+// @Failure default
+// @Router /actor/{id} [PATCH]
+func (a *ActorHandler) patchActor(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	patch, err := readMergePatch(req)
+	if err != nil {
+		newPatchErrorResponse(w, err, "Can't parse actor patch from json")
+		return
+	}
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	var actor domain.Actor
+	actor, err = a.ser.Actor.PatchActor(editor(req), id, versions, patch)
+	if err != nil {
+		newVersionErrorResponse(w, err, "Can't patch actor")
+		return
+	}
+
+	jsonData, err := json.Marshal(actor)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse actor to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(actor.Version, jsonData))
+	w.Write(jsonData)
+}
+
+// @Summary Get revisions of actor
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Get the revisions of actor by ID, the latest first. Every update saves one, the first is how the actor was before their first update. Each revision tells the fields which changed since the revision before it.
+// @ID get-actor-revisions
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Success 200 {array} domain.ActorRevision
+// @Failure 400
+// @Failure default
+// @Router /actor/{id}/revisions [GET]
+func (a *ActorHandler) getActorRevisions(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var revisions []domain.ActorRevision
+	revisions, err = a.ser.Actor.GetActorRevisions(id)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get revisions of actor", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(revisions)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse revisions to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Get films of actor
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Get a page of the films of actor by ID with the parts the actor plays in them. Films go by year unless another order is asked for.
+// @ID get-actor-films
+// @Accept  json
+// @Produce  json
+// @Param orderBy query string false "Sort by params" Enums(year,title,rating,billing)
+// @Param sort query string false "Sort list by desc or asc" Enums(desc,asc)
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of films"
+// @Success 200 {object} domain.FilmCreditPage
+// @Failure 400
+// @Failure default
+// @Router /actor/{id}/films [GET]
+func (a *ActorHandler) getActorFilms(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseCreditQuery(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong page params", http.StatusBadRequest)
+		return
+	}
+
+	films, err := a.ser.Actor.GetActorFilms(id, q)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get films of actor", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(films)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse films to json.", http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, string(jsonData))
+	w.Write(jsonData)
+}
+
+// @Summary Get path between actors
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Get the shortest chain of films linking two actors, each step is a film the previous actor plays in together with the next one. The chain is searched up to a max number of films.
+// @ID get-actor-path
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Param otherId path int true "ID of the other actor"
+// @Success 200 {object} domain.ActorPath
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /actor/{id}/path/{otherId} [GET]
+func (a *ActorHandler) getActorPath(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+	otherId, err := strconv.ParseInt(req.PathValue("otherId"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	path, err := a.ser.Graph.GetActorPath(id, otherId)
+	if errors.Is(err, domain.ErrNoActorPath) {
+		newErrorResponse(w, err, "Actors aren't linked by films", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		newErrorResponse(w, err, "Can't get path between actors", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(path)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse path to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
 }
 
 // @Summary Update actor by ID
 // @Security ApiKeyAuth
 // @Tags actors
 // @Description Update actor by ID. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.
 // @ID update-actor-by-id
 // @Accept  json
 // @Produce  json
 // @Param input body domain.Actor true "Actor"
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
 // @Success 201
 // @Failure 400
+// @Failure 412
+// @Failure 428
 // @Failure default
 // @Router /actor/{id} [PUT]
 func (a *ActorHandler) updateActor(w http.ResponseWriter, req *http.Request) {
@@ -169,10 +407,15 @@ func (a *ActorHandler) updateActor(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	actor.ID = id
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
 
-	err = a.ser.Actor.UpdateActor(actor)
+	err = a.ser.Actor.UpdateActor(editor(req), actor, versions)
 	if err != nil {
-		newErrorResponse(w, err, "Can't update actor", http.StatusBadRequest)
+		newVersionErrorResponse(w, err, "Can't update actor")
 		return
 	}
 
@@ -182,12 +425,16 @@ func (a *ActorHandler) updateActor(w http.ResponseWriter, req *http.Request) {
 // @Summary Delete actor by ID
 // @Security ApiKeyAuth
 // @Tags actors
-// @Description Delete actor by ID. You must have admin role.
+// @Description Move actor to the trash. Their credits are kept until they are purged. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the actor was changed since it was read. The server may require If-Match, answering 428 without it.
 // @ID delete-actor-by-id
 // @Accept  json
 // @Produce  json
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
 // @Success 204
 // @Failure 400
+// @Failure 412
+// @Failure 428
 // @Failure default
 // @Router /actor/{id} [DELETE]
 func (a *ActorHandler) deleteActor(w http.ResponseWriter, req *http.Request) {
@@ -207,10 +454,112 @@ func (a *ActorHandler) deleteActor(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if err := a.ser.Actor.DeleteActor(id); err != nil {
-		newErrorResponse(w, err, "Can't delete actor", http.StatusBadRequest)
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.ser.Actor.DeleteActor(editor(req), id, versions); err != nil {
+		newVersionErrorResponse(w, err, "Can't delete actor")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Restore actor
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Take actor out of the trash with their credits. You must have admin role.
+// @ID restore-actor
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Success 204
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /actor/{id}/restore [POST]
+func (a *ActorHandler) restoreActor(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.ser.Actor.RestoreActor(editor(req), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			newErrorResponse(w, err, "Actor isn't in the trash", http.StatusNotFound)
+			return
+		}
+		newErrorResponse(w, err, "Can't restore actor", http.StatusBadRequest)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// @Summary Upload Photo
+// @Security ApiKeyAuth
+// @Tags actors
+// @Description Upload photo of actor by ID as JPEG, PNG, GIF or WebP. Thumbnails are made from it. The version of the actor grows, so ETags read before the upload no longer match. You must have admin role.
+// @ID upload-actor-photo
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Actor ID"
+// @Param image formData file true "Image"
+// @Success 201 {object} domain.Image
+// @Failure 400
+// @Failure 413
+// @Failure 415
+// @Failure default
+// @Router /actor/{id}/photo [POST]
+func (a *ActorHandler) uploadPhoto(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	data, err := readUpload(w, req, "image", a.ser.Config.MaxImageSize)
+	if err != nil {
+		newImageErrorResponse(w, err, "Can't read image from form")
+		return
+	}
+
+	photo, err := a.ser.Actor.SetPhoto(editor(req), id, data)
+	if err != nil {
+		newImageErrorResponse(w, err, "Can't upload photo")
+		return
+	}
+
+	jsonData, err := json.Marshal(photo)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse photo to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonData)
+}
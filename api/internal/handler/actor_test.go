@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
@@ -11,6 +12,7 @@ import (
 	"kinoteka/internal/domain"
 	"kinoteka/internal/service"
 	mock_service "kinoteka/internal/service/mocks"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -29,6 +31,7 @@ func TestFilmHandler_actorsList(t *testing.T) {
 		actorParam           string
 		orderByParam         string
 		descParam            bool
+		ifModifiedSince      string
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
@@ -37,38 +40,92 @@ func TestFilmHandler_actorsList(t *testing.T) {
 			addToUrl: ``,
 			mockBehavior: func(r *mock_service.MockActor) {
 				birthday, _ := time.Parse(time.RFC3339, "1980-12-11T00:00:00Z")
-				r.EXPECT().GetActors().Return([]domain.Actor{
-					{
-						ID:          1,
-						Name:        "Райан",
-						Surname:     "Томас Гослинг",
-						Patronymic:  sql.NullString{String: "", Valid: true},
-						Birthday:    birthday,
-						Sex:         "m",
-						Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
+				r.EXPECT().GetActorsPage(domain.PageRequest{}).Return(domain.ActorPage{
+					Actors: []domain.Actor{
+						{
+							ID:          1,
+							Name:        "Райан",
+							Surname:     "Томас Гослинг",
+							Patronymic:  sql.NullString{String: "", Valid: true},
+							Birthday:    birthday,
+							Sex:         "m",
+							Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
+						},
 					},
 				}, nil)
 			},
 			expectedStatusCode: 200,
 			titleParam:         "",
 			descParam:          false,
-			expectedResponseBody: `[
-    {
-    "id": 1,
-    "name": "Райан",
-    "surname": "Томас Гослинг",
-    "patronymic": {
-        "String": "",
-        "Valid": true
-    },
-    "birthday": "1980-12-11T00:00:00Z",
-    "sex": "m",
-    "information": {
-        "String": "Томас Гослинг Райан",
-        "Valid": true
-    }
-}
-]`,
+			expectedResponseBody: `{
+    "actors": [
+        {
+            "id": 1,
+            "name": "Райан",
+            "surname": "Томас Гослинг",
+            "patronymic": {
+                "String": "",
+                "Valid": true
+            },
+            "birthday": "1980-12-11T00:00:00Z",
+            "sex": "m",
+            "information": {
+                "String": "Томас Гослинг Райан",
+                "Valid": true
+            }
+        }
+    ]
+}`,
+		},
+		{
+			name:     "Ok with limit and cursor",
+			addToUrl: `?limit=1&cursor=eyJpZCI6MX0&withTotal=true`,
+			mockBehavior: func(r *mock_service.MockActor) {
+				birthday, _ := time.Parse(time.RFC3339, "1976-05-25T00:00:00Z")
+				total := int64(13)
+				r.EXPECT().GetActorsPage(domain.PageRequest{Limit: 1, Cursor: "eyJpZCI6MX0", WithTotal: true}).Return(domain.ActorPage{
+					Actors: []domain.Actor{
+						{
+							ID:       2,
+							Name:     "Киллиан",
+							Surname:  "Мерфи",
+							Birthday: birthday,
+							Sex:      "m",
+						},
+					},
+					NextCursor: "eyJpZCI6Mn0",
+					Total:      &total,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "actors": [
+        {
+            "id": 2,
+            "name": "Киллиан",
+            "surname": "Мерфи",
+            "patronymic": {
+                "String": "",
+                "Valid": false
+            },
+            "birthday": "1976-05-25T00:00:00Z",
+            "sex": "m",
+            "information": {
+                "String": "",
+                "Valid": false
+            }
+        }
+    ],
+    "nextCursor": "eyJpZCI6Mn0",
+    "total": 13
+}`,
+		},
+		{
+			name:                 "Wrong limit",
+			addToUrl:             `?limit=-5`,
+			mockBehavior:         func(r *mock_service.MockActor) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong page params"}`,
 		},
 		{
 			name:     "Ok with films",
@@ -94,7 +151,9 @@ func TestFilmHandler_actorsList(t *testing.T) {
 				r.EXPECT().GetActorsWithFilms().Return([]domain.ActorFilm{
 					{
 						Actor: a,
-						Films: []domain.Film{f},
+						Films: []domain.FilmCredit{
+							{Film: f, Part: domain.Part{Character: "Данила Багров", Billing: 1, Type: domain.CreditLead}},
+						},
 					},
 				}, nil)
 			},
@@ -131,18 +190,40 @@ func TestFilmHandler_actorsList(t *testing.T) {
                 "rating": {
                     "Float64": 8.6,
                     "Valid": true
-                }
+                },
+                "votes": 0,
+                "editorialRating": {"Float64": 0, "Valid": false},
+                "runtimeMinutes": {"Int64": 0, "Valid": false},
+                "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+                "countries": null,
+                "originalLanguage": {"String": "", "Valid": false},
+                "character": "Данила Багров",
+                "billing": 1,
+                "type": "lead"
             }
 ]
 
 }
 ]`,
 		},
+		{
+			name:            "Not modified since",
+			addToUrl:        ``,
+			ifModifiedSince: "Wed, 01 May 2024 10:00:00 GMT",
+			mockBehavior: func(r *mock_service.MockActor) {
+				r.EXPECT().GetActorsPage(domain.PageRequest{}).Return(domain.ActorPage{
+					Actors:    []domain.Actor{{ID: 1, Name: "Райан"}},
+					UpdatedAt: time.Date(2024, 4, 30, 10, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			expectedStatusCode:   304,
+			expectedResponseBody: ``,
+		},
 		{
 			name:     "Can't get actors",
 			addToUrl: ``,
 			mockBehavior: func(r *mock_service.MockActor) {
-				r.EXPECT().GetActors().Return([]domain.Actor{}, errors.New(""))
+				r.EXPECT().GetActorsPage(domain.PageRequest{}).Return(domain.ActorPage{}, errors.New(""))
 			},
 			expectedStatusCode:   400,
 			titleParam:           "",
@@ -171,13 +252,18 @@ func TestFilmHandler_actorsList(t *testing.T) {
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/actor%s", test.addToUrl)
 			req := httptest.NewRequest("GET", url, nil)
+			if test.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", test.ifModifiedSince)
+			}
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
 			http.DefaultServeMux.ServeHTTP(w, req)
 
 			// Assert
 			assert.Equal(t, w.Code, test.expectedStatusCode)
-			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
 		})
 	}
 }
@@ -197,6 +283,7 @@ func TestFilmHandler_createActor(t *testing.T) {
 		birthday             string
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedLocation     string
 	}{
 		{
 			name: "Ok",
@@ -222,15 +309,27 @@ func TestFilmHandler_createActor(t *testing.T) {
 				Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
 			},
 			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor) {
-				r.EXPECT().CreateActor(actor).Return(nil)
+				created := actor
+				created.ID = 5
+				created.Version = 1
+				r.EXPECT().CreateActor(domain.Editor{UserID: 10}, actor).Return(created, nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   201,
-			ID:                   10,
-			birthday:             "1980-12-11T00:00:00Z",
-			expectedResponseBody: ``,
+			expectedStatusCode: 201,
+			ID:                 10,
+			birthday:           "1980-12-11T00:00:00Z",
+			expectedResponseBody: `{
+    "id": 5,
+    "name": "Райан",
+    "surname": "Томас Гослинг",
+    "patronymic": {"String": "", "Valid": true},
+    "birthday": "1980-12-11T00:00:00Z",
+    "sex": "m",
+    "information": {"String": "Томас Гослинг Райан", "Valid": true}
+}`,
+			expectedLocation: "/actor/5",
 		},
 		{
 			name: "Wrong request",
@@ -248,7 +347,7 @@ func TestFilmHandler_createActor(t *testing.T) {
 				Information: sql.NullString{String: "02:19", Valid: true},
 			},
 			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor) {
-				r.EXPECT().CreateActor(actor).Return(errors.New("actor is not valid"))
+				r.EXPECT().CreateActor(domain.Editor{UserID: 10}, actor).Return(domain.Actor{}, errors.New("actor is not valid"))
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -319,13 +418,14 @@ func TestFilmHandler_createActor(t *testing.T) {
 			if w.Body.String() != test.expectedResponseBody {
 				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
 			}
+			assert.Equal(t, test.expectedLocation, w.Header().Get("Location"))
 		})
 	}
 }
 
 func TestFilmHandler_updateActor(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockActor, actor domain.Actor)
+	type mockBehavior func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions)
 	type mockBehavior2 func(r *mock_service.MockUser, id int64)
 
 	tests := []struct {
@@ -335,6 +435,8 @@ func TestFilmHandler_updateActor(t *testing.T) {
 		mockBehaviorAdmin    mockBehavior2
 		inputBody            string
 		inputActor           domain.Actor
+		ifMatch              string
+		versions             domain.Versions
 		ID                   int64
 		birthday             string
 		expectedStatusCode   int
@@ -365,8 +467,8 @@ func TestFilmHandler_updateActor(t *testing.T) {
 				Sex:         "m",
 				Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
 			},
-			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor) {
-				r.EXPECT().UpdateActor(actor).Return(nil)
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {
+				r.EXPECT().UpdateActor(domain.Editor{UserID: 10}, actor, versions).Return(nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -389,8 +491,8 @@ func TestFilmHandler_updateActor(t *testing.T) {
 				ID:          1,
 				Information: sql.NullString{String: "02:19", Valid: true},
 			},
-			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor) {
-				r.EXPECT().UpdateActor(actor).Return(errors.New("actor is not valid"))
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {
+				r.EXPECT().UpdateActor(domain.Editor{UserID: 10}, actor, versions).Return(errors.New("actor is not valid"))
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -415,7 +517,7 @@ func TestFilmHandler_updateActor(t *testing.T) {
     }
 }`,
 			inputActor:   domain.Actor{},
-			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor) {},
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(false, nil)
 			},
@@ -423,6 +525,51 @@ func TestFilmHandler_updateActor(t *testing.T) {
 			ID:                   10,
 			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
 		},
+		{
+			name:       "Stale version",
+			addToUrl:   "/1",
+			inputBody:  `{"name": "Райан", "surname": "Гослинг", "sex": "m"}`,
+			inputActor: domain.Actor{ID: 1, Name: "Райан", Surname: "Гослинг", Sex: "m"},
+			ifMatch:    `"4"`,
+			versions:   domain.Versions{4},
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {
+				r.EXPECT().UpdateActor(domain.Editor{UserID: 10}, actor, versions).Return(domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			ID:                   10,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:       "Version required",
+			addToUrl:   "/1",
+			inputBody:  `{"name": "Райан", "surname": "Гослинг", "sex": "m"}`,
+			inputActor: domain.Actor{ID: 1, Name: "Райан", Surname: "Гослинг", Sex: "m"},
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {
+				r.EXPECT().UpdateActor(domain.Editor{UserID: 10}, actor, versions).Return(domain.ErrVersionRequired)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   428,
+			ID:                   10,
+			expectedResponseBody: `{"message":"version must be given to change it"}`,
+		},
+		{
+			name:         "Wrong If-Match",
+			addToUrl:     "/1",
+			inputBody:    `{"name": "Райан", "surname": "Гослинг", "sex": "m"}`,
+			ifMatch:      "4",
+			mockBehavior: func(r *mock_service.MockActor, actor domain.Actor, versions domain.Versions) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"Wrong If-Match header"}`,
+		},
 	}
 
 	for _, test := range tests {
@@ -438,7 +585,7 @@ func TestFilmHandler_updateActor(t *testing.T) {
 			birthday, _ := time.Parse(time.RFC3339, test.birthday)
 			test.inputActor.Birthday = birthday
 
-			test.mockBehavior(repo, test.inputActor)
+			test.mockBehavior(repo, test.inputActor, test.versions)
 			test.mockBehaviorAdmin(repo2, test.ID)
 
 			services := &service.Service{Actor: repo, User: repo2}
@@ -453,6 +600,9 @@ func TestFilmHandler_updateActor(t *testing.T) {
 			url := fmt.Sprintf("/actor%s", test.addToUrl)
 			req := httptest.NewRequest("PUT", url,
 				bytes.NewBufferString(test.inputBody))
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
 			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
@@ -467,6 +617,183 @@ func TestFilmHandler_updateActor(t *testing.T) {
 	}
 }
 
+func TestFilmHandler_patchActor(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		contentType          string
+		ifMatch              string
+		inputBody            string
+		versions             domain.Versions
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:        "Ok",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"2-d2c1e0bb3ddc1da8"`,
+			inputBody:   `{"information": null}`,
+			versions:    domain.Versions{2},
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchActor(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Actor{ID: 1, Name: "Сергей", Surname: "Бодров", Sex: "m", Version: 3}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "id": 1,
+    "name": "Сергей",
+    "surname": "Бодров",
+    "patronymic": {"String": "", "Valid": false},
+    "birthday": "0001-01-01T00:00:00Z",
+    "sex": "m",
+    "information": {"String": "", "Valid": false}
+}`,
+			expectedETag: `"3-fca8980e01f6861d"`,
+		},
+		{
+			name:        "Ok with charset",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json; charset=utf-8",
+			inputBody:   `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchActor(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Actor{ID: 1, Name: "Сергей", Surname: "Бодров", Sex: "m", Version: 3}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "id": 1,
+    "name": "Сергей",
+    "surname": "Бодров",
+    "patronymic": {"String": "", "Valid": false},
+    "birthday": "0001-01-01T00:00:00Z",
+    "sex": "m",
+    "information": {"String": "", "Valid": false}
+}`,
+		},
+		{
+			name:         "Wrong content type",
+			addToUrl:     "/1",
+			contentType:  "application/json",
+			inputBody:    `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   415,
+			expectedResponseBody: `{"message":"patch must be sent as application/merge-patch+json"}`,
+		},
+		{
+			name:         "Not an object",
+			addToUrl:     "/1",
+			contentType:  "application/merge-patch+json",
+			inputBody:    `null`,
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse actor patch from json"}`,
+		},
+		{
+			name:        "Not valid",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"name": null}`,
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchActor(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Actor{}, errors.New("actor is not valid"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't patch actor"}`,
+		},
+		{
+			name:        "Stale version",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"1"`,
+			inputBody:   `{"information": null}`,
+			versions:    domain.Versions{1},
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchActor(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Actor{}, domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1",
+			contentType:  "application/merge-patch+json",
+			inputBody:    `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockActor, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockActor(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, 1, test.versions, test.inputBody)
+			test.mockBehaviorAdmin(repo2, 10)
+
+			services := &service.Service{Actor: repo, User: repo2}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("PATCH /actor/{id}", middlewareLog(http.HandlerFunc(handler.patchActor)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			req := httptest.NewRequest("PATCH", url,
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			if test.expectedETag != "" {
+				assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
 func TestFilmHandler_deleteActor(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockActor, id int64)
@@ -479,6 +806,7 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 		mockBehaviorAdmin    mockBehavior2
 		UserId               int64
 		ActorId              int64
+		ifMatch              string
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
@@ -486,7 +814,7 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 			name:     "Ok",
 			addToUrl: "/1",
 			mockBehavior: func(r *mock_service.MockActor, id int64) {
-				r.EXPECT().DeleteActor(id).Return(nil)
+				r.EXPECT().DeleteActor(domain.Editor{UserID: 10}, id, nil).Return(nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -512,7 +840,7 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 			name:     "Can't delete",
 			addToUrl: "/1",
 			mockBehavior: func(r *mock_service.MockActor, id int64) {
-				r.EXPECT().DeleteActor(id).Return(errors.New(""))
+				r.EXPECT().DeleteActor(domain.Editor{UserID: 10}, id, nil).Return(errors.New(""))
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -522,6 +850,21 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 			ActorId:              1,
 			expectedResponseBody: `{"message":"Can't delete actor"}`,
 		},
+		{
+			name:     "Stale version",
+			addToUrl: "/1",
+			ifMatch:  `"2"`,
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().DeleteActor(domain.Editor{UserID: 10}, id, domain.Versions{2}).Return(domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
 		{
 			name:         "Bad url",
 			addToUrl:     "/asd",
@@ -561,6 +904,9 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 			url := fmt.Sprintf("/actor%s", test.addToUrl)
 			req := httptest.NewRequest("DELETE", url,
 				nil)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
 			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
@@ -575,56 +921,193 @@ func TestFilmHandler_deleteActor(t *testing.T) {
 	}
 }
 
-func TestFilmHandler_getActor(t *testing.T) {
+func TestFilmHandler_restoreActor(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockActor, id int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
 
 	tests := []struct {
 		name                 string
 		addToUrl             string
 		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
 		ActorId              int64
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:     "Ok",
-			addToUrl: "/1",
+			addToUrl: "/1/restore",
 			mockBehavior: func(r *mock_service.MockActor, id int64) {
-				birthday, _ := time.Parse(time.RFC3339, "1980-12-11T00:00:00Z")
-				r.EXPECT().GetActor(id).Return(domain.Actor{
-					ID:          1,
-					Name:        "Райан",
-					Surname:     "Томас Гослинг",
-					Patronymic:  sql.NullString{String: "", Valid: true},
-					Birthday:    birthday,
-					Sex:         "m",
-					Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
-				}, nil)
+				r.EXPECT().RestoreActor(domain.Editor{UserID: 10}, id).Return(nil)
 			},
-			expectedStatusCode: 200,
-			ActorId:            1,
-			expectedResponseBody: `
-{
-    "id": 1,
-    "name": "Райан",
-    "surname": "Томас Гослинг",
-    "patronymic": {
-        "String": "",
-        "Valid": true
-    },
-    "birthday": "1980-12-11T00:00:00Z",
-    "sex": "m",
-    "information": {
-        "String": "Томас Гослинг Райан",
-        "Valid": true
-    }
-}`,
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: ``,
 		},
 		{
-			name:                 "Bad url",
-			addToUrl:             "/asd",
-			mockBehavior:         func(r *mock_service.MockActor, id int64) {},
+			name:     "Not in trash",
+			addToUrl: "/1/restore",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().RestoreActor(domain.Editor{UserID: 10}, id).Return(sql.ErrNoRows)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   404,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: `{"message":"Actor isn't in the trash"}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1/restore",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:     "Can't restore",
+			addToUrl: "/1/restore",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().RestoreActor(domain.Editor{UserID: 10}, id).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: `{"message":"Can't restore actor"}`,
+		},
+		{
+			name:         "Bad url",
+			addToUrl:     "/asd/restore",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			ActorId:              1,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockActor(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.ActorId)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Actor: repo, User: repo2}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /actor/{id}/restore", middlewareLog(http.HandlerFunc(handler.restoreActor)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_getActor(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockActor, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		ifNoneMatch          string
+		mockBehavior         mockBehavior
+		ActorId              int64
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				birthday, _ := time.Parse(time.RFC3339, "1980-12-11T00:00:00Z")
+				r.EXPECT().GetActor(id).Return(domain.Actor{
+					ID:          1,
+					Name:        "Райан",
+					Surname:     "Томас Гослинг",
+					Patronymic:  sql.NullString{String: "", Valid: true},
+					Birthday:    birthday,
+					Sex:         "m",
+					Information: sql.NullString{String: "Томас Гослинг Райан", Valid: true},
+					Version:     2,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			ActorId:            1,
+			expectedETag:       `"2-882ee2fba4dee2b7"`,
+			expectedResponseBody: `
+{
+    "id": 1,
+    "name": "Райан",
+    "surname": "Томас Гослинг",
+    "patronymic": {
+        "String": "",
+        "Valid": true
+    },
+    "birthday": "1980-12-11T00:00:00Z",
+    "sex": "m",
+    "information": {
+        "String": "Томас Гослинг Райан",
+        "Valid": true
+    }
+}`,
+		},
+		{
+			name:        "Not modified",
+			addToUrl:    "/1",
+			ifNoneMatch: "*",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().GetActor(id).Return(domain.Actor{ID: 1, Name: "Райан", Version: 2}, nil)
+			},
+			expectedStatusCode:   304,
+			ActorId:              1,
+			expectedResponseBody: ``,
+			expectedETag:         `"2-b26c3c76e3c20daa"`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/asd",
+			mockBehavior:         func(r *mock_service.MockActor, id int64) {},
 			expectedStatusCode:   400,
 			ActorId:              1,
 			expectedResponseBody: `{"message":"Can't parse id from path"}`,
@@ -664,6 +1147,257 @@ func TestFilmHandler_getActor(t *testing.T) {
 			url := fmt.Sprintf("/actor%s", test.addToUrl)
 			req := httptest.NewRequest("GET", url,
 				nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+			assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestFilmHandler_getActorRevisions(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockActor, id int64)
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	birthday := time.Date(1971, 12, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		ActorId              int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/12/revisions",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().GetActorRevisions(id).Return([]domain.ActorRevision{
+					{
+						Rev:       2,
+						CreatedAt: createdAt,
+						Actor: domain.Actor{
+							ID:          12,
+							Name:        "Сергей",
+							Surname:     "Бодров",
+							Birthday:    birthday,
+							Sex:         "m",
+							Information: sql.NullString{String: "Актёр", Valid: true},
+						},
+						Diff: domain.Diff{"information": {
+							Before: json.RawMessage(`{"String":"","Valid":false}`),
+							After:  json.RawMessage(`{"String":"Актёр","Valid":true}`),
+						}},
+					},
+					{
+						Rev:       1,
+						CreatedAt: createdAt.Add(-time.Hour),
+						Actor:     domain.Actor{ID: 12, Name: "Сергей", Surname: "Бодров", Birthday: birthday, Sex: "m"},
+					},
+				}, nil)
+			},
+			ActorId:            12,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {
+        "rev": 2,
+        "createdAt": "2024-03-01T12:00:00Z",
+        "actor": {
+            "id": 12,
+            "name": "Сергей",
+            "surname": "Бодров",
+            "patronymic": {"String": "", "Valid": false},
+            "birthday": "1971-12-27T00:00:00Z",
+            "sex": "m",
+            "information": {"String": "Актёр", "Valid": true}
+        },
+        "diff": {"information": {
+            "before": {"String": "", "Valid": false},
+            "after": {"String": "Актёр", "Valid": true}
+        }}
+    },
+    {
+        "rev": 1,
+        "createdAt": "2024-03-01T11:00:00Z",
+        "actor": {
+            "id": 12,
+            "name": "Сергей",
+            "surname": "Бодров",
+            "patronymic": {"String": "", "Valid": false},
+            "birthday": "1971-12-27T00:00:00Z",
+            "sex": "m",
+            "information": {"String": "", "Valid": false}
+        }
+    }
+]`,
+		},
+		{
+			name:     "No actor",
+			addToUrl: "/12/revisions",
+			mockBehavior: func(r *mock_service.MockActor, id int64) {
+				r.EXPECT().GetActorRevisions(id).Return(nil, sql.ErrNoRows)
+			},
+			ActorId:              12,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get revisions of actor"}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/asd/revisions",
+			mockBehavior:         func(r *mock_service.MockActor, id int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockActor(c)
+			test.mockBehavior(repo, test.ActorId)
+
+			services := &service.Service{Actor: repo}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /actor/{id}/revisions", middlewareLog(http.HandlerFunc(handler.getActorRevisions)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestFilmHandler_uploadPhoto(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockActor, actorId int64, data []byte)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	data := []byte("\xff\xd8\xff\xe0")
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		ActorId              int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/3/photo",
+			mockBehavior: func(r *mock_service.MockActor, actorId int64, data []byte) {
+				r.EXPECT().SetPhoto(domain.Editor{UserID: 10}, actorId, data).Return(domain.Image{
+					URL: "/media/actors/3/photo/6071a1b2c3d4e5f0/original.jpg",
+					Thumbnails: map[string]string{
+						"small":  "/media/actors/3/photo/6071a1b2c3d4e5f0/small.jpg",
+						"medium": "/media/actors/3/photo/6071a1b2c3d4e5f0/medium.jpg",
+						"large":  "/media/actors/3/photo/6071a1b2c3d4e5f0/large.jpg",
+					},
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			ActorId:            3,
+			expectedStatusCode: 201,
+			expectedResponseBody: `{
+    "url": "/media/actors/3/photo/6071a1b2c3d4e5f0/original.jpg",
+    "thumbnails": {
+        "small": "/media/actors/3/photo/6071a1b2c3d4e5f0/small.jpg",
+        "medium": "/media/actors/3/photo/6071a1b2c3d4e5f0/medium.jpg",
+        "large": "/media/actors/3/photo/6071a1b2c3d4e5f0/large.jpg"
+    }
+}`,
+		},
+		{
+			name:         "Wrong id",
+			addToUrl:     "/three/photo",
+			mockBehavior: func(r *mock_service.MockActor, actorId int64, data []byte) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:     "Can't upload",
+			addToUrl: "/3/photo",
+			mockBehavior: func(r *mock_service.MockActor, actorId int64, data []byte) {
+				r.EXPECT().SetPhoto(domain.Editor{UserID: 10}, actorId, data).Return(domain.Image{}, errors.New("disk is full"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			ActorId:              3,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't upload photo"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockActor(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.ActorId, data)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Actor: repo, User: repo2}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /actor/{id}/photo", middlewareLog(http.HandlerFunc(handler.uploadPhoto)))
+
+			// Create Request
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile("image", "photo.jpg")
+			part.Write(data)
+			form.Close()
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
 			http.DefaultServeMux.ServeHTTP(w, req)
@@ -676,3 +1410,231 @@ func TestFilmHandler_getActor(t *testing.T) {
 		})
 	}
 }
+
+func TestFilmHandler_getActorPath(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGraph, id, otherId int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		ActorId              int64
+		OtherId              int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1/path/3",
+			mockBehavior: func(r *mock_service.MockGraph, id, otherId int64) {
+				r.EXPECT().GetActorPath(id, otherId).Return(domain.ActorPath{
+					Degrees: 2,
+					Start:   domain.PathActor{ID: 1, Name: "Сергей", Surname: "Бодров"},
+					Steps: []domain.PathStep{
+						{
+							Film:  domain.PathFilm{ID: 6, Title: "Брат", Year: 1997},
+							Actor: domain.PathActor{ID: 2, Name: "Виктор", Surname: "Сухоруков"},
+						},
+						{
+							Film:  domain.PathFilm{ID: 8, Title: "Жмурки", Year: 2005},
+							Actor: domain.PathActor{ID: 3, Name: "Никита", Surname: "Михалков"},
+						},
+					},
+				}, nil)
+			},
+			ActorId:            1,
+			OtherId:            3,
+			expectedStatusCode: 200,
+			expectedResponseBody: `
+{
+    "degrees": 2,
+    "start": {"id": 1, "name": "Сергей", "surname": "Бодров"},
+    "steps": [
+        {
+            "film": {"id": 6, "title": "Брат", "year": 1997},
+            "actor": {"id": 2, "name": "Виктор", "surname": "Сухоруков"}
+        },
+        {
+            "film": {"id": 8, "title": "Жмурки", "year": 2005},
+            "actor": {"id": 3, "name": "Никита", "surname": "Михалков"}
+        }
+    ]
+}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/1/path/asd",
+			mockBehavior:         func(r *mock_service.MockGraph, id, otherId int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:     "No path",
+			addToUrl: "/1/path/4",
+			mockBehavior: func(r *mock_service.MockGraph, id, otherId int64) {
+				r.EXPECT().GetActorPath(id, otherId).Return(domain.ActorPath{}, domain.ErrNoActorPath)
+			},
+			ActorId:              1,
+			OtherId:              4,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"Actors aren't linked by films"}`,
+		},
+		{
+			name:     "Can't get",
+			addToUrl: "/1/path/100",
+			mockBehavior: func(r *mock_service.MockGraph, id, otherId int64) {
+				r.EXPECT().GetActorPath(id, otherId).Return(domain.ActorPath{}, errors.New("there is no actor with id = 100"))
+			},
+			ActorId:              1,
+			OtherId:              100,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get path between actors"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGraph(c)
+			test.mockBehavior(repo, test.ActorId, test.OtherId)
+
+			services := &service.Service{Graph: repo}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /actor/{id}/path/{otherId}", middlewareLog(http.HandlerFunc(handler.getActorPath)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			req := httptest.NewRequest("GET", url, nil)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestFilmHandler_getActorFilms(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockActor, id int64, q domain.CreditQuery)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		ActorId              int64
+		query                domain.CreditQuery
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1/films?orderBy=rating&sort=desc&limit=1",
+			mockBehavior: func(r *mock_service.MockActor, id int64, q domain.CreditQuery) {
+				r.EXPECT().GetActorFilms(id, q).Return(domain.FilmCreditPage{
+					Films: []domain.FilmCredit{
+						{
+							Film: domain.Film{ID: 6, Title: "Брат", Year: 1997},
+							Part: domain.Part{Character: "Данила Багров", Billing: 1, Type: domain.CreditLead},
+						},
+					},
+					NextCursor: "eyJvIjoicmF0aW5nIiwiZCI6dHJ1ZSwidiI6Ii0xIiwiaWQiOjZ9",
+				}, nil)
+			},
+			ActorId: 1,
+			query: domain.CreditQuery{
+				OrderBy: "rating",
+				Desc:    true,
+				Page:    domain.PageRequest{Limit: 1},
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `
+{
+    "films": [
+        {
+            "id": 6,
+            "title": "Брат",
+            "year": 1997,
+            "information": {"String": "", "Valid": false},
+            "rating": {"Float64": 0, "Valid": false},
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false},
+            "character": "Данила Багров",
+            "billing": 1,
+            "type": "lead"
+        }
+    ],
+    "nextCursor": "eyJvIjoicmF0aW5nIiwiZCI6dHJ1ZSwidiI6Ii0xIiwiaWQiOjZ9"
+}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/asd/films",
+			mockBehavior:         func(r *mock_service.MockActor, id int64, q domain.CreditQuery) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:                 "Wrong limit",
+			addToUrl:             "/1/films?limit=0",
+			mockBehavior:         func(r *mock_service.MockActor, id int64, q domain.CreditQuery) {},
+			ActorId:              1,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong page params"}`,
+		},
+		{
+			name:     "Can't get",
+			addToUrl: "/100/films",
+			mockBehavior: func(r *mock_service.MockActor, id int64, q domain.CreditQuery) {
+				r.EXPECT().GetActorFilms(id, q).Return(domain.FilmCreditPage{}, errors.New(""))
+			},
+			ActorId:              100,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get films of actor"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockActor(c)
+			test.mockBehavior(repo, test.ActorId, test.query)
+
+			services := &service.Service{Actor: repo}
+			handler := ActorHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /actor/{id}/films", middlewareLog(http.HandlerFunc(handler.getActorFilms)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/actor%s", test.addToUrl)
+			req := httptest.NewRequest("GET", url, nil)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
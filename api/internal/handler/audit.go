@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+)
+
+type AuditHandler struct {
+	ser *service.Service
+}
+
+// @Summary Get audit log
+// @Security ApiKeyAuth
+// @Tags audit
+// @Description Get the changes of films and actors, the latest first. Every entry tells who made the change, in which request, and the fields it changed with their values before and after it. You must have admin role.
+// @Description Actions are create, update, delete, restore, revert, cast_add, cast_replace, cast_remove, genre_add, poster, photo and purge. Cast changes are told as a change of the cast field of the film, added genres as one of its genres field and uploads as one of the poster or photo field.
+// @ID get-audit
+// @Produce  json
+// @Param entityType query string false "Kind of entity" Enums(film,actor)
+// @Param entityId query int false "Entity ID, needs entityType"
+// @Param userId query int false "ID of the user who made the changes"
+// @Param from query string false "Changes made at or after, like 2006-01-02T15:04:05Z or 2006-01-02"
+// @Param to query string false "Changes made before, like 2006-01-02T15:04:05Z or 2006-01-02"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of entries"
+// @Success 200 {object} domain.AuditPage
+// @Failure 400
+// @Failure default
+// @Router /audit [get]
+func (h *AuditHandler) getAudit(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseAuditQuery(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong audit params", http.StatusBadRequest)
+		return
+	}
+
+	var page domain.AuditPage
+	page, err = h.ser.Audit.GetAudit(q)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get audit log", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(page)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse audit log to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+func parseAuditQuery(req *http.Request) (domain.AuditQuery, error) {
+	var q domain.AuditQuery
+	query := req.URL.Query()
+	q.EntityType = query.Get("entityType")
+	entityId, err := queryInt(query, "entityId")
+	if err != nil {
+		return q, err
+	}
+	q.EntityID = int64(entityId)
+	userId, err := queryInt(query, "userId")
+	if err != nil {
+		return q, err
+	}
+	q.UserID = int64(userId)
+	if q.From, err = queryTime(query, "from"); err != nil {
+		return q, err
+	}
+	if q.To, err = queryTime(query, "to"); err != nil {
+		return q, err
+	}
+	q.Page, err = parsePageRequest(req)
+
+	return q, err
+}
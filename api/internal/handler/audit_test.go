@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuditHandler_getAudit(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockAudit, q domain.AuditQuery)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		query                domain.AuditQuery
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "?entityType=film&entityId=6&from=2024-03-01&to=2024-03-02T00:00:00Z&limit=1",
+			mockBehavior: func(r *mock_service.MockAudit, q domain.AuditQuery) {
+				r.EXPECT().GetAudit(q).Return(domain.AuditPage{
+					Entries: []domain.AuditEntry{{
+						ID:         41,
+						UserID:     10,
+						RequestID:  "4f1c9b2e",
+						Action:     domain.AuditUpdate,
+						EntityType: domain.AuditFilm,
+						EntityID:   6,
+						Diff: domain.Diff{"title": {
+							Before: json.RawMessage(`"Брат"`),
+							After:  json.RawMessage(`"Брат 2"`),
+						}},
+						CreatedAt: createdAt,
+					}},
+					NextCursor: "eyJpZCI6NDF9",
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId: 10,
+			query: domain.AuditQuery{
+				EntityType: domain.AuditFilm,
+				EntityID:   6,
+				From:       createdAt.Add(-12 * time.Hour),
+				To:         createdAt.Add(12 * time.Hour),
+				Page:       domain.PageRequest{Limit: 1},
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "entries": [{
+        "id": 41,
+        "userId": 10,
+        "requestId": "4f1c9b2e",
+        "action": "update",
+        "entityType": "film",
+        "entityId": 6,
+        "diff": {"title": {"before": "Брат", "after": "Брат 2"}},
+        "createdAt": "2024-03-01T12:00:00Z"
+    }],
+    "nextCursor": "eyJpZCI6NDF9"
+}`,
+		},
+		{
+			name:     "Ok by user",
+			addToUrl: "?userId=12&withTotal=true",
+			mockBehavior: func(r *mock_service.MockAudit, q domain.AuditQuery) {
+				total := int64(0)
+				r.EXPECT().GetAudit(q).Return(domain.AuditPage{Entries: []domain.AuditEntry{}, Total: &total}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.AuditQuery{UserID: 12, Page: domain.PageRequest{WithTotal: true}},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"entries": [], "total": 0}`,
+		},
+		{
+			name:         "Not admin",
+			mockBehavior: func(r *mock_service.MockAudit, q domain.AuditQuery) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:         "Wrong time",
+			addToUrl:     "?from=yesterday",
+			mockBehavior: func(r *mock_service.MockAudit, q domain.AuditQuery) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong audit params"}`,
+		},
+		{
+			name:     "Wrong entity type",
+			addToUrl: "?entityType=genre",
+			mockBehavior: func(r *mock_service.MockAudit, q domain.AuditQuery) {
+				r.EXPECT().GetAudit(q).Return(domain.AuditPage{}, errors.New("entityType must be film or actor"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.AuditQuery{EntityType: "genre"},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get audit log"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockAudit(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.query)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Audit: repo, User: repo2}
+			handler := AuditHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /audit", middlewareLog(http.HandlerFunc(handler.getAudit)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/audit%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
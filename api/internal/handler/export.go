@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"log"
+	"net/http"
+)
+
+type ExportHandler struct {
+	ser *service.Service
+}
+
+// exportContentTypes maps the export formats to the content types of
+// exported files.
+var exportContentTypes = map[string]string{
+	domain.ExportCSV:    "text/csv; charset=utf-8",
+	domain.ExportNDJSON: "application/x-ndjson",
+	domain.ExportJSON:   "application/json",
+}
+
+// exportWriter tells whether anything was written to the response. Once an
+// export has begun, its status can't be changed.
+type exportWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.written = true
+	return e.w.Write(p)
+}
+
+// @Summary Export films
+// @Security ApiKeyAuth
+// @Tags export
+// @Description Export films matching the filters of the film list, in order of IDs. As in the film list, if no title or actor matches, similar ones do. You must have admin role.
+// @Description Films are written as they are read from the database. Credits go to the last CSV column as a JSON array.
+// @Description A CSV or NDJSON export can be imported back as new films: id, rating, votes and credits are skipped by imports.
+// @ID export-films
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  json
+// @Param format query string false "Format of the file, csv by default" Enums(csv,ndjson,json)
+// @Param withCredits query boolean false "Include the cast of every film"
+// @Param title query string false "Search by title"
+// @Param actor query string false "Search by actor name, surname or patronymic"
+// @Param yearFrom query int false "Minimal year"
+// @Param yearTo query int false "Maximal year"
+// @Param minRating query number false "Minimal user rating"
+// @Param maxRating query number false "Maximal user rating"
+// @Param hasRating query boolean false "Only films with or without user votes"
+// @Param minRuntime query int false "Minimal runtime in minutes"
+// @Param maxRuntime query int false "Maximal runtime in minutes"
+// @Param releasedFrom query string false "Released on or after the date, YYYY-MM-DD"
+// @Param releasedTo query string false "Released on or before the date, YYYY-MM-DD"
+// @Param country query string false "Production country, ISO 3166-1 alpha-2 code"
+// @Param language query string false "Original language, ISO 639 code"
+// @Param inWatchlist query boolean false "Only films which are or aren't in the watchlist of the user"
+// @Param genre query string false "Comma separated genre names"
+// @Param genreMode query string false "Film must have all (and) or any (or) of the genres" Enums(or,and)
+// @Success 200 {array} domain.ExportFilm
+// @Failure 400
+// @Failure 500
+// @Failure default
+// @Router /export/films [get]
+func (h *ExportHandler) exportFilms(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseFilmQuery(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong query params", http.StatusBadRequest)
+		return
+	}
+	q.UserID = req.Context().Value("userID").(int64)
+
+	h.export(w, req, "films", func(format string, out io.Writer) error {
+		return h.ser.Export.ExportFilms(q, req.URL.Query().Get("withCredits") == "true", format, out)
+	})
+}
+
+// @Summary Export actors
+// @Security ApiKeyAuth
+// @Tags export
+// @Description Export all actors in order of IDs. You must have admin role.
+// @Description Actors are written as they are read from the database. Credits go to the last CSV column as a JSON array.
+// @Description A CSV or NDJSON export can be imported back as new actors: id and credits are skipped by imports.
+// @ID export-actors
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  json
+// @Param format query string false "Format of the file, csv by default" Enums(csv,ndjson,json)
+// @Param withCredits query boolean false "Include the films of every actor"
+// @Success 200 {array} domain.ExportActor
+// @Failure 400
+// @Failure 500
+// @Failure default
+// @Router /export/actors [get]
+func (h *ExportHandler) exportActors(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	h.export(w, req, "actors", func(format string, out io.Writer) error {
+		return h.ser.Export.ExportActors(req.URL.Query().Get("withCredits") == "true", format, out)
+	})
+}
+
+// export streams a file named name in the requested format. An error after
+// the file has begun can only be logged.
+func (h *ExportHandler) export(w http.ResponseWriter, req *http.Request, name string, fn func(format string, out io.Writer) error) {
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = domain.ExportCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		newErrorResponse(w, fmt.Errorf("can't export to %q", format), "Wrong query params", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	out := &exportWriter{w: w}
+	if err := fn(format, out); err != nil {
+		if out.written {
+			log.Printf("Export of %s failed: %s", name, err.Error())
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		newErrorResponse(w, err, fmt.Sprintf("Can't export %s", name), http.StatusInternalServerError)
+	}
+}
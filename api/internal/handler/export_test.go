@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExportHandler_exportFilms(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		query                domain.FilmQuery
+		withCredits          bool
+		format               string
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "?yearFrom=1990",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {
+				r.EXPECT().ExportFilms(q, withCredits, format, gomock.Any()).DoAndReturn(
+					func(q domain.FilmQuery, withCredits bool, format string, w io.Writer) error {
+						_, err := io.WriteString(w, "id,title,year\n6,Брат,1997\n")
+						return err
+					})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.FilmQuery{YearFrom: 1990, UserID: 10},
+			format:               domain.ExportCSV,
+			expectedStatusCode:   200,
+			expectedContentType:  "text/csv; charset=utf-8",
+			expectedResponseBody: "id,title,year\n6,Брат,1997\n",
+		},
+		{
+			name:     "Ok ndjson with credits",
+			addToUrl: "?format=ndjson&withCredits=true&genre=drama",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {
+				r.EXPECT().ExportFilms(q, withCredits, format, gomock.Any()).DoAndReturn(
+					func(q domain.FilmQuery, withCredits bool, format string, w io.Writer) error {
+						_, err := io.WriteString(w, `{"id":6,"credits":[{"actorId":1,"type":"lead"}]}`+"\n")
+						return err
+					})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.FilmQuery{Genres: []string{"drama"}, UserID: 10},
+			withCredits:          true,
+			format:               domain.ExportNDJSON,
+			expectedStatusCode:   200,
+			expectedContentType:  "application/x-ndjson",
+			expectedResponseBody: `{"id":6,"credits":[{"actorId":1,"type":"lead"}]}` + "\n",
+		},
+		{
+			name:         "Wrong format",
+			addToUrl:     "?format=xml",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: `{"message":"Wrong query params"}` + "\n",
+		},
+		{
+			name:         "Wrong filter",
+			addToUrl:     "?yearFrom=new",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: `{"message":"Wrong query params"}` + "\n",
+		},
+		{
+			name:         "Not admin",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: `{"message":"you don't have enough permissions"}` + "\n",
+		},
+		{
+			name:     "Can't export",
+			addToUrl: "?format=json",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {
+				r.EXPECT().ExportFilms(q, withCredits, format, gomock.Any()).Return(errors.New("connection refused"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.FilmQuery{UserID: 10},
+			format:               domain.ExportJSON,
+			expectedStatusCode:   500,
+			expectedContentType:  "text/plain; charset=utf-8",
+			expectedResponseBody: `{"message":"Can't export films"}` + "\n",
+		},
+		{
+			name:     "Failed after start",
+			addToUrl: "?format=json",
+			mockBehavior: func(r *mock_service.MockExport, q domain.FilmQuery, withCredits bool, format string) {
+				r.EXPECT().ExportFilms(q, withCredits, format, gomock.Any()).DoAndReturn(
+					func(q domain.FilmQuery, withCredits bool, format string, w io.Writer) error {
+						io.WriteString(w, `[{"id":6}`)
+						return errors.New("connection reset")
+					})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			query:                domain.FilmQuery{UserID: 10},
+			format:               domain.ExportJSON,
+			expectedStatusCode:   200,
+			expectedContentType:  "application/json",
+			expectedResponseBody: `[{"id":6}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockExport(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.query, test.withCredits, test.format)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Export: repo, User: repo2}
+			handler := ExportHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /export/films", middlewareLog(http.HandlerFunc(handler.exportFilms)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/export/films%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.Equal(t, w.Header().Get("Content-Type"), test.expectedContentType)
+			assert.Equal(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestExportHandler_exportActors(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockExport, withCredits bool, format string)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		withCredits          bool
+		format               string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "?format=json&withCredits=true",
+			mockBehavior: func(r *mock_service.MockExport, withCredits bool, format string) {
+				r.EXPECT().ExportActors(withCredits, format, gomock.Any()).DoAndReturn(
+					func(withCredits bool, format string, w io.Writer) error {
+						_, err := io.WriteString(w, `[{"id":1,"credits":[{"filmId":6,"type":"lead"}]}]`)
+						return err
+					})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			withCredits:          true,
+			format:               domain.ExportJSON,
+			expectedStatusCode:   200,
+			expectedResponseBody: `[{"id":1,"credits":[{"filmId":6,"type":"lead"}]}]`,
+		},
+		{
+			name:         "Not admin",
+			mockBehavior: func(r *mock_service.MockExport, withCredits bool, format string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockExport(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.withCredits, test.format)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Export: repo, User: repo2}
+			handler := ExportHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /export/actors", middlewareLog(http.HandlerFunc(handler.exportActors)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/export/actors%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"kinoteka/internal/service"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type FilmHandler struct {
@@ -17,103 +19,147 @@ type FilmHandler struct {
 // @Summary Get List of films
 // @Security ApiKeyAuth
 // @Tags films
-// @Description get list of films. You can search by title, actor name, surname
+// @Description get list of films. All filters can be combined.
+// @Description If nothing matches title or actor exactly, films with a similar title or actor are returned
+// @Description with the similarity of every film and "fuzzy": true. They are ordered by similarity unless orderBy is set.
 // @ID get-list-films
 // @Accept  json
 // @Produce  json
 // @Param title query string false "Search by title"
+// @Param actor query string false "Search by actor name, surname or patronymic"
+// @Param yearFrom query int false "Minimal year"
+// @Param yearTo query int false "Maximal year"
+// @Param minRating query number false "Minimal user rating"
+// @Param maxRating query number false "Maximal user rating"
+// @Param hasRating query boolean false "Only films with or without user votes"
+// @Param minRuntime query int false "Minimal runtime in minutes"
+// @Param maxRuntime query int false "Maximal runtime in minutes"
+// @Param releasedFrom query string false "Released on or after the date, YYYY-MM-DD"
+// @Param releasedTo query string false "Released on or before the date, YYYY-MM-DD"
+// @Param country query string false "Production country, ISO 3166-1 alpha-2 code"
+// @Param language query string false "Original language, ISO 639 code"
+// @Param inWatchlist query boolean false "Only films which are or aren't in the watchlist of the user"
+// @Param genre query string false "Comma separated genre names"
+// @Param genreMode query string false "Film must have all (and) or any (or) of the genres" Enums(or,and)
 // @Param sort query string false "Sort list by desc or asc" Enums(desc,asc)
-// @Param orderBy query string false "sort by params" Enums(rating,title,year)
-// @Param actor query string false "Search by actor"
-// @Success 200 {object} []domain.Film
-// @Success 210 {object} []domain.ActorFilm
+// @Param orderBy query string false "sort by params" Enums(rating,title,year,id,runtime,releaseDate)
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of films"
+// @Param If-None-Match header string false "ETag of the list the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the list the client has"
+// @Success 200 {object} domain.FilmPage
+// @Header 200 {string} ETag "Tag of the list"
+// @Header 200 {string} Last-Modified "Last change of the films or actors, not sent with inWatchlist"
+// @Success 304
 // @Failure 400
 // @Failure 500
 // @Failure default
 // @Router /film [get]
 func (a *FilmHandler) film(w http.ResponseWriter, req *http.Request) {
-	title := req.URL.Query().Get("title")
-	orderBy := req.URL.Query().Get("orderBy")
-	actor := req.URL.Query().Get("actor")
-	desc := false
-	if req.URL.Query().Get("sort") == "desc" {
-		desc = true
-	}
-	var jsonData []byte
-
-	if title != "" && orderBy != "" {
-		jsonData = a.getFilmsSortLike(w, req, orderBy, title, desc)
-	} else if title != "" {
-		jsonData = a.getFilmsLike(w, req, title)
-	} else if actor != "" {
-		jsonData = a.getFilmActor(w, req, actor)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(210)
-	} else {
-		jsonData = a.getFilmsSort(w, req, orderBy, desc)
-	}
-
-	if jsonData != nil {
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, string(jsonData))
-	}
-}
-
-func (a *FilmHandler) getFilmsSortLike(w http.ResponseWriter, req *http.Request, orderBy, title string, desc bool) []byte {
-	films, err := a.ser.Film.GetFilmsSortLike(orderBy, title, desc)
-	if err != nil {
-		newErrorResponse(w, err, "Can't get sort films", http.StatusBadRequest)
-		return nil
-	}
-
-	jsonData, err := json.Marshal(films)
+	q, err := parseFilmQuery(req)
 	if err != nil {
-		newErrorResponse(w, err, "Can't parse films to json", http.StatusInternalServerError)
-		return nil
+		newErrorResponse(w, err, "Wrong query params", http.StatusBadRequest)
+		return
 	}
-	return jsonData
-}
+	q.UserID = req.Context().Value("userID").(int64)
 
-func (a *FilmHandler) getFilmsLike(w http.ResponseWriter, req *http.Request, title string) []byte {
-	films, err := a.ser.Film.GetFilmsLike(title)
+	films, err := a.ser.Film.GetFilms(q)
 	if err != nil {
 		newErrorResponse(w, err, "Can't get films", http.StatusBadRequest)
-		return nil
+		return
 	}
 
 	jsonData, err := json.Marshal(films)
 	if err != nil {
 		newErrorResponse(w, err, "Can't parse films to json", http.StatusInternalServerError)
-		return nil
+		return
 	}
 
-	return jsonData
+	if notModified(w, req, etag(0, jsonData), films.UpdatedAt) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
 }
 
-func (a *FilmHandler) getFilmsSort(w http.ResponseWriter, req *http.Request, orderBy string, desc bool) []byte {
-	films, err := a.ser.Film.GetFilmsSort(orderBy, desc)
-	if err != nil {
-		newErrorResponse(w, err, "Can't get films", http.StatusBadRequest)
-		return nil
+func parseFilmQuery(req *http.Request) (domain.FilmQuery, error) {
+	query := req.URL.Query()
+	q := domain.FilmQuery{
+		Title:    query.Get("title"),
+		Actor:    query.Get("actor"),
+		Country:  query.Get("country"),
+		Language: query.Get("language"),
+		OrderBy:  query.Get("orderBy"),
+		Desc:     query.Get("sort") == "desc",
 	}
 
-	jsonData, err := json.Marshal(films)
-	if err != nil {
-		newErrorResponse(w, err, "Can't parse films to json", http.StatusInternalServerError)
-		return nil
+	if genres := query.Get("genre"); genres != "" {
+		for _, genre := range strings.Split(genres, ",") {
+			if genre = strings.TrimSpace(genre); genre != "" {
+				q.Genres = append(q.Genres, genre)
+			}
+		}
+	}
+	switch query.Get("genreMode") {
+	case "", "or":
+	case "and":
+		q.AllGenres = true
+	default:
+		return q, errors.New("genreMode must be and or or")
+	}
+
+	var err error
+	if q.YearFrom, err = queryInt(query, "yearFrom"); err != nil {
+		return q, err
+	}
+	if q.YearTo, err = queryInt(query, "yearTo"); err != nil {
+		return q, err
+	}
+	if q.MinRating, err = queryFloat(query, "minRating"); err != nil {
+		return q, err
+	}
+	if q.MaxRating, err = queryFloat(query, "maxRating"); err != nil {
+		return q, err
+	}
+	if q.HasRating, err = queryBool(query, "hasRating"); err != nil {
+		return q, err
+	}
+	if q.InWatchlist, err = queryBool(query, "inWatchlist"); err != nil {
+		return q, err
+	}
+	if q.MinRuntime, err = queryInt(query, "minRuntime"); err != nil {
+		return q, err
+	}
+	if q.MaxRuntime, err = queryInt(query, "maxRuntime"); err != nil {
+		return q, err
+	}
+	if q.ReleasedFrom, err = queryDate(query, "releasedFrom"); err != nil {
+		return q, err
+	}
+	if q.ReleasedTo, err = queryDate(query, "releasedTo"); err != nil {
+		return q, err
+	}
+	if q.Page, err = parsePageRequest(req); err != nil {
+		return q, err
 	}
 
-	return jsonData
+	return q, nil
 }
 
 // @Summary Get Film by ID
 // @Security ApiKeyAuth
 // @Tags films
-// @Description Get Film by ID
+// @Description Get Film by ID with its genres and cast. Cast is ordered by billing.
 // @ID get-film-by-id
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} domain.Actor
+// @Param If-None-Match header string false "ETag of the film the client has"
+// @Param If-Modified-Since header string false "Last-Modified of the film the client has"
+// @Success 200 {object} domain.Film
+// @Header 200 {string} ETag "Version and tag of the film, to be sent as If-Match"
+// @Header 200 {string} Last-Modified "Last change of the film or its cast"
+// @Success 304
 // @Failure 400
 // @Failure default
 // @Router /film/{id} [GET]
@@ -124,7 +170,7 @@ func (a *FilmHandler) getFilm(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	films, err := a.ser.Film.GetFilm(id)
+	films, err := a.ser.Film.GetFilm(id, req.Context().Value("userID").(int64))
 	if err != nil {
 		newErrorResponse(w, err, "Can't get film", http.StatusBadRequest)
 		return
@@ -136,19 +182,70 @@ func (a *FilmHandler) getFilm(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if notModified(w, req, etag(films.Version, jsonData), films.UpdatedAt) {
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, string(jsonData))
+	w.Write(jsonData)
+}
+
+// @Summary Get actors of film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Get a page of the cast of film by ID with the parts the actors play. Actors go by billing unless another order is asked for.
+// @ID get-film-actors
+// @Accept  json
+// @Produce  json
+// @Param orderBy query string false "Sort by params" Enums(billing,name,birthday)
+// @Param sort query string false "Sort list by desc or asc" Enums(desc,asc)
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of actors"
+// @Success 200 {object} domain.CastPage
+// @Failure 400
+// @Failure default
+// @Router /film/{id}/actors [GET]
+func (a *FilmHandler) getFilmActors(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	q, err := parseCreditQuery(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong page params", http.StatusBadRequest)
+		return
+	}
+
+	cast, err := a.ser.Film.GetFilmActors(id, q)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get actors of film", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(cast)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse actors to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
 }
 
 // @Summary Create Film
 // @Security ApiKeyAuth
 // @Tags films
-// @Description Create Film. You must have admin role.
+// @Description Create Film. Admins set editorialRating, rating and votes are made up from user votes. You must have admin role.
+// @Description The created film is returned with its ID, and Location tells where to get it.
 // @ID create-film
 // @Accept  json
 // @Produce  json
 // @Param input body domain.Film true "Film"
-// @Success 201
+// @Success 201 {object} domain.Film
+// @Header 201 {string} Location "Path of the created film, like /film/1"
+// @Header 201 {string} ETag "Version and tag of the created film"
 // @Failure 400
 // @Failure 500
 // @Failure default
@@ -170,25 +267,39 @@ func (a *FilmHandler) createFilm(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = a.ser.Film.CreateFilm(film)
+	film, err = a.ser.Film.CreateFilm(editor(req), film)
 	if err != nil {
 		newErrorResponse(w, err, "Can't create film", http.StatusBadRequest)
 		return
 	}
 
+	jsonData, err := json.Marshal(film)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse film to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/film/%d", film.ID))
+	w.Header().Set("ETag", etag(film.Version, jsonData))
 	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonData)
 }
 
 // @Summary Update Film by ID
 // @Security ApiKeyAuth
 // @Tags films
-// @Description Update Film by ID. You must have admin role.
+// @Description Update Film by ID. Admins set editorialRating, rating and votes are made up from user votes. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.
 // @ID update-film-by-id
 // @Accept  json
 // @Produce  json
 // @Param input body domain.Film true "Film"
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
 // @Success 201
 // @Failure 400
+// @Failure 412
+// @Failure 428
 // @Failure default
 // @Router /film/{id} [PUT]
 func (a *FilmHandler) updateFilm(w http.ResponseWriter, req *http.Request) {
@@ -214,26 +325,182 @@ func (a *FilmHandler) updateFilm(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 	film.ID = id
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
 
-	err = a.ser.Film.UpdateFilm(film)
+	err = a.ser.Film.UpdateFilm(editor(req), film, versions)
 	if err != nil {
-		newErrorResponse(w, err, "Can't update film", http.StatusBadRequest)
+		newVersionErrorResponse(w, err, "Can't update film")
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
 }
 
+// @Summary Patch Film by ID
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Change some fields of Film by ID with a JSON merge patch (RFC 7396) sent as application/merge-patch+json. Fields left out are kept, null clears a field, nullable fields like information are replaced as a whole, valid unless Valid is given. Members the film has no field for, and read-only ones like id, rating or cast, are refused. The patched film must be valid. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.
+// @ID patch-film-by-id
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param input body domain.Film true "Fields of the film to change"
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
+// @Success 200 {object} domain.Film
+// @Header 200 {string} ETag "Version and tag of the patched film"
+// @Failure 400
+// @Failure 412
+// @Failure 415
+// @Failure 428
+// @Failure default
+// @Router /film/{id} [PATCH]
+func (a *FilmHandler) patchFilm(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	patch, err := readMergePatch(req)
+	if err != nil {
+		newPatchErrorResponse(w, err, "Can't parse film patch from json")
+		return
+	}
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	var film domain.Film
+	film, err = a.ser.Film.PatchFilm(editor(req), id, versions, patch)
+	if err != nil {
+		newVersionErrorResponse(w, err, "Can't patch film")
+		return
+	}
+
+	jsonData, err := json.Marshal(film)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse film to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", etag(film.Version, jsonData))
+	w.Write(jsonData)
+}
+
+// @Summary Get revisions of Film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Get the revisions of Film by ID, the latest first. Every update saves one, the first is how the film was before its first update. Each revision tells the fields which changed since the revision before it.
+// @ID get-film-revisions
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 200 {array} domain.FilmRevision
+// @Failure 400
+// @Failure default
+// @Router /film/{id}/revisions [GET]
+func (a *FilmHandler) getFilmRevisions(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var revisions []domain.FilmRevision
+	revisions, err = a.ser.Film.GetFilmRevisions(id)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get revisions of film", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(revisions)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse revisions to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Revert Film to revision
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Bring Film back to the revision. The film as it becomes is saved as a new revision. You must have admin role.
+// @ID revert-film
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param rev path int true "Revision"
+// @Success 204
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /film/{id}/revisions/{rev}/revert [POST]
+func (a *FilmHandler) revertFilm(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+	rev, err := strconv.ParseInt(req.PathValue("rev"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse revision from path", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.ser.Film.RevertFilm(editor(req), id, rev); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			newErrorResponse(w, err, "Revision doesn't exist", http.StatusNotFound)
+			return
+		}
+		newErrorResponse(w, err, "Can't revert film", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Delete Film by ID
 // @Security ApiKeyAuth
 // @Tags films
-// @Description Delete Film by ID. You must have admin role.
+// @Description Move Film to the trash. Its cast, genres and votes are kept until it is purged; it is taken off watchlists and favorites. You must have admin role.
+// @Description With the ETag of GET sent as If-Match, the change is refused with 412 if the film was changed since it was read. The server may require If-Match, answering 428 without it.
 // @ID delete-film-by-id
 // @Accept  json
 // @Produce  json
 // @Param input body domain.Film true "Film"
+// @Param If-Match header string false "ETags of the versions which may be changed, weak ones never match"
 // @Success 201
 // @Failure 400
+// @Failure 412
+// @Failure 428
 // @Failure default
 // @Router /film/{id} [DELETE]
 func (a *FilmHandler) deleteFilm(w http.ResponseWriter, req *http.Request) {
@@ -253,44 +520,80 @@ func (a *FilmHandler) deleteFilm(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if err := a.ser.Film.DeleteFilm(id); err != nil {
-		newErrorResponse(w, err, "Can't delete film", http.StatusBadRequest)
+	versions, err := ifMatch(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong If-Match header", http.StatusBadRequest)
+		return
+	}
+
+	if err := a.ser.Film.DeleteFilm(editor(req), id, versions); err != nil {
+		newVersionErrorResponse(w, err, "Can't delete film")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (a *FilmHandler) getFilmActor(w http.ResponseWriter, req *http.Request, actor string) []byte {
-	films, err := a.ser.Film.SearchFilmsWithActor(actor)
+// @Summary Restore Film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Take Film out of the trash with its cast. You must have admin role.
+// @ID restore-film
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Success 204
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /film/{id}/restore [POST]
+func (a *FilmHandler) restoreFilm(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
 	if err != nil {
-		newErrorResponse(w, err, "Can't get films with actor", http.StatusBadRequest)
-		return nil
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
 	}
 
-	jsonData, err := json.Marshal(films)
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
 	if err != nil {
-		newErrorResponse(w, err, "Can't parse film to json", http.StatusInternalServerError)
-		return nil
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
 	}
 
-	return jsonData
+	if err := a.ser.Film.RestoreFilm(editor(req), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			newErrorResponse(w, err, "Film isn't in the trash", http.StatusNotFound)
+			return
+		}
+		newErrorResponse(w, err, "Can't restore film", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
+// Data holds the actors to add to a film. Actors are added as supporting
+// cast without a character, credits tell what every actor plays.
 type Data struct {
-	Actors []int64 `json:"actors"`
+	Actors  []int64         `json:"actors"`
+	Credits []domain.Credit `json:"credits"`
 } // @name Data
 
 // @Summary Add actors to film by id
 // @Security ApiKeyAuth
 // @Tags films
 // @Description Add actors to film by id. You must have admin role.
+// @Description Credit type is one of lead, supporting, cameo, voice. Billing is the position in the credits.
+// @Description If an actor doesn't exist or is in the cast already, no actor is added and the errors tell why for each.
 // @ID add-actor-to-film-by-id
 // @Accept  json
 // @Produce  json
 // @Param input body Data true "Array of actor's id"
 // @Success 201
-// @Failure 400
+// @Failure 400 {object} CastErrorResponse
 // @Failure default
 // @Router /film/{id} [POST]
 func (a *FilmHandler) addActorsToFilm(w http.ResponseWriter, req *http.Request) {
@@ -314,16 +617,264 @@ func (a *FilmHandler) addActorsToFilm(w http.ResponseWriter, req *http.Request)
 		newErrorResponse(w, err, "Can't parse data from json", http.StatusBadRequest)
 		return
 	}
-	if data.Actors == nil {
-		newErrorResponse(w, errors.New("data.Actors is nil"), "Wrong input form", http.StatusBadRequest)
+	if data.Actors == nil && data.Credits == nil {
+		newErrorResponse(w, errors.New("data.Actors and data.Credits are nil"), "Wrong input form", http.StatusBadRequest)
 		return
 	}
 
-	err = a.ser.Film.AddActorToFilm(id, data.Actors)
+	credits := data.Credits
+	for _, actorId := range data.Actors {
+		credits = append(credits, domain.Credit{ActorID: actorId})
+	}
+
+	err = a.ser.Film.AddActorToFilm(editor(req), id, credits)
 	if err != nil {
-		newErrorResponse(w, err, "Can't add actor to film", http.StatusBadRequest)
+		newCreditErrorResponse(w, err, "Can't add actor to film")
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// @Summary Replace actors of film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Replace the whole cast of film by ID. You must have admin role.
+// @Description If an actor doesn't exist or is credited twice, the cast is left as it was and the errors tell why for each.
+// @ID replace-film-actors
+// @Accept  json
+// @Produce  json
+// @Param input body Data true "Cast"
+// @Success 204
+// @Failure 400 {object} CastErrorResponse
+// @Failure default
+// @Router /film/{id}/actors [PUT]
+func (a *FilmHandler) replaceFilmActors(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var data Data
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		newErrorResponse(w, err, "Can't parse data from json", http.StatusBadRequest)
+		return
+	}
+	if data.Actors == nil && data.Credits == nil {
+		newErrorResponse(w, errors.New("data.Actors and data.Credits are nil"), "Wrong input form", http.StatusBadRequest)
+		return
+	}
+
+	credits := data.Credits
+	for _, actorId := range data.Actors {
+		credits = append(credits, domain.Credit{ActorID: actorId})
+	}
+
+	err = a.ser.Film.ReplaceCast(editor(req), id, credits)
+	if err != nil {
+		newCreditErrorResponse(w, err, "Can't replace actors of film")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Remove actor from film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Remove actor from the cast of film by ID. You must have admin role.
+// @ID remove-actor-from-film
+// @Accept  json
+// @Produce  json
+// @Success 204
+// @Failure 400
+// @Failure 404 {object} CastErrorResponse
+// @Failure default
+// @Router /film/{id}/actors/{actorId} [DELETE]
+func (a *FilmHandler) removeActorFromFilm(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+	actorId, err := strconv.ParseInt(req.PathValue("actorId"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	err = a.ser.Film.RemoveActorFromFilm(editor(req), id, actorId)
+	if err != nil {
+		newCreditErrorResponse(w, err, "Can't remove actor from film")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type GenresData struct {
+	Genres []int64 `json:"genres"`
+} // @name GenresData
+
+// @Summary Add genres to film by id
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Add genres to film by id. You must have admin role.
+// @ID add-genre-to-film-by-id
+// @Accept  json
+// @Produce  json
+// @Param input body GenresData true "Array of genre's id"
+// @Success 204
+// @Failure 400
+// @Failure default
+// @Router /film/{id}/genres [POST]
+func (a *FilmHandler) addGenresToFilm(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var data GenresData
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		newErrorResponse(w, err, "Can't parse data from json", http.StatusBadRequest)
+		return
+	}
+	if data.Genres == nil {
+		newErrorResponse(w, errors.New("data.Genres is nil"), "Wrong input form", http.StatusBadRequest)
+		return
+	}
+
+	err = a.ser.Film.AddGenreToFilm(editor(req), id, data.Genres)
+	if err != nil {
+		newErrorResponse(w, err, "Can't add genre to film", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Upload Poster
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Upload poster of film by ID as JPEG, PNG, GIF or WebP. Thumbnails are made from it. The version of the film grows, so ETags read before the upload no longer match. You must have admin role.
+// @ID upload-film-poster
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param image formData file true "Image"
+// @Success 201 {object} domain.Image
+// @Failure 400
+// @Failure 413
+// @Failure 415
+// @Failure default
+// @Router /film/{id}/poster [POST]
+func (a *FilmHandler) uploadPoster(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := a.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	data, err := readUpload(w, req, "image", a.ser.Config.MaxImageSize)
+	if err != nil {
+		newImageErrorResponse(w, err, "Can't read image from form")
+		return
+	}
+
+	poster, err := a.ser.Film.SetPoster(editor(req), id, data)
+	if err != nil {
+		newImageErrorResponse(w, err, "Can't upload poster")
+		return
+	}
+
+	jsonData, err := json.Marshal(poster)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse poster to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonData)
+}
+
+// @Summary Rate film
+// @Security ApiKeyAuth
+// @Tags films
+// @Description Rate film by ID from 0 to 10. A user has one vote per film, rating again replaces it.
+// @ID rate-film
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param input body domain.Vote true "Vote"
+// @Success 200 {object} domain.FilmRating
+// @Failure 400
+// @Failure default
+// @Router /film/{id}/rating [PUT]
+func (a *FilmHandler) rateFilm(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var vote domain.Vote
+	if err := json.NewDecoder(req.Body).Decode(&vote); err != nil {
+		newErrorResponse(w, err, "Can't parse rating from json", http.StatusBadRequest)
+		return
+	}
+
+	rating, err := a.ser.Film.RateFilm(id, req.Context().Value("userID").(int64), vote)
+	if err != nil {
+		newErrorResponse(w, err, "Can't rate film", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(rating)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse rating to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
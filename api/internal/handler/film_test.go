@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
@@ -11,395 +12,396 @@ import (
 	"kinoteka/internal/domain"
 	"kinoteka/internal/service"
 	mock_service "kinoteka/internal/service/mocks"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 )
 
 func TestFilmHandler_film(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockFilm, s1, s2, s3 string, b bool)
+	type mockBehavior func(r *mock_service.MockFilm, q domain.FilmQuery)
+
+	rating := 8.0
+	hasRating := true
+	inWatchlist := true
 
 	tests := []struct {
 		name                 string
 		addToUrl             string
 		mockBehavior         mockBehavior
-		titleParam           string
-		actorParam           string
-		orderByParam         string
-		descParam            bool
+		query                domain.FilmQuery
+		ifNoneMatch          string
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedETag         string
 	}{
 		{
 			name:     "Ok",
 			addToUrl: ``,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsSort(orderByParam, descParam).Return([]domain.Film{
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:          10,
+							Title:       "Человек-паук 3",
+							Year:        2007,
+							Information: sql.NullString{String: "2:19", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+						},
 					},
 				}, nil)
 			},
+			query:              domain.FilmQuery{},
 			expectedStatusCode: 200,
-			titleParam:         "",
-			actorParam:         "",
-			orderByParam:       "",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 10,
+            "title": "Человек-паук 3",
+            "year": 2007,
+            "information": {
+                "String": "2:19",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 8.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
         }
-    }
-]`,
+    ]
+}`,
 		},
 		{
 			name:     "Ok with title",
 			addToUrl: `?title=паук`,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsLike(titleParam).Return([]domain.Film{
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:          10,
+							Title:       "Человек-паук 3",
+							Year:        2007,
+							Information: sql.NullString{String: "2:19", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+						},
 					},
 				}, nil)
 			},
+			query:              domain.FilmQuery{Title: "паук"},
 			expectedStatusCode: 200,
-			titleParam:         "паук",
-			actorParam:         "",
-			orderByParam:       "",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 10,
+            "title": "Человек-паук 3",
+            "year": 2007,
+            "information": {
+                "String": "2:19",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 8.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
         }
-    }
-]`,
+    ]
+}`,
 		},
 		{
-			name:     "Ok with title and order by title",
-			addToUrl: `?title=паук&orderBy=title`,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsSortLike(orderByParam, titleParam, descParam).Return([]domain.Film{
-					{
-						ID:          8,
-						Title:       "Человек-паук",
-						Year:        2002,
-						Information: sql.NullString{String: "2:01", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.3, Valid: true},
-					},
-					{
-						ID:          9,
-						Title:       "Человек-паук 2",
-						Year:        2004,
-						Information: sql.NullString{String: "2:07", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.2, Valid: true},
-					},
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+			name:     "Ok with title, actor and order by year",
+			addToUrl: `?title=паук&actor=магуайр&orderBy=year&sort=desc&limit=2`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:          10,
+							Title:       "Человек-паук 3",
+							Year:        2007,
+							Information: sql.NullString{String: "2:19", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+						},
+						{
+							ID:          9,
+							Title:       "Человек-паук 2",
+							Year:        2004,
+							Information: sql.NullString{String: "2:07", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 8.2, Valid: true},
+						},
 					},
+					NextCursor: "eyJvIjoieWVhciIsImQiOnRydWUsInYiOiIyMDA0IiwiaWQiOjl9",
 				}, nil)
 			},
+			query: domain.FilmQuery{
+				Title:   "паук",
+				Actor:   "магуайр",
+				OrderBy: "year",
+				Desc:    true,
+				Page:    domain.PageRequest{Limit: 2},
+			},
 			expectedStatusCode: 200,
-			titleParam:         "паук",
-			actorParam:         "",
-			orderByParam:       "title",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 8,
-        "title": "Человек-паук",
-        "year": 2002,
-        "information": {
-            "String": "2:01",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.3,
-            "Valid": true
-        }
-    },
-    {
-        "id": 9,
-        "title": "Человек-паук 2",
-        "year": 2004,
-        "information": {
-            "String": "2:07",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.2,
-            "Valid": true
-        }
-    },
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 10,
+            "title": "Человек-паук 3",
+            "year": 2007,
+            "information": {
+                "String": "2:19",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 8.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
         },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
+        {
+            "id": 9,
+            "title": "Человек-паук 2",
+            "year": 2004,
+            "information": {
+                "String": "2:07",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 8.2,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
         }
-    }
-]`,
+    ],
+    "nextCursor": "eyJvIjoieWVhciIsImQiOnRydWUsInYiOiIyMDA0IiwiaWQiOjl9"
+}`,
 		},
 		{
-			name:     "Ok with title and order by rating",
-			addToUrl: `?title=паук&orderBy=title`,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsSortLike(orderByParam, titleParam, descParam).Return([]domain.Film{
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
-					},
-					{
-						ID:          9,
-						Title:       "Человек-паук 2",
-						Year:        2004,
-						Information: sql.NullString{String: "2:07", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.2, Valid: true},
-					},
-					{
-						ID:          8,
-						Title:       "Человек-паук",
-						Year:        2002,
-						Information: sql.NullString{String: "2:01", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.3, Valid: true},
+			name:     "Ok with ranges",
+			addToUrl: `?yearFrom=2000&yearTo=2005&minRating=8&hasRating=true&withTotal=true`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				total := int64(0)
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{Films: []domain.Film{}, Total: &total}, nil)
+			},
+			query: domain.FilmQuery{
+				YearFrom:  2000,
+				YearTo:    2005,
+				MinRating: &rating,
+				HasRating: &hasRating,
+				Page:      domain.PageRequest{WithTotal: true},
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"films": [], "total": 0}`,
+		},
+		{
+			name:     "Ok with metadata",
+			addToUrl: `?minRuntime=120&releasedFrom=1999-01-01&country=us&language=en&orderBy=runtime`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:               2,
+							Title:            "Бойцовский клуб",
+							Year:             1999,
+							Rating:           sql.NullFloat64{Float64: 9.1, Valid: true},
+							RuntimeMinutes:   sql.NullInt64{Int64: 139, Valid: true},
+							ReleaseDate:      sql.NullTime{Time: time.Date(1999, 9, 10, 0, 0, 0, 0, time.UTC), Valid: true},
+							Countries:        []string{"US", "DE"},
+							OriginalLanguage: sql.NullString{String: "en", Valid: true},
+						},
 					},
 				}, nil)
 			},
+			query: domain.FilmQuery{
+				MinRuntime:   120,
+				ReleasedFrom: time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC),
+				Country:      "us",
+				Language:     "en",
+				OrderBy:      "runtime",
+			},
 			expectedStatusCode: 200,
-			titleParam:         "паук",
-			actorParam:         "",
-			orderByParam:       "title",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
-        }
-    },
-    {
-        "id": 9,
-        "title": "Человек-паук 2",
-        "year": 2004,
-        "information": {
-            "String": "2:07",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.2,
-            "Valid": true
-        }
-    },
-    {
-        "id": 8,
-        "title": "Человек-паук",
-        "year": 2002,
-        "information": {
-            "String": "2:01",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.3,
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 2,
+            "title": "Бойцовский клуб",
+            "year": 1999,
+            "information": {
+                "String": "",
+                "Valid": false
+            },
+            "rating": {
+                "Float64": 9.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 139, "Valid": true},
+            "releaseDate": {"Time": "1999-09-10T00:00:00Z", "Valid": true},
+            "countries": ["US", "DE"],
+            "originalLanguage": {"String": "en", "Valid": true}
         }
-    }
-]`,
+    ]
+}`,
 		},
 		{
-			name:     "Wrong order by With title ",
-			addToUrl: `?title=паук&orderBy=titl`,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsSortLike(orderByParam, titleParam, descParam).Return([]domain.Film{
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
-					},
-					{
-						ID:          9,
-						Title:       "Человек-паук 2",
-						Year:        2004,
-						Information: sql.NullString{String: "2:07", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.2, Valid: true},
-					},
-					{
-						ID:          8,
-						Title:       "Человек-паук",
-						Year:        2002,
-						Information: sql.NullString{String: "2:01", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.3, Valid: true},
+			name:     "Ok in watchlist",
+			addToUrl: `?inWatchlist=true`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{Films: []domain.Film{}}, nil)
+			},
+			query:                domain.FilmQuery{InWatchlist: &inWatchlist},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"films": []}`,
+		},
+		{
+			name:     "Ok with fuzzy title",
+			addToUrl: `?title=` + url.QueryEscape("Бойцовкий клуб"),
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				similarity := 0.6666667
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:          2,
+							Title:       "Бойцовский клуб",
+							Year:        1999,
+							Information: sql.NullString{String: "02:19", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
+							Similarity:  &similarity,
+						},
 					},
+					Fuzzy: true,
 				}, nil)
 			},
+			query:              domain.FilmQuery{Title: "Бойцовкий клуб"},
 			expectedStatusCode: 200,
-			titleParam:         "паук",
-			actorParam:         "",
-			orderByParam:       "titl",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
-        }
-    },
-    {
-        "id": 9,
-        "title": "Человек-паук 2",
-        "year": 2004,
-        "information": {
-            "String": "2:07",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.2,
-            "Valid": true
-        }
-    },
-    {
-        "id": 8,
-        "title": "Человек-паук",
-        "year": 2002,
-        "information": {
-            "String": "2:01",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.3,
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 2,
+            "title": "Бойцовский клуб",
+            "year": 1999,
+            "information": {
+                "String": "02:19",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 9.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false},
+            "similarity": 0.6666667
         }
-    }
-]`,
+    ],
+    "fuzzy": true
+}`,
 		},
 		{
-			name:     "Wrong order by Without title",
-			addToUrl: `?orderBy=titl`,
-			mockBehavior: func(r *mock_service.MockFilm, titleParam, actorParam, orderByParam string, descParam bool) {
-				r.EXPECT().GetFilmsSort(orderByParam, descParam).Return([]domain.Film{
-					{
-						ID:          10,
-						Title:       "Человек-паук 3",
-						Year:        2007,
-						Information: sql.NullString{String: "2:19", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
-					},
-					{
-						ID:          9,
-						Title:       "Человек-паук 2",
-						Year:        2004,
-						Information: sql.NullString{String: "2:07", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.2, Valid: true},
-					},
-					{
-						ID:          8,
-						Title:       "Человек-паук",
-						Year:        2002,
-						Information: sql.NullString{String: "2:01", Valid: true},
-						Rating:      sql.NullFloat64{Float64: 8.3, Valid: true},
+			name:     "Ok with all genres",
+			addToUrl: `?genre=` + url.QueryEscape("драма, криминал") + `&genreMode=and`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{
+						{
+							ID:          2,
+							Title:       "Бойцовский клуб",
+							Year:        1999,
+							Information: sql.NullString{String: "02:19", Valid: true},
+							Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
+						},
 					},
 				}, nil)
 			},
+			query:              domain.FilmQuery{Genres: []string{"драма", "криминал"}, AllGenres: true},
 			expectedStatusCode: 200,
-			titleParam:         "",
-			actorParam:         "",
-			orderByParam:       "titl",
-			descParam:          false,
-			expectedResponseBody: `[
-    {
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
-        }
-    },
-    {
-        "id": 9,
-        "title": "Человек-паук 2",
-        "year": 2004,
-        "information": {
-            "String": "2:07",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.2,
-            "Valid": true
-        }
-    },
-    {
-        "id": 8,
-        "title": "Человек-паук",
-        "year": 2002,
-        "information": {
-            "String": "2:01",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.3,
-            "Valid": true
+			expectedResponseBody: `{
+    "films": [
+        {
+            "id": 2,
+            "title": "Бойцовский клуб",
+            "year": 1999,
+            "information": {
+                "String": "02:19",
+                "Valid": true
+            },
+            "rating": {
+                "Float64": 9.1,
+                "Valid": true
+            },
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
         }
-    }
-]`,
+    ]
+}`,
+		},
+		{
+			name:                 "Wrong genre mode",
+			addToUrl:             `?genre=drama&genreMode=xor`,
+			mockBehavior:         func(r *mock_service.MockFilm, q domain.FilmQuery) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong query params"}`,
+		},
+		{
+			name:                 "Wrong year",
+			addToUrl:             `?yearFrom=двухтысячный`,
+			mockBehavior:         func(r *mock_service.MockFilm, q domain.FilmQuery) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong query params"}`,
+		},
+		{
+			name:        "Not modified",
+			addToUrl:    `?title=Брат`,
+			ifNoneMatch: `W/"0000000000000000", W/"d56545a538c41c72"`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{
+					Films: []domain.Film{{ID: 1, Title: "Брат", Year: 1997}},
+				}, nil)
+			},
+			query:                domain.FilmQuery{Title: "Брат"},
+			expectedStatusCode:   304,
+			expectedResponseBody: ``,
+			expectedETag:         `"d56545a538c41c72"`,
+		},
+		{
+			name:     "Can't get films",
+			addToUrl: `?orderBy=titl`,
+			mockBehavior: func(r *mock_service.MockFilm, q domain.FilmQuery) {
+				r.EXPECT().GetFilms(q).Return(domain.FilmPage{}, errors.New("cursor is not valid"))
+			},
+			query:                domain.FilmQuery{OrderBy: "titl"},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get films"}`,
 		},
 	}
 
@@ -411,7 +413,8 @@ func TestFilmHandler_film(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockFilm(c)
-			test.mockBehavior(repo, test.titleParam, test.actorParam, test.orderByParam, test.descParam)
+			test.query.UserID = 10
+			test.mockBehavior(repo, test.query)
 
 			services := &service.Service{Film: repo}
 			handler := FilmHandler{services}
@@ -422,14 +425,24 @@ func TestFilmHandler_film(t *testing.T) {
 			// Create Request
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
 			req := httptest.NewRequest("GET", url, nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
 			http.DefaultServeMux.ServeHTTP(w, req)
 
 			// Assert
 			assert.Equal(t, w.Code, test.expectedStatusCode)
-			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+			if test.expectedETag != "" {
+				assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
+			}
 		})
 	}
 }
@@ -438,57 +451,220 @@ func TestFilmHandler_getFilm(t *testing.T) {
 	// Init Test Table
 	type mockBehavior func(r *mock_service.MockFilm, id int64)
 
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC)
+	film := domain.Film{
+		ID:          10,
+		Title:       "Человек-паук 3",
+		Year:        2007,
+		Information: sql.NullString{String: "2:19", Valid: true},
+		Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
+		Version:     3,
+		UpdatedAt:   updatedAt,
+	}
+	filmJSON := `{
+        "id": 10,
+        "title": "Человек-паук 3",
+        "year": 2007,
+        "information": {
+            "String": "2:19",
+            "Valid": true
+        },
+        "rating": {
+            "Float64": 8.1,
+            "Valid": true
+        },
+        "votes": 0,
+        "editorialRating": {"Float64": 0, "Valid": false},
+        "runtimeMinutes": {"Int64": 0, "Valid": false},
+        "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+        "countries": null,
+        "originalLanguage": {"String": "", "Valid": false}
+}`
+
 	tests := []struct {
 		name                 string
 		addToUrl             string
+		ifNoneMatch          string
+		ifModifiedSince      string
 		mockBehavior         mockBehavior
 		ID                   int64
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedETag         string
+		expectedLastModified string
 	}{
 		{
 			name:     "Ok",
 			addToUrl: `/10`,
 			mockBehavior: func(r *mock_service.MockFilm, id int64) {
-				r.EXPECT().GetFilm(id).Return(domain.Film{
-					ID:          10,
-					Title:       "Человек-паук 3",
-					Year:        2007,
-					Information: sql.NullString{String: "2:19", Valid: true},
-					Rating:      sql.NullFloat64{Float64: 8.1, Valid: true},
-				}, nil)
+				r.EXPECT().GetFilm(id, int64(10)).Return(film, nil)
 			},
-			expectedStatusCode: 200,
-			ID:                 10,
-			expectedResponseBody: `{
-        "id": 10,
-        "title": "Человек-паук 3",
-        "year": 2007,
-        "information": {
-            "String": "2:19",
-            "Valid": true
-        },
-        "rating": {
-            "Float64": 8.1,
-            "Valid": true
-        }
-}`,
+			expectedStatusCode:   200,
+			ID:                   10,
+			expectedETag:         `"3-57cff1033cbfbeb9"`,
+			expectedLastModified: "Wed, 01 May 2024 10:00:00 GMT",
+			expectedResponseBody: filmJSON,
 		},
 		{
-			name:                 "Wrong type id",
-			addToUrl:             `/asd`,
-			mockBehavior:         func(r *mock_service.MockFilm, id int64) {},
-			expectedStatusCode:   400,
+			name:        "Not modified",
+			addToUrl:    `/10`,
+			ifNoneMatch: `"2-d2c1e0bb3ddc1da8", "3-57cff1033cbfbeb9"`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilm(id, int64(10)).Return(film, nil)
+			},
+			expectedStatusCode:   304,
 			ID:                   10,
-			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+			expectedETag:         `"3-57cff1033cbfbeb9"`,
+			expectedResponseBody: ``,
 		},
 		{
-			name:     "Out of range index",
-			addToUrl: `/111`,
+			name:        "Changed since tag",
+			addToUrl:    `/10`,
+			ifNoneMatch: `"2-d2c1e0bb3ddc1da8"`,
+			// If-Modified-Since is left out when If-None-Match is sent
+			ifModifiedSince: "Wed, 01 May 2024 10:00:00 GMT",
 			mockBehavior: func(r *mock_service.MockFilm, id int64) {
-				r.EXPECT().GetFilm(id).Return(domain.Film{}, errors.New(""))
+				r.EXPECT().GetFilm(id, int64(10)).Return(film, nil)
 			},
-			expectedStatusCode:   400,
+			expectedStatusCode:   200,
+			ID:                   10,
+			expectedETag:         `"3-57cff1033cbfbeb9"`,
+			expectedResponseBody: filmJSON,
+		},
+		{
+			name:            "Not modified since",
+			addToUrl:        `/10`,
+			ifModifiedSince: "Wed, 01 May 2024 10:00:00 GMT",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilm(id, int64(10)).Return(film, nil)
+			},
+			expectedStatusCode:   304,
+			ID:                   10,
+			expectedLastModified: "Wed, 01 May 2024 10:00:00 GMT",
+			expectedResponseBody: ``,
+		},
+		{
+			name:            "Modified since",
+			addToUrl:        `/10`,
+			ifModifiedSince: "Tue, 30 Apr 2024 10:00:00 GMT",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilm(id, int64(10)).Return(film, nil)
+			},
+			expectedStatusCode:   200,
+			ID:                   10,
+			expectedLastModified: "Wed, 01 May 2024 10:00:00 GMT",
+			expectedResponseBody: filmJSON,
+		},
+		{
+			name:     "Ok with user rating",
+			addToUrl: `/2`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				userRating := 9
+				r.EXPECT().GetFilm(id, int64(10)).Return(domain.Film{
+					ID:              2,
+					Title:           "Бойцовский клуб",
+					Year:            1999,
+					Rating:          sql.NullFloat64{Float64: 8.7, Valid: true},
+					Votes:           3,
+					EditorialRating: sql.NullFloat64{Float64: 9.1, Valid: true},
+					UserRating:      &userRating,
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			ID:                 2,
+			expectedResponseBody: `{
+    "id": 2,
+    "title": "Бойцовский клуб",
+    "year": 1999,
+    "information": {"String": "", "Valid": false},
+    "rating": {"Float64": 8.7, "Valid": true},
+    "votes": 3,
+    "editorialRating": {"Float64": 9.1, "Valid": true},
+    "userRating": 9,
+    "runtimeMinutes": {"Int64": 0, "Valid": false},
+    "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+    "countries": null,
+    "originalLanguage": {"String": "", "Valid": false}
+}`,
+		},
+		{
+			name:     "Ok with genres and cast",
+			addToUrl: `/6`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				birthday, _ := time.Parse(time.RFC3339, "1971-12-27T00:00:00Z")
+				r.EXPECT().GetFilm(id, int64(10)).Return(domain.Film{
+					ID:          6,
+					Title:       "Брат",
+					Year:        1997,
+					Information: sql.NullString{String: "1:40", Valid: true},
+					Rating:      sql.NullFloat64{Float64: 8.6, Valid: true},
+					Genres:      []domain.Genre{{ID: 1, Name: "драма"}},
+					Cast: []domain.CastMember{
+						{
+							Actor: domain.Actor{
+								ID:       12,
+								Name:     "Сергей",
+								Surname:  "Бодров",
+								Birthday: birthday,
+								Sex:      "m",
+							},
+							Part: domain.Part{Character: "Данила Багров", Billing: 1, Type: domain.CreditLead},
+						},
+					},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			ID:                 6,
+			expectedResponseBody: `{
+    "id": 6,
+    "title": "Брат",
+    "year": 1997,
+    "information": {
+        "String": "1:40",
+        "Valid": true
+    },
+    "rating": {
+        "Float64": 8.6,
+        "Valid": true
+    },
+    "votes": 0,
+    "editorialRating": {"Float64": 0, "Valid": false},
+    "runtimeMinutes": {"Int64": 0, "Valid": false},
+    "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+    "countries": null,
+    "originalLanguage": {"String": "", "Valid": false},
+    "genres": [{"id": 1, "name": "драма"}],
+    "cast": [
+        {
+            "id": 12,
+            "name": "Сергей",
+            "surname": "Бодров",
+            "patronymic": {"String": "", "Valid": false},
+            "birthday": "1971-12-27T00:00:00Z",
+            "sex": "m",
+            "information": {"String": "", "Valid": false},
+            "character": "Данила Багров",
+            "billing": 1,
+            "type": "lead"
+        }
+    ]
+}`,
+		},
+		{
+			name:                 "Wrong type id",
+			addToUrl:             `/asd`,
+			mockBehavior:         func(r *mock_service.MockFilm, id int64) {},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:     "Out of range index",
+			addToUrl: `/111`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilm(id, int64(10)).Return(domain.Film{}, errors.New(""))
+			},
+			expectedStatusCode:   400,
 			ID:                   111,
 			expectedResponseBody: `{"message":"Can't get film"}`,
 		},
@@ -513,14 +689,30 @@ func TestFilmHandler_getFilm(t *testing.T) {
 			// Create Request
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
 			req := httptest.NewRequest("GET", url, nil)
+			if test.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+			if test.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", test.ifModifiedSince)
+			}
+			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
 			http.DefaultServeMux.ServeHTTP(w, req)
 
 			// Assert
 			assert.Equal(t, w.Code, test.expectedStatusCode)
-			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+			if test.expectedETag != "" {
+				assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
+			}
+			if test.expectedLastModified != "" {
+				assert.Equal(t, test.expectedLastModified, w.Header().Get("Last-Modified"))
+			}
 		})
 	}
 }
@@ -539,6 +731,7 @@ func TestFilmHandler_createFilm(t *testing.T) {
 		ID                   int64
 		expectedStatusCode   int
 		expectedResponseBody string
+		expectedLocation     string
 	}{
 		{
 			name: "Ok",
@@ -561,14 +754,30 @@ func TestFilmHandler_createFilm(t *testing.T) {
 				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
 			},
 			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {
-				r.EXPECT().CreateFilm(film).Return(nil)
+				created := film
+				created.ID = 7
+				created.Version = 1
+				r.EXPECT().CreateFilm(domain.Editor{UserID: 10}, film).Return(created, nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   201,
-			ID:                   10,
-			expectedResponseBody: ``,
+			expectedStatusCode: 201,
+			ID:                 10,
+			expectedResponseBody: `{
+    "id": 7,
+    "title": "Бойцовский клуб",
+    "year": 1999,
+    "information": {"String": "02:19", "Valid": true},
+    "rating": {"Float64": 9.1, "Valid": true},
+    "votes": 0,
+    "editorialRating": {"Float64": 0, "Valid": false},
+    "runtimeMinutes": {"Int64": 0, "Valid": false},
+    "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+    "countries": null,
+    "originalLanguage": {"String": "", "Valid": false}
+}`,
+			expectedLocation: "/film/7",
 		},
 		{
 			name: "Wrong request",
@@ -587,7 +796,7 @@ func TestFilmHandler_createFilm(t *testing.T) {
 				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
 			},
 			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {
-				r.EXPECT().CreateFilm(film).Return(errors.New("film is not valid"))
+				r.EXPECT().CreateFilm(domain.Editor{UserID: 10}, film).Return(domain.Film{}, errors.New("film is not valid"))
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
@@ -616,13 +825,1596 @@ func TestFilmHandler_createFilm(t *testing.T) {
 				Information: sql.NullString{String: "02:19", Valid: true},
 				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
 			},
-			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {},
-			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
-				r.EXPECT().IsAdmin(id).Return(false, nil)
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.inputFilm)
+			test.mockBehaviorAdmin(repo2, test.ID)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film", middlewareLog(http.HandlerFunc(handler.createFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", test.ID)
+			req := httptest.NewRequest("POST", "/film",
+				bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+			assert.Equal(t, test.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestFilmHandler_updateFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		inputBody            string
+		inputFilm            domain.Film
+		ifMatch              string
+		versions             domain.Versions
+		ID                   int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1",
+			inputBody: `{
+    "title": "Бойцовский клуб",
+    "year": 1999,
+    "information": {
+        "String": "02:19",
+        "Valid": true
+    },
+    "rating": {
+        "Float64": 9.1,
+        "Valid": true
+    }
+}`,
+			inputFilm: domain.Film{
+				ID:          1,
+				Title:       "Бойцовский клуб",
+				Year:        1999,
+				Information: sql.NullString{String: "02:19", Valid: true},
+				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   201,
+			ID:                   10,
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Not admin",
+			addToUrl: "/1",
+			inputBody: `{
+    "title": "Бойцовский клуб",
+    "year": 1999,
+    "information": {
+        "String": "02:19",
+        "Valid": true
+    },
+    "rating": {
+        "Float64": 9.1,
+        "Valid": true
+    }
+}`,
+			inputFilm: domain.Film{
+				Title:       "Бойцовский клуб",
+				Year:        1999,
+				Information: sql.NullString{String: "02:19", Valid: true},
+				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:     "Wrong request",
+			addToUrl: "/1",
+			inputBody: `{
+    "information": {
+        "String": "02:19",
+        "Valid": true
+    },
+    "rating": {
+        "Float64": 9.1,
+        "Valid": true
+    }
+}`,
+			inputFilm: domain.Film{
+				ID:          1,
+				Information: sql.NullString{String: "02:19", Valid: true},
+				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(errors.New("film is not valid"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"Can't update film"}`,
+		},
+		{
+			name:      "Ok with If-Match",
+			addToUrl:  "/1",
+			inputBody: `{"title": "Бойцовский клуб", "year": 1999}`,
+			inputFilm: domain.Film{ID: 1, Title: "Бойцовский клуб", Year: 1999},
+			ifMatch:   `"3"`,
+			versions:  domain.Versions{3},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   201,
+			ID:                   10,
+			expectedResponseBody: ``,
+		},
+		{
+			name:      "Stale version",
+			addToUrl:  "/1",
+			inputBody: `{"title": "Бойцовский клуб", "year": 1999}`,
+			inputFilm: domain.Film{ID: 1, Title: "Бойцовский клуб", Year: 1999},
+			ifMatch:   `"2"`,
+			versions:  domain.Versions{2},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			ID:                   10,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:      "Version required",
+			addToUrl:  "/1",
+			inputBody: `{"title": "Бойцовский клуб", "year": 1999}`,
+			inputFilm: domain.Film{ID: 1, Title: "Бойцовский клуб", Year: 1999},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(domain.ErrVersionRequired)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   428,
+			ID:                   10,
+			expectedResponseBody: `{"message":"version must be given to change it"}`,
+		},
+		{
+			name:      "If-Match list",
+			addToUrl:  "/1",
+			inputBody: `{"title": "Бойцовский клуб", "year": 1999}`,
+			inputFilm: domain.Film{ID: 1, Title: "Бойцовский клуб", Year: 1999},
+			ifMatch:   `"2-9f86d081884c7d65", W/"3-60303ae22b998861",, "3-fd61a03af4f77d87"`,
+			versions:  domain.Versions{2, 3},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   201,
+			ID:                   10,
+			expectedResponseBody: ``,
+		},
+		{
+			name:      "Weak If-Match",
+			addToUrl:  "/1",
+			inputBody: `{"title": "Бойцовский клуб", "year": 1999}`,
+			inputFilm: domain.Film{ID: 1, Title: "Бойцовский клуб", Year: 1999},
+			ifMatch:   `W/"3"`,
+			versions:  domain.Versions{},
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {
+				r.EXPECT().UpdateFilm(domain.Editor{UserID: 10}, film, versions).Return(domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			ID:                   10,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:         "Wrong If-Match",
+			addToUrl:     "/1",
+			inputBody:    `{"title": "Бойцовский клуб", "year": 1999}`,
+			ifMatch:      `"3" "4"`,
+			mockBehavior: func(r *mock_service.MockFilm, film domain.Film, versions domain.Versions) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"Wrong If-Match header"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.inputFilm, test.versions)
+			test.mockBehaviorAdmin(repo2, test.ID)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("PUT /film/{id}", middlewareLog(http.HandlerFunc(handler.updateFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.ID)
+			req := httptest.NewRequest("PUT", url,
+				bytes.NewBufferString(test.inputBody))
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_patchFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		contentType          string
+		ifMatch              string
+		inputBody            string
+		versions             domain.Versions
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		expectedStatusCode   int
+		expectedResponseBody string
+		expectedETag         string
+	}{
+		{
+			name:        "Ok",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"2-d2c1e0bb3ddc1da8"`,
+			inputBody:   `{"information": null}`,
+			versions:    domain.Versions{2},
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchFilm(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Film{ID: 1, Title: "Брат", Year: 1997, Version: 3}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "id": 1,
+    "title": "Брат",
+    "year": 1997,
+    "information": {"String": "", "Valid": false},
+    "rating": {"Float64": 0, "Valid": false},
+    "votes": 0,
+    "editorialRating": {"Float64": 0, "Valid": false},
+    "runtimeMinutes": {"Int64": 0, "Valid": false},
+    "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+    "countries": null,
+    "originalLanguage": {"String": "", "Valid": false}
+}`,
+			expectedETag: `"3-e5753d682f7a9e3b"`,
+		},
+		{
+			name:        "Ok with charset",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json; charset=utf-8",
+			inputBody:   `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchFilm(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Film{ID: 1, Title: "Брат", Year: 1997, Version: 3}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "id": 1,
+    "title": "Брат",
+    "year": 1997,
+    "information": {"String": "", "Valid": false},
+    "rating": {"Float64": 0, "Valid": false},
+    "votes": 0,
+    "editorialRating": {"Float64": 0, "Valid": false},
+    "runtimeMinutes": {"Int64": 0, "Valid": false},
+    "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+    "countries": null,
+    "originalLanguage": {"String": "", "Valid": false}
+}`,
+		},
+		{
+			name:         "Wrong content type",
+			addToUrl:     "/1",
+			contentType:  "application/json",
+			inputBody:    `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   415,
+			expectedResponseBody: `{"message":"patch must be sent as application/merge-patch+json"}`,
+		},
+		{
+			name:         "Not an object",
+			addToUrl:     "/1",
+			contentType:  "application/merge-patch+json",
+			inputBody:    `null`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse film patch from json"}`,
+		},
+		{
+			name:        "Not valid",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"title": null}`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchFilm(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Film{}, errors.New("film is not valid"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't patch film"}`,
+		},
+		{
+			name:        "Unknown member",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			inputBody:   `{"titel": "Брат 2"}`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchFilm(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Film{}, errors.New(`json: unknown field "titel"`))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't patch film"}`,
+		},
+		{
+			name:        "Stale version",
+			addToUrl:    "/1",
+			contentType: "application/merge-patch+json",
+			ifMatch:     `"1"`,
+			inputBody:   `{"information": null}`,
+			versions:    domain.Versions{1},
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {
+				r.EXPECT().PatchFilm(domain.Editor{UserID: 10}, id, versions, []byte(patch)).Return(domain.Film{}, domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1",
+			contentType:  "application/merge-patch+json",
+			inputBody:    `{"information": null}`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64, versions domain.Versions, patch string) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, 1, test.versions, test.inputBody)
+			test.mockBehaviorAdmin(repo2, 10)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("PATCH /film/{id}", middlewareLog(http.HandlerFunc(handler.patchFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			req := httptest.NewRequest("PATCH", url,
+				bytes.NewBufferString(test.inputBody))
+			req.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			if test.expectedETag != "" {
+				assert.Equal(t, test.expectedETag, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestFilmHandler_deleteFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, id int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		ifMatch              string
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().DeleteFilm(domain.Editor{UserID: 10}, id, nil).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: ``,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:     "Can't delete",
+			addToUrl: "/1",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().DeleteFilm(domain.Editor{UserID: 10}, id, nil).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Can't delete film"}`,
+		},
+		{
+			name:     "Ok with If-Match",
+			addToUrl: "/1",
+			ifMatch:  `"2"`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().DeleteFilm(domain.Editor{UserID: 10}, id, domain.Versions{2}).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Any version",
+			addToUrl: "/1",
+			ifMatch:  "*",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().DeleteFilm(domain.Editor{UserID: 10}, id, domain.Versions{domain.AnyVersion}).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Stale version",
+			addToUrl: "/1",
+			ifMatch:  `"1"`,
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().DeleteFilm(domain.Editor{UserID: 10}, id, domain.Versions{1}).Return(domain.ErrVersionMismatch)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   412,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"version doesn't match, it was changed since it was read"}`,
+		},
+		{
+			name:         "Bad url",
+			addToUrl:     "/asd",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("DELETE /film/{id}", middlewareLog(http.HandlerFunc(handler.deleteFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("DELETE", url,
+				nil)
+			if test.ifMatch != "" {
+				req.Header.Set("If-Match", test.ifMatch)
+			}
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_restoreFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, id int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1/restore",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().RestoreFilm(domain.Editor{UserID: 10}, id).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Not in trash",
+			addToUrl: "/1/restore",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().RestoreFilm(domain.Editor{UserID: 10}, id).Return(sql.ErrNoRows)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   404,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Film isn't in the trash"}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1/restore",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:     "Can't restore",
+			addToUrl: "/1/restore",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().RestoreFilm(domain.Editor{UserID: 10}, id).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Can't restore film"}`,
+		},
+		{
+			name:         "Bad url",
+			addToUrl:     "/asd/restore",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film/{id}/restore", middlewareLog(http.HandlerFunc(handler.restoreFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_getFilmRevisions(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, id int64)
+
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		FilmId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/6/revisions",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilmRevisions(id).Return([]domain.FilmRevision{
+					{
+						Rev:       2,
+						CreatedAt: createdAt,
+						Film:      domain.Film{ID: 6, Title: "Брат", Year: 1997},
+						Diff: domain.Diff{"year": {
+							Before: json.RawMessage(`1996`),
+							After:  json.RawMessage(`1997`),
+						}},
+					},
+					{
+						Rev:       1,
+						CreatedAt: createdAt.Add(-time.Hour),
+						Film:      domain.Film{ID: 6, Title: "Брат", Year: 1996},
+					},
+				}, nil)
+			},
+			FilmId:             6,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {
+        "rev": 2,
+        "createdAt": "2024-03-01T12:00:00Z",
+        "film": {
+            "id": 6,
+            "title": "Брат",
+            "year": 1997,
+            "information": {"String": "", "Valid": false},
+            "rating": {"Float64": 0, "Valid": false},
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
+        },
+        "diff": {"year": {"before": 1996, "after": 1997}}
+    },
+    {
+        "rev": 1,
+        "createdAt": "2024-03-01T11:00:00Z",
+        "film": {
+            "id": 6,
+            "title": "Брат",
+            "year": 1996,
+            "information": {"String": "", "Valid": false},
+            "rating": {"Float64": 0, "Valid": false},
+            "votes": 0,
+            "editorialRating": {"Float64": 0, "Valid": false},
+            "runtimeMinutes": {"Int64": 0, "Valid": false},
+            "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+            "countries": null,
+            "originalLanguage": {"String": "", "Valid": false}
+        }
+    }
+]`,
+		},
+		{
+			name:     "Never updated",
+			addToUrl: "/6/revisions",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilmRevisions(id).Return([]domain.FilmRevision{}, nil)
+			},
+			FilmId:               6,
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:     "No film",
+			addToUrl: "/6/revisions",
+			mockBehavior: func(r *mock_service.MockFilm, id int64) {
+				r.EXPECT().GetFilmRevisions(id).Return(nil, sql.ErrNoRows)
+			},
+			FilmId:               6,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get revisions of film"}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/asd/revisions",
+			mockBehavior:         func(r *mock_service.MockFilm, id int64) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			test.mockBehavior(repo, test.FilmId)
+
+			services := &service.Service{Film: repo}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /film/{id}/revisions", middlewareLog(http.HandlerFunc(handler.getFilmRevisions)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", int64(10))
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestFilmHandler_revertFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, id, rev int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		Rev                  int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/6/revisions/1/revert",
+			mockBehavior: func(r *mock_service.MockFilm, id, rev int64) {
+				r.EXPECT().RevertFilm(domain.Editor{UserID: 10}, id, rev).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               6,
+			Rev:                  1,
+			expectedStatusCode:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "No revision",
+			addToUrl: "/6/revisions/9/revert",
+			mockBehavior: func(r *mock_service.MockFilm, id, rev int64) {
+				r.EXPECT().RevertFilm(domain.Editor{UserID: 10}, id, rev).Return(sql.ErrNoRows)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               6,
+			Rev:                  9,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"Revision doesn't exist"}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/6/revisions/1/revert",
+			mockBehavior: func(r *mock_service.MockFilm, id, rev int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:         "Bad revision",
+			addToUrl:     "/6/revisions/last/revert",
+			mockBehavior: func(r *mock_service.MockFilm, id, rev int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse revision from path"}`,
+		},
+		{
+			name:     "Can't revert",
+			addToUrl: "/6/revisions/1/revert",
+			mockBehavior: func(r *mock_service.MockFilm, id, rev int64) {
+				r.EXPECT().RevertFilm(domain.Editor{UserID: 10}, id, rev).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               6,
+			Rev:                  1,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't revert film"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId, test.Rev)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film/{id}/revisions/{rev}/revert", middlewareLog(http.HandlerFunc(handler.revertFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_addActorsToFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		inputBody            string
+		inputData            Data
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		Credits              []domain.Credit
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1",
+			inputBody: `{
+  "actors": [
+    1, 2
+  ]
+}`,
+			inputData: Data{
+				Actors: []int64{1, 2},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().AddActorToFilm(domain.Editor{UserID: 10}, filmId, credits).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			Credits:              []domain.Credit{{ActorID: 1}, {ActorID: 2}},
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Ok with credits",
+			addToUrl: "/13",
+			inputBody: `{
+  "actors": [9],
+  "credits": [
+    {"actorId": 5, "character": "Рик Далтон", "billing": 1, "type": "lead"},
+    {"actorId": 6, "character": "Клифф Бут", "billing": 2, "type": "lead"}
+  ]
+}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().AddActorToFilm(domain.Editor{UserID: 10}, filmId, credits).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 204,
+			UserId:             10,
+			FilmId:             13,
+			Credits: []domain.Credit{
+				{ActorID: 5, Part: domain.Part{Character: "Рик Далтон", Billing: 1, Type: domain.CreditLead}},
+				{ActorID: 6, Part: domain.Part{Character: "Клифф Бут", Billing: 2, Type: domain.CreditLead}},
+				{ActorID: 9},
+			},
+			expectedResponseBody: ``,
+		},
+		{
+			name:     "Not admin",
+			addToUrl: "/1",
+			inputBody: `{
+  "actors": [
+    1, 2
+  ]
+}`,
+			inputData: Data{
+				Actors: []int64{1, 2},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			Credits:              []domain.Credit{{ActorID: 1}, {ActorID: 2}},
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:     "Can't add",
+			addToUrl: "/1",
+			inputBody: `{
+  "actors": [
+    1, 2
+  ]
+}`,
+			inputData: Data{
+				Actors: []int64{1, 2},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().AddActorToFilm(domain.Editor{UserID: 10}, filmId, credits).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			Credits:              []domain.Credit{{ActorID: 1}, {ActorID: 2}},
+			expectedResponseBody: `{"message":"Can't add actor to film"}`,
+		},
+		{
+			name:      "Already present",
+			addToUrl:  "/1",
+			inputBody: `{"actors": [1, 2]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().AddActorToFilm(domain.Editor{UserID: 10}, filmId, credits).Return(domain.CreditErrors{
+					{ActorID: 2, Reason: domain.CreditAlreadyPresent},
+				})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode: 400,
+			UserId:             10,
+			FilmId:             1,
+			Credits:            []domain.Credit{{ActorID: 1}, {ActorID: 2}},
+			expectedResponseBody: `{
+    "message": "Can't add actor to film",
+    "errors": [{"actorId": 2, "reason": "already_present"}]
+}`,
+		},
+		{
+			name:     "Bad url",
+			addToUrl: "/asd",
+			inputBody: `{
+  "actors": [
+    1, 2
+  ]
+}`,
+			inputData: Data{
+				Actors: []int64{1, 2},
+			},
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId, test.Credits)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film/{id}", middlewareLog(http.HandlerFunc(handler.addActorsToFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url,
+				bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_addGenresToFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, filmId int64, genreId []int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		inputBody            string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		GenresId             []int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			addToUrl:  "/1/genres",
+			inputBody: `{"genres": [1, 8]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, genreId []int64) {
+				r.EXPECT().AddGenreToFilm(domain.Editor{UserID: 10}, filmId, genreId).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   204,
+			UserId:               10,
+			FilmId:               1,
+			GenresId:             []int64{1, 8},
+			expectedResponseBody: ``,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1/genres",
+			inputBody:    `{"genres": [1, 8]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, genreId []int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:         "Wrong input form",
+			addToUrl:     "/1/genres",
+			inputBody:    `{"actors": [1, 8]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, genreId []int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			expectedResponseBody: `{"message":"Wrong input form"}`,
+		},
+		{
+			name:      "Can't add",
+			addToUrl:  "/1/genres",
+			inputBody: `{"genres": [100]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, genreId []int64) {
+				r.EXPECT().AddGenreToFilm(domain.Editor{UserID: 10}, filmId, genreId).Return(errors.New(""))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			UserId:               10,
+			FilmId:               1,
+			GenresId:             []int64{100},
+			expectedResponseBody: `{"message":"Can't add genre to film"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId, test.GenresId)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film/{id}/genres", middlewareLog(http.HandlerFunc(handler.addGenresToFilm)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url,
+				bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_uploadPoster(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, filmId int64, data []byte)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	poster := domain.Image{
+		URL: "/media/films/1/poster/0a1b2c3d4e5f6071/original.png",
+		Thumbnails: map[string]string{
+			"small":  "/media/films/1/poster/0a1b2c3d4e5f6071/small.jpg",
+			"medium": "/media/films/1/poster/0a1b2c3d4e5f6071/medium.jpg",
+			"large":  "/media/films/1/poster/0a1b2c3d4e5f6071/large.jpg",
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		field                string
+		data                 []byte
+		maxImageSize         int64
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		FilmId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1/poster",
+			field:    "image",
+			data:     []byte("\x89PNG\r\n\x1a\n"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {
+				r.EXPECT().SetPoster(domain.Editor{UserID: 10}, filmId, data).Return(poster, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			FilmId:             1,
+			expectedStatusCode: 201,
+			expectedResponseBody: `{
+    "url": "/media/films/1/poster/0a1b2c3d4e5f6071/original.png",
+    "thumbnails": {
+        "small": "/media/films/1/poster/0a1b2c3d4e5f6071/small.jpg",
+        "medium": "/media/films/1/poster/0a1b2c3d4e5f6071/medium.jpg",
+        "large": "/media/films/1/poster/0a1b2c3d4e5f6071/large.jpg"
+    }
+}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1/poster",
+			field:        "image",
+			data:         []byte("\x89PNG\r\n\x1a\n"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:         "No image",
+			addToUrl:     "/1/poster",
+			field:        "poster",
+			data:         []byte("\x89PNG\r\n\x1a\n"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't read image from form"}`,
+		},
+		{
+			name:     "Not an image",
+			addToUrl: "/1/poster",
+			field:    "image",
+			data:     []byte("%PDF-1.7"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {
+				r.EXPECT().SetPoster(domain.Editor{UserID: 10}, filmId, data).Return(domain.Image{}, domain.ErrImageTypeUnsupported)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               1,
+			expectedStatusCode:   415,
+			expectedResponseBody: `{"message":"image type is not supported, use JPEG, PNG, GIF or WebP"}`,
+		},
+		{
+			name:     "Too large",
+			addToUrl: "/1/poster",
+			field:    "image",
+			data:     []byte("\xff\xd8\xff\xe0"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {
+				r.EXPECT().SetPoster(domain.Editor{UserID: 10}, filmId, data).Return(domain.Image{}, domain.ErrImageTooLarge)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               1,
+			expectedStatusCode:   413,
+			expectedResponseBody: `{"message":"image is too large"}`,
+		},
+		{
+			name:         "Body too large",
+			addToUrl:     "/1/poster",
+			field:        "image",
+			data:         bytes.Repeat([]byte{0xff}, 2<<20),
+			maxImageSize: 1 << 20,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               1,
+			expectedStatusCode:   413,
+			expectedResponseBody: `{"message":"image is too large"}`,
+		},
+		{
+			name:     "No film",
+			addToUrl: "/100/poster",
+			field:    "image",
+			data:     []byte("\x89PNG\r\n\x1a\n"),
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, data []byte) {
+				r.EXPECT().SetPoster(domain.Editor{UserID: 10}, filmId, data).Return(domain.Image{}, sql.ErrNoRows)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			FilmId:               100,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't upload poster"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockFilm(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.FilmId, test.data)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Film: repo, User: repo2, Config: service.Config{MaxImageSize: test.maxImageSize}}
+			handler := FilmHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /film/{id}/poster", middlewareLog(http.HandlerFunc(handler.uploadPoster)))
+
+			// Create Request
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile(test.field, "poster")
+			part.Write(test.data)
+			form.Close()
+
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestFilmHandler_rateFilm(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockFilm, filmId, userId int64, vote domain.Vote)
+
+	userRating := 7
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		inputBody            string
+		mockBehavior         mockBehavior
+		UserId               int64
+		FilmId               int64
+		Vote                 domain.Vote
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			addToUrl:  "/2/rating",
+			inputBody: `{"rating": 7}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId, userId int64, vote domain.Vote) {
+				r.EXPECT().RateFilm(filmId, userId, vote).Return(domain.FilmRating{
+					Rating:     sql.NullFloat64{Float64: 8.3, Valid: true},
+					Votes:      4,
+					UserRating: &userRating,
+				}, nil)
+			},
+			UserId:               10,
+			FilmId:               2,
+			Vote:                 domain.Vote{Rating: 7},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"rating": {"Float64": 8.3, "Valid": true}, "votes": 4, "userRating": 7}`,
+		},
+		{
+			name:                 "Wrong input",
+			addToUrl:             "/2/rating",
+			inputBody:            `{"rating": "seven"}`,
+			mockBehavior:         func(r *mock_service.MockFilm, filmId, userId int64, vote domain.Vote) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse rating from json"}`,
+		},
+		{
+			name:      "Out of range",
+			addToUrl:  "/2/rating",
+			inputBody: `{"rating": 11}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId, userId int64, vote domain.Vote) {
+				r.EXPECT().RateFilm(filmId, userId, vote).Return(domain.FilmRating{}, errors.New("rating must be from 0 to 10"))
 			},
+			UserId:               10,
+			FilmId:               2,
+			Vote:                 domain.Vote{Rating: 11},
 			expectedStatusCode:   400,
-			ID:                   10,
-			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+			expectedResponseBody: `{"message":"Can't rate film"}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/two/rating",
+			inputBody:            `{"rating": 7}`,
+			mockBehavior:         func(r *mock_service.MockFilm, filmId, userId int64, vote domain.Vote) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
 		},
 	}
 
@@ -634,22 +2426,19 @@ func TestFilmHandler_createFilm(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockFilm(c)
-			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, test.FilmId, test.UserId, test.Vote)
 
-			test.mockBehavior(repo, test.inputFilm)
-			test.mockBehaviorAdmin(repo2, test.ID)
-
-			services := &service.Service{Film: repo, User: repo2}
+			services := &service.Service{Film: repo}
 			handler := FilmHandler{services}
 
 			// Init Endpoint
-			http.Handle("POST /film", middlewareLog(http.HandlerFunc(handler.createFilm)))
+			http.Handle("PUT /film/{id}/rating", middlewareLog(http.HandlerFunc(handler.rateFilm)))
 
 			// Create Request
 			w := httptest.NewRecorder()
-			ctx := context.WithValue(context.Background(), "userID", test.ID)
-			req := httptest.NewRequest("POST", "/film",
-				bytes.NewBufferString(test.inputBody))
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("PUT", url, bytes.NewBufferString(test.inputBody))
 			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
@@ -657,117 +2446,90 @@ func TestFilmHandler_createFilm(t *testing.T) {
 
 			// Assert
 			assert.Equal(t, w.Code, test.expectedStatusCode)
-			if w.Body.String() != test.expectedResponseBody {
-				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
-			}
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
 		})
 	}
 }
 
-func TestFilmHandler_updateFilm(t *testing.T) {
+func TestFilmHandler_getFilmActors(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockFilm, film domain.Film)
-	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+	type mockBehavior func(r *mock_service.MockFilm, id int64, q domain.CreditQuery)
+
+	birthday := time.Date(1971, 12, 27, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name                 string
 		addToUrl             string
 		mockBehavior         mockBehavior
-		mockBehaviorAdmin    mockBehavior2
-		inputBody            string
-		inputFilm            domain.Film
-		ID                   int64
+		FilmId               int64
+		query                domain.CreditQuery
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:     "Ok",
-			addToUrl: "/1",
-			inputBody: `{
-    "title": "Бойцовский клуб",
-    "year": 1999,
-    "information": {
-        "String": "02:19",
-        "Valid": true
-    },
-    "rating": {
-        "Float64": 9.1,
-        "Valid": true
-    }
-}`,
-			inputFilm: domain.Film{
-				ID:          1,
-				Title:       "Бойцовский клуб",
-				Year:        1999,
-				Information: sql.NullString{String: "02:19", Valid: true},
-				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {
-				r.EXPECT().UpdateFilm(film).Return(nil)
-			},
-			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
-				r.EXPECT().IsAdmin(id).Return(true, nil)
+			addToUrl: "/6/actors?withTotal=true",
+			mockBehavior: func(r *mock_service.MockFilm, id int64, q domain.CreditQuery) {
+				total := int64(1)
+				r.EXPECT().GetFilmActors(id, q).Return(domain.CastPage{
+					Actors: []domain.CastMember{
+						{
+							Actor: domain.Actor{ID: 1, Name: "Сергей", Surname: "Бодров", Birthday: birthday, Sex: "m"},
+							Part:  domain.Part{Character: "Данила Багров", Billing: 1, Type: domain.CreditLead},
+						},
+					},
+					Total: &total,
+				}, nil)
 			},
-			expectedStatusCode:   201,
-			ID:                   10,
-			expectedResponseBody: ``,
+			FilmId:             6,
+			query:              domain.CreditQuery{Page: domain.PageRequest{WithTotal: true}},
+			expectedStatusCode: 200,
+			expectedResponseBody: `
+{
+    "actors": [
+        {
+            "id": 1,
+            "name": "Сергей",
+            "surname": "Бодров",
+            "patronymic": {"String": "", "Valid": false},
+            "birthday": "1971-12-27T00:00:00Z",
+            "sex": "m",
+            "information": {"String": "", "Valid": false},
+            "character": "Данила Багров",
+            "billing": 1,
+            "type": "lead"
+        }
+    ],
+    "total": 1
+}`,
 		},
 		{
-			name:     "Not admin",
-			addToUrl: "/1",
-			inputBody: `{
-    "title": "Бойцовский клуб",
-    "year": 1999,
-    "information": {
-        "String": "02:19",
-        "Valid": true
-    },
-    "rating": {
-        "Float64": 9.1,
-        "Valid": true
-    }
-}`,
-			inputFilm: domain.Film{
-				Title:       "Бойцовский клуб",
-				Year:        1999,
-				Information: sql.NullString{String: "02:19", Valid: true},
-				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {},
-			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
-				r.EXPECT().IsAdmin(id).Return(false, nil)
+			name:     "Ok ordered by name",
+			addToUrl: "/6/actors?orderBy=name&cursor=abc",
+			mockBehavior: func(r *mock_service.MockFilm, id int64, q domain.CreditQuery) {
+				r.EXPECT().GetFilmActors(id, q).Return(domain.CastPage{Actors: []domain.CastMember{}}, nil)
 			},
+			FilmId:               6,
+			query:                domain.CreditQuery{OrderBy: "name", Page: domain.PageRequest{Cursor: "abc"}},
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"actors": []}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/asd/actors",
+			mockBehavior:         func(r *mock_service.MockFilm, id int64, q domain.CreditQuery) {},
 			expectedStatusCode:   400,
-			ID:                   10,
-			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
 		},
 		{
-			name:     "Wrong request",
-			addToUrl: "/1",
-			inputBody: `{
-    "information": {
-        "String": "02:19",
-        "Valid": true
-    },
-    "rating": {
-        "Float64": 9.1,
-        "Valid": true
-    }
-}`,
-			inputFilm: domain.Film{
-				ID:          1,
-				Information: sql.NullString{String: "02:19", Valid: true},
-				Rating:      sql.NullFloat64{Float64: 9.1, Valid: true},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, film domain.Film) {
-				r.EXPECT().UpdateFilm(film).Return(errors.New("film is not valid"))
-			},
-			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
-				r.EXPECT().IsAdmin(id).Return(true, nil)
+			name:     "Can't get",
+			addToUrl: "/100/actors",
+			mockBehavior: func(r *mock_service.MockFilm, id int64, q domain.CreditQuery) {
+				r.EXPECT().GetFilmActors(id, q).Return(domain.CastPage{}, errors.New(""))
 			},
+			FilmId:               100,
 			expectedStatusCode:   400,
-			ID:                   10,
-			expectedResponseBody: `{"message":"Can't update film"}`,
+			expectedResponseBody: `{"message":"Can't get actors of film"}`,
 		},
 	}
 
@@ -779,103 +2541,143 @@ func TestFilmHandler_updateFilm(t *testing.T) {
 			defer c.Finish()
 
 			repo := mock_service.NewMockFilm(c)
-			repo2 := mock_service.NewMockUser(c)
+			test.mockBehavior(repo, test.FilmId, test.query)
 
-			test.mockBehavior(repo, test.inputFilm)
-			test.mockBehaviorAdmin(repo2, test.ID)
-
-			services := &service.Service{Film: repo, User: repo2}
+			services := &service.Service{Film: repo}
 			handler := FilmHandler{services}
 
 			// Init Endpoint
-			http.Handle("PUT /film/{id}", middlewareLog(http.HandlerFunc(handler.updateFilm)))
+			http.Handle("GET /film/{id}/actors", middlewareLog(http.HandlerFunc(handler.getFilmActors)))
 
 			// Create Request
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/film%s", test.addToUrl)
-			ctx := context.WithValue(context.Background(), "userID", test.ID)
-			req := httptest.NewRequest("PUT", url,
-				bytes.NewBufferString(test.inputBody))
-			req = req.WithContext(ctx)
+			req := httptest.NewRequest("GET", url, nil)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
 			http.DefaultServeMux.ServeHTTP(w, req)
 
 			// Assert
 			assert.Equal(t, w.Code, test.expectedStatusCode)
-			if w.Body.String() != test.expectedResponseBody {
-				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
-			}
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
 		})
 	}
 }
 
-func TestFilmHandler_deleteFilm(t *testing.T) {
+func TestFilmHandler_replaceFilmActors(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockFilm, id int64)
+	type mockBehavior func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit)
 	type mockBehavior2 func(r *mock_service.MockUser, id int64)
 
 	tests := []struct {
 		name                 string
 		addToUrl             string
+		inputBody            string
 		mockBehavior         mockBehavior
 		mockBehaviorAdmin    mockBehavior2
 		UserId               int64
 		FilmId               int64
+		Credits              []domain.Credit
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
-			name:     "Ok",
-			addToUrl: "/1",
-			mockBehavior: func(r *mock_service.MockFilm, id int64) {
-				r.EXPECT().DeleteFilm(id).Return(nil)
+			name:      "Ok",
+			addToUrl:  "/13/actors",
+			inputBody: `{"credits": [{"actorId": 5, "character": "Рик Далтон", "billing": 1, "type": "lead"}]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().ReplaceCast(domain.Editor{UserID: 10}, filmId, credits).Return(nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
+			UserId: 10,
+			FilmId: 13,
+			Credits: []domain.Credit{
+				{ActorID: 5, Part: domain.Part{Character: "Рик Далтон", Billing: 1, Type: domain.CreditLead}},
+			},
 			expectedStatusCode:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:      "Ok empty cast",
+			addToUrl:  "/13/actors",
+			inputBody: `{"actors": []}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().ReplaceCast(domain.Editor{UserID: 10}, filmId, credits).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
 			UserId:               10,
-			FilmId:               1,
+			FilmId:               13,
+			expectedStatusCode:   204,
 			expectedResponseBody: ``,
 		},
 		{
 			name:         "Not admin",
-			addToUrl:     "/1",
-			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			addToUrl:     "/13/actors",
+			inputBody:    `{"actors": [5]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(false, nil)
 			},
-			expectedStatusCode:   400,
 			UserId:               10,
-			FilmId:               1,
+			expectedStatusCode:   400,
 			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
 		},
 		{
-			name:     "Can't delete",
-			addToUrl: "/1",
-			mockBehavior: func(r *mock_service.MockFilm, id int64) {
-				r.EXPECT().DeleteFilm(id).Return(errors.New(""))
-			},
+			name:         "Wrong input form",
+			addToUrl:     "/13/actors",
+			inputBody:    `{"cast": [5]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   400,
 			UserId:               10,
-			FilmId:               1,
-			expectedResponseBody: `{"message":"Can't delete film"}`,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong input form"}`,
 		},
 		{
-			name:         "Bad url",
-			addToUrl:     "/asd",
-			mockBehavior: func(r *mock_service.MockFilm, id int64) {},
+			name:      "Wrong credits",
+			addToUrl:  "/13/actors",
+			inputBody: `{"actors": [5, 5, 100]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().ReplaceCast(domain.Editor{UserID: 10}, filmId, credits).Return(domain.CreditErrors{
+					{ActorID: 5, Reason: domain.CreditAlreadyPresent},
+					{ActorID: 100, Reason: domain.CreditUnknownActor},
+				})
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			FilmId:             13,
+			Credits:            []domain.Credit{{ActorID: 5}, {ActorID: 5}, {ActorID: 100}},
+			expectedStatusCode: 400,
+			expectedResponseBody: `{
+    "message": "Can't replace actors of film",
+    "errors": [
+        {"actorId": 5, "reason": "already_present"},
+        {"actorId": 100, "reason": "unknown_actor"}
+    ]
+}`,
+		},
+		{
+			name:      "Can't replace",
+			addToUrl:  "/100/actors",
+			inputBody: `{"actors": [5]}`,
+			mockBehavior: func(r *mock_service.MockFilm, filmId int64, credits []domain.Credit) {
+				r.EXPECT().ReplaceCast(domain.Editor{UserID: 10}, filmId, credits).Return(errors.New("sql: no rows in result set"))
+			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   400,
 			UserId:               10,
-			FilmId:               1,
-			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+			FilmId:               100,
+			Credits:              []domain.Credit{{ActorID: 5}},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't replace actors of film"}`,
 		},
 	}
 
@@ -889,21 +2691,20 @@ func TestFilmHandler_deleteFilm(t *testing.T) {
 			repo := mock_service.NewMockFilm(c)
 			repo2 := mock_service.NewMockUser(c)
 
-			test.mockBehavior(repo, test.FilmId)
+			test.mockBehavior(repo, test.FilmId, test.Credits)
 			test.mockBehaviorAdmin(repo2, test.UserId)
 
 			services := &service.Service{Film: repo, User: repo2}
 			handler := FilmHandler{services}
 
 			// Init Endpoint
-			http.Handle("DELETE /film/{id}", middlewareLog(http.HandlerFunc(handler.deleteFilm)))
+			http.Handle("PUT /film/{id}/actors", middlewareLog(http.HandlerFunc(handler.replaceFilmActors)))
 
 			// Create Request
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/film%s", test.addToUrl)
 			ctx := context.WithValue(context.Background(), "userID", test.UserId)
-			req := httptest.NewRequest("DELETE", url,
-				nil)
+			req := httptest.NewRequest("PUT", url, bytes.NewBufferString(test.inputBody))
 			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
@@ -918,110 +2719,78 @@ func TestFilmHandler_deleteFilm(t *testing.T) {
 	}
 }
 
-func TestFilmHandler_addActorsToFilm(t *testing.T) {
+func TestFilmHandler_removeActorFromFilm(t *testing.T) {
 	// Init Test Table
-	type mockBehavior func(r *mock_service.MockFilm, filmId int64, actorId []int64)
+	type mockBehavior func(r *mock_service.MockFilm, filmId, actorId int64)
 	type mockBehavior2 func(r *mock_service.MockUser, id int64)
 
 	tests := []struct {
 		name                 string
 		addToUrl             string
-		inputBody            string
-		inputData            Data
 		mockBehavior         mockBehavior
 		mockBehaviorAdmin    mockBehavior2
 		UserId               int64
 		FilmId               int64
-		ActorsId             []int64
+		ActorId              int64
 		expectedStatusCode   int
 		expectedResponseBody string
 	}{
 		{
 			name:     "Ok",
-			addToUrl: "/1",
-			inputBody: `{
-  "actors": [
-    1, 2
-  ]
-}`,
-			inputData: Data{
-				Actors: []int64{1, 2},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, filmId int64, actorId []int64) {
-				r.EXPECT().AddActorToFilm(filmId, actorId).Return(nil)
+			addToUrl: "/13/actors/5",
+			mockBehavior: func(r *mock_service.MockFilm, filmId, actorId int64) {
+				r.EXPECT().RemoveActorFromFilm(domain.Editor{UserID: 10}, filmId, actorId).Return(nil)
 			},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   204,
 			UserId:               10,
-			FilmId:               1,
-			ActorsId:             []int64{1, 2},
+			FilmId:               13,
+			ActorId:              5,
+			expectedStatusCode:   204,
 			expectedResponseBody: ``,
 		},
 		{
-			name:     "Not admin",
-			addToUrl: "/1",
-			inputBody: `{
-  "actors": [
-    1, 2
-  ]
-}`,
-			inputData: Data{
-				Actors: []int64{1, 2},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, filmId int64, actorId []int64) {},
+			name:         "Not admin",
+			addToUrl:     "/13/actors/5",
+			mockBehavior: func(r *mock_service.MockFilm, filmId, actorId int64) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(false, nil)
 			},
-			expectedStatusCode:   400,
 			UserId:               10,
-			FilmId:               1,
-			ActorsId:             []int64{1, 2},
+			expectedStatusCode:   400,
 			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
 		},
 		{
-			name:     "Can't add",
-			addToUrl: "/1",
-			inputBody: `{
-  "actors": [
-    1, 2
-  ]
-}`,
-			inputData: Data{
-				Actors: []int64{1, 2},
-			},
-			mockBehavior: func(r *mock_service.MockFilm, filmId int64, actorId []int64) {
-				r.EXPECT().AddActorToFilm(filmId, actorId).Return(errors.New(""))
-			},
+			name:         "Bad url",
+			addToUrl:     "/13/actors/asd",
+			mockBehavior: func(r *mock_service.MockFilm, filmId, actorId int64) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   400,
 			UserId:               10,
-			FilmId:               1,
-			ActorsId:             []int64{1, 2},
-			expectedResponseBody: `{"message":"Can't add actor to film"}`,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
 		},
 		{
-			name:     "Bad url",
-			addToUrl: "/asd",
-			inputBody: `{
-  "actors": [
-    1, 2
-  ]
-}`,
-			inputData: Data{
-				Actors: []int64{1, 2},
+			name:     "Not in cast",
+			addToUrl: "/13/actors/7",
+			mockBehavior: func(r *mock_service.MockFilm, filmId, actorId int64) {
+				r.EXPECT().RemoveActorFromFilm(domain.Editor{UserID: 10}, filmId, actorId).Return(domain.CreditErrors{
+					{ActorID: 7, Reason: domain.CreditNotInCast},
+				})
 			},
-			mockBehavior: func(r *mock_service.MockFilm, filmId int64, actorId []int64) {},
 			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
 				r.EXPECT().IsAdmin(id).Return(true, nil)
 			},
-			expectedStatusCode:   400,
-			UserId:               10,
-			FilmId:               1,
-			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+			UserId:             10,
+			FilmId:             13,
+			ActorId:            7,
+			expectedStatusCode: 404,
+			expectedResponseBody: `{
+    "message": "Can't remove actor from film",
+    "errors": [{"actorId": 7, "reason": "not_in_cast"}]
+}`,
 		},
 	}
 
@@ -1035,21 +2804,20 @@ func TestFilmHandler_addActorsToFilm(t *testing.T) {
 			repo := mock_service.NewMockFilm(c)
 			repo2 := mock_service.NewMockUser(c)
 
-			test.mockBehavior(repo, test.FilmId, test.ActorsId)
+			test.mockBehavior(repo, test.FilmId, test.ActorId)
 			test.mockBehaviorAdmin(repo2, test.UserId)
 
 			services := &service.Service{Film: repo, User: repo2}
 			handler := FilmHandler{services}
 
 			// Init Endpoint
-			http.Handle("POST /film/{id}", middlewareLog(http.HandlerFunc(handler.addActorsToFilm)))
+			http.Handle("DELETE /film/{id}/actors/{actorId}", middlewareLog(http.HandlerFunc(handler.removeActorFromFilm)))
 
 			// Create Request
 			w := httptest.NewRecorder()
 			url := fmt.Sprintf("/film%s", test.addToUrl)
 			ctx := context.WithValue(context.Background(), "userID", test.UserId)
-			req := httptest.NewRequest("POST", url,
-				bytes.NewBufferString(test.inputBody))
+			req := httptest.NewRequest("DELETE", url, nil)
 			req = req.WithContext(ctx)
 
 			// Call your handler directly, passing in the ResponseRecorder and Request
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type GenreHandler struct {
+	ser *service.Service
+}
+
+// @Summary Get List of genres
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description get list of genres
+// @ID get-list-genre
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} []domain.Genre
+// @Failure 400
+// @Failure 500
+// @Failure default
+// @Router /genre [get]
+func (g *GenreHandler) genresList(w http.ResponseWriter, req *http.Request) {
+	genres, err := g.ser.Genre.GetGenres()
+	if err != nil {
+		newErrorResponse(w, err, "Can't get genres", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(genres)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse genres to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Create genre
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Create genre. You must have admin role.
+// @ID create-genre
+// @Accept  json
+// @Produce  json
+// @Param input body domain.Genre true "Genre"
+// @Success 201
+// @Failure 400
+// @Failure default
+// @Router /genre [POST]
+func (g *GenreHandler) createGenre(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := g.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	var genre domain.Genre
+	if err := json.NewDecoder(req.Body).Decode(&genre); err != nil {
+		newErrorResponse(w, err, "Can't decode genre from json", http.StatusBadRequest)
+		return
+	}
+
+	err = g.ser.Genre.CreateGenre(genre)
+	if err != nil {
+		newErrorResponse(w, err, "Can't create genre", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Get genre by ID
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Get genre by ID
+// @ID get-genre-by-id
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} domain.Genre
+// @Failure 400
+// @Failure default
+// @Router /genre/{id} [GET]
+func (g *GenreHandler) getGenre(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	genre, err := g.ser.Genre.GetGenre(id)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get genre", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(genre)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse genre to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Update genre by ID
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Update genre by ID. You must have admin role.
+// @ID update-genre-by-id
+// @Accept  json
+// @Produce  json
+// @Param input body domain.Genre true "Genre"
+// @Success 201
+// @Failure 400
+// @Failure default
+// @Router /genre/{id} [PUT]
+func (g *GenreHandler) updateGenre(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := g.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	var genre domain.Genre
+	if err := json.NewDecoder(req.Body).Decode(&genre); err != nil {
+		newErrorResponse(w, err, "Can't decode genre from json", http.StatusBadRequest)
+		return
+	}
+	genre.ID = id
+
+	err = g.ser.Genre.UpdateGenre(genre)
+	if err != nil {
+		newErrorResponse(w, err, "Can't update genre", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Delete genre by ID
+// @Security ApiKeyAuth
+// @Tags genres
+// @Description Delete genre by ID. Films of the genre are kept. You must have admin role.
+// @ID delete-genre-by-id
+// @Accept  json
+// @Produce  json
+// @Success 204
+// @Failure 400
+// @Failure default
+// @Router /genre/{id} [DELETE]
+func (g *GenreHandler) deleteGenre(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := g.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	if err := g.ser.Genre.DeleteGenre(id); err != nil {
+		newErrorResponse(w, err, "Can't delete genre", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenreHandler_genresList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGenre)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenres().Return([]domain.Genre{
+					{ID: 1, Name: "драма"},
+					{ID: 2, Name: "криминал"},
+				}, nil)
+			},
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {"id": 1, "name": "драма"},
+    {"id": 2, "name": "криминал"}
+]`,
+		},
+		{
+			name: "Can't get genres",
+			mockBehavior: func(r *mock_service.MockGenre) {
+				r.EXPECT().GetGenres().Return(nil, errors.New(""))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get genres"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGenre(c)
+			test.mockBehavior(repo)
+
+			services := &service.Service{Genre: repo}
+			handler := GenreHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /genre", middlewareLog(http.HandlerFunc(handler.genresList)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/genre", nil)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestGenreHandler_createGenre(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGenre, genre domain.Genre)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		inputBody            string
+		inputGenre           domain.Genre
+		ID                   int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:       "Ok",
+			inputBody:  `{"name": "вестерн"}`,
+			inputGenre: domain.Genre{Name: "вестерн"},
+			mockBehavior: func(r *mock_service.MockGenre, genre domain.Genre) {
+				r.EXPECT().CreateGenre(genre).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   201,
+			ID:                   10,
+			expectedResponseBody: ``,
+		},
+		{
+			name:       "Wrong request",
+			inputBody:  `{}`,
+			inputGenre: domain.Genre{},
+			mockBehavior: func(r *mock_service.MockGenre, genre domain.Genre) {
+				r.EXPECT().CreateGenre(genre).Return(errors.New("genre is not valid"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"Can't create genre"}`,
+		},
+		{
+			name:         "Not admin",
+			inputBody:    `{"name": "вестерн"}`,
+			inputGenre:   domain.Genre{Name: "вестерн"},
+			mockBehavior: func(r *mock_service.MockGenre, genre domain.Genre) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			expectedStatusCode:   400,
+			ID:                   10,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGenre(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.inputGenre)
+			test.mockBehaviorAdmin(repo2, test.ID)
+
+			services := &service.Service{Genre: repo, User: repo2}
+			handler := GenreHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /genre", middlewareLog(http.HandlerFunc(handler.createGenre)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", test.ID)
+			req := httptest.NewRequest("POST", "/genre",
+				bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestGenreHandler_deleteGenre(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockGenre, id int64)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		GenreId              int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/1",
+			mockBehavior: func(r *mock_service.MockGenre, id int64) {
+				r.EXPECT().DeleteGenre(id).Return(nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			GenreId:              1,
+			expectedStatusCode:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "/1",
+			mockBehavior: func(r *mock_service.MockGenre, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			GenreId:              1,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:         "Bad url",
+			addToUrl:     "/asd",
+			mockBehavior: func(r *mock_service.MockGenre, id int64) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockGenre(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.GenreId)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Genre: repo, User: repo2}
+			handler := GenreHandler{services}
+
+			// Init Endpoint
+			http.Handle("DELETE /genre/{id}", middlewareLog(http.HandlerFunc(handler.deleteGenre)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/genre%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("DELETE", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
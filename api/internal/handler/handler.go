@@ -1,29 +1,58 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"io"
 	_ "kinoteka/docs"
+	"kinoteka/internal/domain"
 	"kinoteka/internal/service"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type Handler struct {
-	actor *ActorHandler
-	film  *FilmHandler
-	user  *UserHandler
-	ser   *service.Service
+	actor   *ActorHandler
+	film    *FilmHandler
+	user    *UserHandler
+	genre   *GenreHandler
+	search  *SearchHandler
+	list    *ListHandler
+	history *HistoryHandler
+	rec     *RecommendationHandler
+	imp     *ImportHandler
+	exp     *ExportHandler
+	trash   *TrashHandler
+	audit   *AuditHandler
+	ser     *service.Service
 }
 
 func New(ser *service.Service) *Handler {
 	s := &Handler{
-		actor: &ActorHandler{ser: ser},
-		film:  &FilmHandler{ser: ser},
-		user:  &UserHandler{ser: ser},
-		ser:   ser,
+		actor:   &ActorHandler{ser: ser},
+		film:    &FilmHandler{ser: ser},
+		user:    &UserHandler{ser: ser},
+		genre:   &GenreHandler{ser: ser},
+		search:  &SearchHandler{ser: ser},
+		list:    &ListHandler{ser: ser},
+		history: &HistoryHandler{ser: ser},
+		rec:     &RecommendationHandler{ser: ser},
+		imp:     &ImportHandler{ser: ser},
+		exp:     &ExportHandler{ser: ser},
+		trash:   &TrashHandler{ser: ser},
+		audit:   &AuditHandler{ser: ser},
+		ser:     ser,
 	}
 
 	return s
@@ -35,15 +64,60 @@ func (h *Handler) RegisterHandlers() {
 
 	http.Handle("GET /actor/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.getActor))))
 	http.Handle("PUT /actor/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.updateActor))))
+	http.Handle("PATCH /actor/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.patchActor))))
 	http.Handle("DELETE /actor/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.deleteActor))))
+	http.Handle("GET /actor/{id}/films", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.getActorFilms))))
+	http.Handle("GET /actor/{id}/path/{otherId}", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.getActorPath))))
+	http.Handle("GET /actor/{id}/revisions", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.getActorRevisions))))
+	http.Handle("POST /actor/{id}/restore", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.restoreActor))))
+	http.Handle("POST /actor/{id}/photo", middlewareLog(h.userIdentity(http.HandlerFunc(h.actor.uploadPhoto))))
 
 	http.Handle("GET /film", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.film))))
 	http.Handle("POST /film", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.createFilm))))
 
 	http.Handle("GET /film/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.getFilm))))
 	http.Handle("PUT /film/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.updateFilm))))
+	http.Handle("PATCH /film/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.patchFilm))))
 	http.Handle("DELETE /film/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.deleteFilm))))
 	http.Handle("POST /film/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.addActorsToFilm))))
+	http.Handle("GET /film/{id}/actors", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.getFilmActors))))
+	http.Handle("PUT /film/{id}/actors", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.replaceFilmActors))))
+	http.Handle("DELETE /film/{id}/actors/{actorId}", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.removeActorFromFilm))))
+	http.Handle("POST /film/{id}/genres", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.addGenresToFilm))))
+	http.Handle("POST /film/{id}/poster", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.uploadPoster))))
+	http.Handle("POST /film/{id}/restore", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.restoreFilm))))
+	http.Handle("GET /film/{id}/revisions", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.getFilmRevisions))))
+	http.Handle("POST /film/{id}/revisions/{rev}/revert", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.revertFilm))))
+	http.Handle("PUT /film/{id}/rating", middlewareLog(h.userIdentity(http.HandlerFunc(h.film.rateFilm))))
+	http.Handle("GET /film/{id}/similar", middlewareLog(h.userIdentity(http.HandlerFunc(h.rec.getSimilarFilms))))
+
+	http.Handle("GET /genre", middlewareLog(h.userIdentity(http.HandlerFunc(h.genre.genresList))))
+	http.Handle("POST /genre", middlewareLog(h.userIdentity(http.HandlerFunc(h.genre.createGenre))))
+
+	http.Handle("GET /genre/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.genre.getGenre))))
+	http.Handle("PUT /genre/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.genre.updateGenre))))
+	http.Handle("DELETE /genre/{id}", middlewareLog(h.userIdentity(http.HandlerFunc(h.genre.deleteGenre))))
+
+	http.Handle("GET /export/films", middlewareLog(h.userIdentity(http.HandlerFunc(h.exp.exportFilms))))
+	http.Handle("GET /export/actors", middlewareLog(h.userIdentity(http.HandlerFunc(h.exp.exportActors))))
+
+	http.Handle("POST /import", middlewareLog(h.userIdentity(http.HandlerFunc(h.imp.importData))))
+
+	http.Handle("GET /trash", middlewareLog(h.userIdentity(http.HandlerFunc(h.trash.getTrash))))
+	http.Handle("DELETE /trash", middlewareLog(h.userIdentity(http.HandlerFunc(h.trash.purgeTrash))))
+
+	http.Handle("GET /audit", middlewareLog(h.userIdentity(http.HandlerFunc(h.audit.getAudit))))
+
+	http.Handle("GET /search", middlewareLog(h.userIdentity(http.HandlerFunc(h.search.search))))
+
+	http.Handle("GET /me/recommendations", middlewareLog(h.userIdentity(http.HandlerFunc(h.rec.getRecommendations))))
+
+	http.Handle("GET /me/history", middlewareLog(h.userIdentity(http.HandlerFunc(h.history.getHistory))))
+	http.Handle("POST /me/history", middlewareLog(h.userIdentity(http.HandlerFunc(h.history.addViewing))))
+
+	http.Handle("GET /me/{list}", middlewareLog(h.userIdentity(http.HandlerFunc(h.list.getList))))
+	http.Handle("POST /me/{list}", middlewareLog(h.userIdentity(http.HandlerFunc(h.list.addToList))))
+	http.Handle("DELETE /me/{list}/{filmId}", middlewareLog(h.userIdentity(http.HandlerFunc(h.list.removeFromList))))
 
 	http.Handle("POST /sign-up", middlewareLog(http.HandlerFunc(h.user.signUp)))
 	http.Handle("POST /sign-in", middlewareLog(http.HandlerFunc(h.user.signIn)))
@@ -71,6 +145,337 @@ func newErrorResponse(w http.ResponseWriter, err error, message string, code int
 	http.Error(w, string(jsonData), code)
 }
 
+func parsePageRequest(req *http.Request) (domain.PageRequest, error) {
+	query := req.URL.Query()
+	page := domain.PageRequest{
+		Cursor:    query.Get("cursor"),
+		WithTotal: query.Get("withTotal") == "true",
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return page, errors.New("limit must be a positive number")
+		}
+		page.Limit = n
+	}
+
+	return page, nil
+}
+
+// parseCreditQuery reads the order and the page of a filmography or a cast.
+func parseCreditQuery(req *http.Request) (domain.CreditQuery, error) {
+	page, err := parsePageRequest(req)
+	if err != nil {
+		return domain.CreditQuery{}, err
+	}
+
+	query := req.URL.Query()
+	return domain.CreditQuery{
+		OrderBy: query.Get("orderBy"),
+		Desc:    query.Get("sort") == "desc",
+		Page:    page,
+	}, nil
+}
+
+// editor returns the user who makes the request and its ID, for the audit
+// log.
+func editor(req *http.Request) domain.Editor {
+	requestID, _ := req.Context().Value("requestID").(string)
+	return domain.Editor{
+		UserID:    req.Context().Value("userID").(int64),
+		RequestID: requestID,
+	}
+}
+
+func queryInt(query url.Values, name string) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+
+	return n, nil
+}
+
+func queryFloat(query url.Values, name string) (*float64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", name)
+	}
+
+	return &n, nil
+}
+
+func queryBool(query url.Values, name string) (*bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", name)
+	}
+
+	return &b, nil
+}
+
+func queryDate(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date like 2006-01-02", name)
+	}
+
+	return date, nil
+}
+
+// queryTime reads a moment given either as an RFC 3339 timestamp or as a
+// date, which means its midnight in UTC.
+func queryTime(query url.Values, name string) (time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a time like 2006-01-02T15:04:05Z or a date like 2006-01-02", name)
+	}
+
+	return date, nil
+}
+
+// uploadOverhead is the room left in the body of an upload request for the
+// multipart form around the file. The services check the size of the
+// uploaded file itself.
+const uploadOverhead = 1 << 20
+
+// readUpload returns the contents of the file sent in the multipart form
+// field name. The body is read up to maxSize, the largest file allowed, and
+// the room of the form.
+func readUpload(w http.ResponseWriter, req *http.Request, name string, maxSize int64) ([]byte, error) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxSize+uploadOverhead)
+	file, _, err := req.FormFile(name)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, domain.ErrImageTooLarge
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(file)
+}
+
+// mergePatchType is the media type of JSON merge patches (RFC 7396), the only
+// one PATCH accepts.
+const mergePatchType = "application/merge-patch+json"
+
+var errNotMergePatch = errors.New("patch must be sent as " + mergePatchType)
+
+// readMergePatch returns the JSON merge patch sent in the body. It must be a
+// JSON object.
+func readMergePatch(req *http.Request) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != mergePatchType {
+		return nil, errNotMergePatch
+	}
+
+	patch, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New("patch must be a JSON object")
+	}
+
+	return patch, nil
+}
+
+// newPatchErrorResponse responds to a patch which can't be read. A patch of
+// another media type is told by 415 and Accept-Patch.
+func newPatchErrorResponse(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, errNotMergePatch) {
+		w.Header().Set("Accept-Patch", mergePatchType)
+		newErrorResponse(w, err, "", http.StatusUnsupportedMediaType)
+		return
+	}
+	newErrorResponse(w, err, message, http.StatusBadRequest)
+}
+
+// newImageErrorResponse responds to a failed image upload. Images which are
+// too large or of a wrong type are told apart by the status code.
+func newImageErrorResponse(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrImageTooLarge):
+		newErrorResponse(w, err, "", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, domain.ErrImageTypeUnsupported):
+		newErrorResponse(w, err, "", http.StatusUnsupportedMediaType)
+	default:
+		newErrorResponse(w, err, message, http.StatusBadRequest)
+	}
+}
+
+// etag is the strong entity tag of a response body. The tag of a film or an
+// actor starts with their version, like "3-9f86d081884c7d65", so that it can
+// be sent back as If-Match. Lists have no version and pass 0.
+func etag(version int64, body []byte) string {
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:8])
+	if version == 0 {
+		return `"` + hash + `"`
+	}
+
+	return fmt.Sprintf(`"%d-%s"`, version, hash)
+}
+
+// ifMatch reads the versions of the film or actor the client expects to
+// change from the list of ETags of If-Match (RFC 9110). It returns nil if the
+// header isn't sent and domain.AnyVersion for *. Only the version of an ETag
+// is compared, so that a change of the cast or the rating doesn't refuse an
+// update. Weak ETags and ETags of no version never match, as the comparison
+// of If-Match is the strong one.
+func ifMatch(req *http.Request) (domain.Versions, error) {
+	value := strings.TrimSpace(req.Header.Get("If-Match"))
+	switch value {
+	case "":
+		return nil, nil
+	case "*":
+		return domain.Versions{domain.AnyVersion}, nil
+	}
+
+	errList := errors.New(`If-Match must be * or a list of ETags like "3-9f86d081884c7d65"`)
+	versions := domain.Versions{}
+	rest := value
+	for tags := 0; ; tags++ {
+		rest = strings.TrimLeft(rest, ", \t")
+		if rest == "" {
+			if tags == 0 {
+				return nil, errList
+			}
+			return versions, nil
+		}
+
+		var weak, ok bool
+		var tag string
+		rest, weak = strings.CutPrefix(rest, "W/")
+		if rest, ok = strings.CutPrefix(rest, `"`); !ok {
+			return nil, errList
+		}
+		if tag, rest, ok = strings.Cut(rest, `"`); !ok {
+			return nil, errList
+		}
+		if rest = strings.TrimLeft(rest, " \t"); rest != "" && rest[0] != ',' {
+			return nil, errList
+		}
+
+		tag, _, _ = strings.Cut(tag, "-")
+		version, err := strconv.ParseInt(tag, 10, 64)
+		if !weak && err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+}
+
+// notModified sets ETag and Last-Modified of a read of the catalog and
+// answers 304 Not Modified if the client already has the response. As in
+// RFC 9110, If-Modified-Since is only looked at without If-None-Match. A zero
+// modified leaves Last-Modified out.
+func notModified(w http.ResponseWriter, req *http.Request, tag string, modified time.Time) bool {
+	w.Header().Set("ETag", tag)
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		if !etagListed(match, tag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+		if err != nil || modified.IsZero() || modified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagListed tells whether tag is one of the comma separated ETags of
+// If-None-Match. Weak tags match too.
+func etagListed(list, tag string) bool {
+	for _, listed := range strings.Split(list, ",") {
+		listed = strings.TrimSpace(listed)
+		if listed == "*" || strings.TrimPrefix(listed, "W/") == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newVersionErrorResponse responds to a failed change of a film or an actor.
+// A stale or missing version is told by the status code.
+func newVersionErrorResponse(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrVersionMismatch):
+		newErrorResponse(w, err, "", http.StatusPreconditionFailed)
+	case errors.Is(err, domain.ErrVersionRequired):
+		newErrorResponse(w, err, "", http.StatusPreconditionRequired)
+	default:
+		newErrorResponse(w, err, message, http.StatusBadRequest)
+	}
+}
+
+// CastErrorResponse tells why each of the wrong credits of a cast change
+// can't be saved or removed.
+type CastErrorResponse struct {
+	Message string               `json:"message"`
+	Errors  []domain.CreditError `json:"errors"`
+} // @name CastErrorResponse
+
+// newCreditErrorResponse responds to a failed change of a cast. When some
+// credits are wrong, the body tells why for each of them. Removing an actor
+// who isn't in the cast is told by the status code.
+func newCreditErrorResponse(w http.ResponseWriter, err error, message string) {
+	var errs domain.CreditErrors
+	if !errors.As(err, &errs) {
+		newErrorResponse(w, err, message, http.StatusBadRequest)
+		return
+	}
+
+	code := http.StatusBadRequest
+	if len(errs) == 1 && errs[0].Reason == domain.CreditNotInCast {
+		code = http.StatusNotFound
+	}
+	log.Printf("HTTP %d - %s. Message: %s", code, err.Error(), message)
+	jsonData, _ := json.Marshal(CastErrorResponse{Message: message, Errors: errs})
+
+	http.Error(w, string(jsonData), code)
+}
+
 func (h *Handler) swaggerHandler(w http.ResponseWriter, r *http.Request) {
 	httpSwagger.WrapHandler(w, r)
 }
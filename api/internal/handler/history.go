@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+)
+
+type HistoryHandler struct {
+	ser *service.Service
+}
+
+// @Summary Get watched history
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Get the films the user watched, the latest viewings first. Every viewing of a film but the first one is a rewatch.
+// @ID get-history
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor of the next page"
+// @Param withTotal query boolean false "Include total count of viewings"
+// @Success 200 {object} domain.ViewingPage
+// @Failure 400
+// @Failure default
+// @Router /me/history [get]
+func (h *HistoryHandler) getHistory(w http.ResponseWriter, req *http.Request) {
+	page, err := parsePageRequest(req)
+	if err != nil {
+		newErrorResponse(w, err, "Wrong page params", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.ser.History.GetHistory(req.Context().Value("userID").(int64), page)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get history", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(history)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse history to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Log viewing
+// @Security ApiKeyAuth
+// @Tags history
+// @Description Log that the user watched a film on a day, today if watchedOn isn't set. A film can be logged again when it is rewatched. The film is taken off the watchlist.
+// @ID add-viewing
+// @Accept  json
+// @Produce  json
+// @Param input body domain.Viewing true "Viewing"
+// @Success 201 {object} domain.Viewing
+// @Failure 400
+// @Failure default
+// @Router /me/history [POST]
+func (h *HistoryHandler) addViewing(w http.ResponseWriter, req *http.Request) {
+	var viewing domain.Viewing
+	if err := json.NewDecoder(req.Body).Decode(&viewing); err != nil {
+		newErrorResponse(w, err, "Can't decode viewing from json", http.StatusBadRequest)
+		return
+	}
+
+	viewing, err := h.ser.History.AddViewing(req.Context().Value("userID").(int64), viewing)
+	if err != nil {
+		newErrorResponse(w, err, "Can't add viewing", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(viewing)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse viewing to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(jsonData)
+}
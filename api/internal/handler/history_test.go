@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistoryHandler_getHistory(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockHistory, userId int64, page domain.PageRequest)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		UserId               int64
+		page                 domain.PageRequest
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "?limit=2",
+			mockBehavior: func(r *mock_service.MockHistory, userId int64, page domain.PageRequest) {
+				r.EXPECT().GetHistory(userId, page).Return(domain.ViewingPage{
+					Viewings: []domain.Viewing{
+						{
+							ID:        8,
+							FilmID:    6,
+							Title:     "Брат",
+							WatchedOn: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+							Note:      sql.NullString{String: "с друзьями", Valid: true},
+							Rewatch:   true,
+						},
+						{
+							ID:        5,
+							FilmID:    6,
+							Title:     "Брат",
+							WatchedOn: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+						},
+					},
+					NextCursor: "eyJvIjoid2F0Y2hlZE9uIiwiZCI6dHJ1ZSwidiI6IjIwMjMtMTItMzEiLCJpZCI6NX0",
+				}, nil)
+			},
+			UserId:             10,
+			page:               domain.PageRequest{Limit: 2},
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "viewings": [
+        {
+            "id": 8,
+            "filmId": 6,
+            "title": "Брат",
+            "watchedOn": "2024-03-08T00:00:00Z",
+            "note": {"String": "с друзьями", "Valid": true},
+            "rewatch": true
+        },
+        {
+            "id": 5,
+            "filmId": 6,
+            "title": "Брат",
+            "watchedOn": "2023-12-31T00:00:00Z",
+            "note": {"String": "", "Valid": false},
+            "rewatch": false
+        }
+    ],
+    "nextCursor": "eyJvIjoid2F0Y2hlZE9uIiwiZCI6dHJ1ZSwidiI6IjIwMjMtMTItMzEiLCJpZCI6NX0"
+}`,
+		},
+		{
+			name:                 "Wrong limit",
+			addToUrl:             "?limit=-1",
+			mockBehavior:         func(r *mock_service.MockHistory, userId int64, page domain.PageRequest) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong page params"}`,
+		},
+		{
+			name:     "Can't get history",
+			addToUrl: "?cursor=abc",
+			mockBehavior: func(r *mock_service.MockHistory, userId int64, page domain.PageRequest) {
+				r.EXPECT().GetHistory(userId, page).Return(domain.ViewingPage{}, errors.New("cursor is not valid"))
+			},
+			UserId:               10,
+			page:                 domain.PageRequest{Cursor: "abc"},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get history"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockHistory(c)
+			test.mockBehavior(repo, test.UserId, test.page)
+
+			services := &service.Service{History: repo}
+			handler := HistoryHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /me/history", middlewareLog(http.HandlerFunc(handler.getHistory)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/me/history%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestHistoryHandler_addViewing(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockHistory, userId int64, viewing domain.Viewing)
+
+	watchedOn := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		inputBody            string
+		mockBehavior         mockBehavior
+		UserId               int64
+		viewing              domain.Viewing
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			inputBody: `{"filmId": 6, "watchedOn": "2024-03-08T00:00:00Z"}`,
+			mockBehavior: func(r *mock_service.MockHistory, userId int64, viewing domain.Viewing) {
+				r.EXPECT().AddViewing(userId, viewing).Return(domain.Viewing{
+					ID:        9,
+					FilmID:    6,
+					Title:     "Брат",
+					WatchedOn: watchedOn,
+					Rewatch:   true,
+				}, nil)
+			},
+			UserId:             10,
+			viewing:            domain.Viewing{FilmID: 6, WatchedOn: watchedOn},
+			expectedStatusCode: 201,
+			expectedResponseBody: `{
+    "id": 9,
+    "filmId": 6,
+    "title": "Брат",
+    "watchedOn": "2024-03-08T00:00:00Z",
+    "note": {"String": "", "Valid": false},
+    "rewatch": true
+}`,
+		},
+		{
+			name:                 "Wrong input",
+			inputBody:            `{"filmId": "Брат"}`,
+			mockBehavior:         func(r *mock_service.MockHistory, userId int64, viewing domain.Viewing) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't decode viewing from json"}`,
+		},
+		{
+			name:      "Can't add",
+			inputBody: `{"filmId": 6, "note": {"String": "в кино", "Valid": true}}`,
+			mockBehavior: func(r *mock_service.MockHistory, userId int64, viewing domain.Viewing) {
+				r.EXPECT().AddViewing(userId, viewing).Return(domain.Viewing{}, errors.New("viewing is not valid"))
+			},
+			UserId:               10,
+			viewing:              domain.Viewing{FilmID: 6, Note: sql.NullString{String: "в кино", Valid: true}},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't add viewing"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockHistory(c)
+			test.mockBehavior(repo, test.UserId, test.viewing)
+
+			services := &service.Service{History: repo}
+			handler := HistoryHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /me/history", middlewareLog(http.HandlerFunc(handler.addViewing)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", "/me/history", bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
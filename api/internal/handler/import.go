@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"mime"
+	"net/http"
+)
+
+type ImportHandler struct {
+	ser *service.Service
+}
+
+// maxImportSize bounds the body of an import request.
+const maxImportSize = 64 << 20
+
+// importFormats maps the content types of imported files to their formats.
+var importFormats = map[string]string{
+	"text/csv":             domain.ImportCSV,
+	"application/x-ndjson": domain.ImportNDJSON,
+	"application/ndjson":   domain.ImportNDJSON,
+}
+
+// @Summary Import catalog data
+// @Security ApiKeyAuth
+// @Tags import
+// @Description Import films, actors or credits from a CSV or NDJSON file sent as the body. You must have admin role.
+// @Description CSV columns of films are title, year, information, editorialRating, runtimeMinutes, releaseDate, countries and originalLanguage; of actors name, surname, patronymic, birthday, sex and information; of credits filmId, actorId, character, billing and type. NDJSON rows are the same as the bodies of the create requests.
+// @Description Exported films and actors can be imported back, their id, rating, votes and credits are skipped.
+// @Description All rows are saved in one transaction, if a row is wrong nothing is saved. In a dry run the rows are checked without saving them. The report tells the errors of each row and the IDs of the created films or actors.
+// @ID import-data
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param kind query string true "Kind of data" Enums(films,actors,credits)
+// @Param format query string false "Format of the file, taken from Content-Type if not set" Enums(csv,ndjson)
+// @Param dryRun query boolean false "Only check the rows"
+// @Success 200 {object} domain.ImportReport
+// @Success 201 {object} domain.ImportReport
+// @Failure 400 {object} domain.ImportReport
+// @Failure default
+// @Router /import [POST]
+func (h *ImportHandler) importData(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	query := req.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		format = importFormats[mediaType]
+	}
+
+	body := http.MaxBytesReader(w, req.Body, maxImportSize)
+	report, err := h.ser.Import.Import(editor(req), query.Get("kind"), format, body, query.Get("dryRun") == "true")
+	if err != nil {
+		newErrorResponse(w, err, "Can't import data", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse import report to json", http.StatusInternalServerError)
+		return
+	}
+
+	code := http.StatusOK
+	if report.Committed {
+		code = http.StatusCreated
+	} else if !report.DryRun && report.Failed != 0 {
+		code = http.StatusBadRequest
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(jsonData)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestImportHandler_importData(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockImport, kind, format string, dryRun bool)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		contentType          string
+		inputBody            string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		kind                 string
+		format               string
+		dryRun               bool
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:        "Ok",
+			addToUrl:    "?kind=films",
+			contentType: "text/csv; charset=utf-8",
+			inputBody:   "title,year\nБрат,1997\n",
+			mockBehavior: func(r *mock_service.MockImport, kind, format string, dryRun bool) {
+				r.EXPECT().Import(domain.Editor{UserID: 10}, kind, format, gomock.Any(), dryRun).Return(domain.ImportReport{
+					Kind:      domain.ImportFilms,
+					Committed: true,
+					Total:     1,
+					Rows:      []domain.ImportRow{{Line: 2, ID: 6}},
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			kind:               domain.ImportFilms,
+			format:             domain.ImportCSV,
+			expectedStatusCode: 201,
+			expectedResponseBody: `{
+    "kind": "films",
+    "dryRun": false,
+    "committed": true,
+    "total": 1,
+    "failed": 0,
+    "rows": [{"line": 2, "id": 6}]
+}`,
+		},
+		{
+			name:      "Ok dry run",
+			addToUrl:  "?kind=actors&format=ndjson&dryRun=true",
+			inputBody: `{"name": "Сергей"}`,
+			mockBehavior: func(r *mock_service.MockImport, kind, format string, dryRun bool) {
+				r.EXPECT().Import(domain.Editor{UserID: 10}, kind, format, gomock.Any(), dryRun).Return(domain.ImportReport{
+					Kind:   domain.ImportActors,
+					DryRun: true,
+					Total:  1,
+					Failed: 1,
+					Rows:   []domain.ImportRow{{Line: 1, Errors: []string{"actor is not valid"}}},
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			kind:               domain.ImportActors,
+			format:             domain.ImportNDJSON,
+			dryRun:             true,
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "kind": "actors",
+    "dryRun": true,
+    "committed": false,
+    "total": 1,
+    "failed": 1,
+    "rows": [{"line": 1, "errors": ["actor is not valid"]}]
+}`,
+		},
+		{
+			name:        "Wrong rows",
+			addToUrl:    "?kind=credits",
+			contentType: "application/x-ndjson",
+			inputBody:   `{"filmId": 6, "actorId": 100}`,
+			mockBehavior: func(r *mock_service.MockImport, kind, format string, dryRun bool) {
+				r.EXPECT().Import(domain.Editor{UserID: 10}, kind, format, gomock.Any(), dryRun).Return(domain.ImportReport{
+					Kind:   domain.ImportCredits,
+					Total:  1,
+					Failed: 1,
+					Rows:   []domain.ImportRow{{Line: 1, Errors: []string{"film or actor doesn't exist"}}},
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			kind:               domain.ImportCredits,
+			format:             domain.ImportNDJSON,
+			expectedStatusCode: 400,
+			expectedResponseBody: `{
+    "kind": "credits",
+    "dryRun": false,
+    "committed": false,
+    "total": 1,
+    "failed": 1,
+    "rows": [{"line": 1, "errors": ["film or actor doesn't exist"]}]
+}`,
+		},
+		{
+			name:         "Not admin",
+			addToUrl:     "?kind=films&format=csv",
+			mockBehavior: func(r *mock_service.MockImport, kind, format string, dryRun bool) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name:      "Can't import",
+			addToUrl:  "?kind=films&format=csv",
+			inputBody: "name,year\n",
+			mockBehavior: func(r *mock_service.MockImport, kind, format string, dryRun bool) {
+				r.EXPECT().Import(domain.Editor{UserID: 10}, kind, format, gomock.Any(), dryRun).Return(domain.ImportReport{}, errors.New(`unknown column "name"`))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			kind:                 domain.ImportFilms,
+			format:               domain.ImportCSV,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't import data"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockImport(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.kind, test.format, test.dryRun)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Import: repo, User: repo2}
+			handler := ImportHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /import", middlewareLog(http.HandlerFunc(handler.importData)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/import%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, bytes.NewBufferString(test.inputBody))
+			req.Header.Set("Content-Type", test.contentType)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
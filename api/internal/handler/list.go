@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type ListHandler struct {
+	ser *service.Service
+}
+
+type ListData struct {
+	FilmID int64 `json:"filmId"`
+} // @name ListData
+
+// listName returns the name of the list in the path of req. It responds
+// with 404 if there is no such list.
+func listName(w http.ResponseWriter, req *http.Request) (string, bool) {
+	list := req.PathValue("list")
+	if !domain.IsList(list) {
+		newErrorResponse(w, fmt.Errorf("there is no list %q", list), "There is no such list", http.StatusNotFound)
+		return "", false
+	}
+
+	return list, true
+}
+
+// @Summary Get personal list
+// @Security ApiKeyAuth
+// @Tags lists
+// @Description Get films in the watchlist or favorites of the user with the time they were added at.
+// @ID get-list
+// @Accept  json
+// @Produce  json
+// @Param list path string true "List" Enums(watchlist,favorites)
+// @Param orderBy query string false "Sort by params" Enums(addedAt,title,year,rating)
+// @Param sort query string false "Sort list by desc or asc, the films added last go first by default" Enums(desc,asc)
+// @Success 200 {object} []domain.ListEntry
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /me/{list} [get]
+func (l *ListHandler) getList(w http.ResponseWriter, req *http.Request) {
+	list, ok := listName(w, req)
+	if !ok {
+		return
+	}
+	query := req.URL.Query()
+
+	entries, err := l.ser.List.GetList(req.Context().Value("userID").(int64), list,
+		query.Get("orderBy"), query.Get("sort") != "asc")
+	if err != nil {
+		newErrorResponse(w, err, "Can't get list", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(entries)
+	if err != nil {
+		newErrorResponse(w, err, "Error when parse list to json.", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Add film to personal list
+// @Security ApiKeyAuth
+// @Tags lists
+// @Description Add film to the watchlist or favorites of the user. Adding it again keeps the time it was first added at.
+// @ID add-to-list
+// @Accept  json
+// @Produce  json
+// @Param list path string true "List" Enums(watchlist,favorites)
+// @Param input body ListData true "Film"
+// @Success 201
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /me/{list} [POST]
+func (l *ListHandler) addToList(w http.ResponseWriter, req *http.Request) {
+	list, ok := listName(w, req)
+	if !ok {
+		return
+	}
+
+	var data ListData
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		newErrorResponse(w, err, "Wrong input form", http.StatusBadRequest)
+		return
+	}
+	if data.FilmID <= 0 {
+		newErrorResponse(w, errors.New("filmId is not set"), "Wrong input form", http.StatusBadRequest)
+		return
+	}
+
+	err := l.ser.List.AddToList(req.Context().Value("userID").(int64), data.FilmID, list)
+	if err != nil {
+		newErrorResponse(w, err, "Can't add film to list", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// @Summary Remove film from personal list
+// @Security ApiKeyAuth
+// @Tags lists
+// @Description Remove film from the watchlist or favorites of the user.
+// @ID remove-from-list
+// @Accept  json
+// @Produce  json
+// @Param list path string true "List" Enums(watchlist,favorites)
+// @Param filmId path int true "Film ID"
+// @Success 204
+// @Failure 400
+// @Failure 404
+// @Failure default
+// @Router /me/{list}/{filmId} [DELETE]
+func (l *ListHandler) removeFromList(w http.ResponseWriter, req *http.Request) {
+	list, ok := listName(w, req)
+	if !ok {
+		return
+	}
+
+	filmId, err := strconv.ParseInt(req.PathValue("filmId"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	err = l.ser.List.RemoveFromList(req.Context().Value("userID").(int64), filmId, list)
+	if err != nil {
+		newErrorResponse(w, err, "Can't remove film from list", http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
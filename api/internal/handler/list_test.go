@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListHandler_getList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockList, userId int64)
+
+	addedAt := time.Date(2024, 3, 8, 19, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		UserId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/watchlist",
+			mockBehavior: func(r *mock_service.MockList, userId int64) {
+				r.EXPECT().GetList(userId, domain.ListWatchlist, "", true).Return([]domain.ListEntry{
+					{
+						Film:    domain.Film{ID: 6, Title: "Брат", Year: 1997},
+						AddedAt: addedAt,
+					},
+				}, nil)
+			},
+			UserId:             10,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {
+        "id": 6,
+        "title": "Брат",
+        "year": 1997,
+        "information": {"String": "", "Valid": false},
+        "rating": {"Float64": 0, "Valid": false},
+        "votes": 0,
+        "editorialRating": {"Float64": 0, "Valid": false},
+        "runtimeMinutes": {"Int64": 0, "Valid": false},
+        "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+        "countries": null,
+        "originalLanguage": {"String": "", "Valid": false},
+        "addedAt": "2024-03-08T19:30:00Z"
+    }
+]`,
+		},
+		{
+			name:     "Ok ordered by title",
+			addToUrl: "/favorites?orderBy=title&sort=asc",
+			mockBehavior: func(r *mock_service.MockList, userId int64) {
+				r.EXPECT().GetList(userId, domain.ListFavorites, "title", false).Return([]domain.ListEntry{}, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "No such list",
+			addToUrl:             "/seen",
+			mockBehavior:         func(r *mock_service.MockList, userId int64) {},
+			UserId:               10,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"There is no such list"}`,
+		},
+		{
+			name:     "Can't get list",
+			addToUrl: "/watchlist?orderBy=title",
+			mockBehavior: func(r *mock_service.MockList, userId int64) {
+				r.EXPECT().GetList(userId, domain.ListWatchlist, "title", true).Return(nil, errors.New(""))
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get list"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockList(c)
+			test.mockBehavior(repo, test.UserId)
+
+			services := &service.Service{List: repo}
+			handler := ListHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /me/{list}", middlewareLog(http.HandlerFunc(handler.getList)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/me%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestListHandler_addToList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockList, userId, filmId int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		inputBody            string
+		mockBehavior         mockBehavior
+		UserId               int64
+		FilmId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:      "Ok",
+			addToUrl:  "/watchlist",
+			inputBody: `{"filmId": 6}`,
+			mockBehavior: func(r *mock_service.MockList, userId, filmId int64) {
+				r.EXPECT().AddToList(userId, filmId, domain.ListWatchlist).Return(nil)
+			},
+			UserId:               10,
+			FilmId:               6,
+			expectedStatusCode:   201,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "Wrong input form",
+			addToUrl:             "/favorites",
+			inputBody:            `{"film": 6}`,
+			mockBehavior:         func(r *mock_service.MockList, userId, filmId int64) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong input form"}`,
+		},
+		{
+			name:      "Can't add",
+			addToUrl:  "/favorites",
+			inputBody: `{"filmId": 100}`,
+			mockBehavior: func(r *mock_service.MockList, userId, filmId int64) {
+				r.EXPECT().AddToList(userId, filmId, domain.ListFavorites).Return(errors.New(""))
+			},
+			UserId:               10,
+			FilmId:               100,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't add film to list"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockList(c)
+			test.mockBehavior(repo, test.UserId, test.FilmId)
+
+			services := &service.Service{List: repo}
+			handler := ListHandler{services}
+
+			// Init Endpoint
+			http.Handle("POST /me/{list}", middlewareLog(http.HandlerFunc(handler.addToList)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/me%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("POST", url, bytes.NewBufferString(test.inputBody))
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
+
+func TestListHandler_removeFromList(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockList, userId, filmId int64)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		UserId               int64
+		FilmId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/watchlist/6",
+			mockBehavior: func(r *mock_service.MockList, userId, filmId int64) {
+				r.EXPECT().RemoveFromList(userId, filmId, domain.ListWatchlist).Return(nil)
+			},
+			UserId:               10,
+			FilmId:               6,
+			expectedStatusCode:   204,
+			expectedResponseBody: ``,
+		},
+		{
+			name:                 "No such list",
+			addToUrl:             "/seen/6",
+			mockBehavior:         func(r *mock_service.MockList, userId, filmId int64) {},
+			UserId:               10,
+			expectedStatusCode:   404,
+			expectedResponseBody: `{"message":"There is no such list"}`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/watchlist/six",
+			mockBehavior:         func(r *mock_service.MockList, userId, filmId int64) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:     "Can't remove",
+			addToUrl: "/favorites/6",
+			mockBehavior: func(r *mock_service.MockList, userId, filmId int64) {
+				r.EXPECT().RemoveFromList(userId, filmId, domain.ListFavorites).Return(errors.New(""))
+			},
+			UserId:               10,
+			FilmId:               6,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't remove film from list"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockList(c)
+			test.mockBehavior(repo, test.UserId, test.FilmId)
+
+			services := &service.Service{List: repo}
+			handler := ListHandler{services}
+
+			// Init Endpoint
+			http.Handle("DELETE /me/{list}/{filmId}", middlewareLog(http.HandlerFunc(handler.removeFromList)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/me%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("DELETE", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			if w.Body.String() != test.expectedResponseBody {
+				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+			}
+		})
+	}
+}
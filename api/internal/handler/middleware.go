@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"kinoteka/internal/metrics"
 	"log"
@@ -47,7 +49,25 @@ func (h *Handler) userIdentity(next http.Handler) http.Handler {
 			return
 		}
 
+		requestID := r.Header.Get("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
 		ctx := context.WithValue(r.Context(), "userID", userId)
+		ctx = context.WithValue(ctx, "requestID", requestID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// maxRequestIDLength bounds the request IDs clients send, longer ones are
+// replaced.
+const maxRequestIDLength = 128
+
+// newRequestID makes up an ID for a request which came without one.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
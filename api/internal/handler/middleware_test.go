@@ -25,6 +25,7 @@ func TestHandler_userIdentity(t *testing.T) {
 		headerName           string
 		headerValue          string
 		token                string
+		requestID            string
 		mockBehavior         mockBehavior
 		expectedStatusCode   int
 		expectedResponseBody string
@@ -40,6 +41,18 @@ func TestHandler_userIdentity(t *testing.T) {
 			expectedStatusCode:   200,
 			expectedResponseBody: "",
 		},
+		{
+			name:        "Ok with request ID",
+			headerName:  "Authorization",
+			headerValue: "Bearer token",
+			token:       "token",
+			requestID:   "4f1c9b2e",
+			mockBehavior: func(r *mock_service.MockUser, token string) {
+				r.EXPECT().ParseToken(token).Return(int64(10), nil)
+			},
+			expectedStatusCode:   200,
+			expectedResponseBody: "",
+		},
 		{
 			name:                 "Invalid Header Name",
 			headerName:           "",
@@ -100,6 +113,9 @@ func TestHandler_userIdentity(t *testing.T) {
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("GET", "/identity", nil)
 			req.Header.Set(test.headerName, test.headerValue)
+			if test.requestID != "" {
+				req.Header.Set("X-Request-ID", test.requestID)
+			}
 
 			http.DefaultServeMux.ServeHTTP(w, req)
 
@@ -108,6 +124,9 @@ func TestHandler_userIdentity(t *testing.T) {
 			if w.Body.String() != test.expectedResponseBody {
 				assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
 			}
+			if test.requestID != "" {
+				assert.Equal(t, w.Header().Get("X-Request-ID"), test.requestID)
+			}
 		})
 	}
 }
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+	"strconv"
+)
+
+type RecommendationHandler struct {
+	ser *service.Service
+}
+
+// @Summary Get recommendations
+// @Security ApiKeyAuth
+// @Tags recommendations
+// @Description Get films for the user to watch next, best first. Films are picked by the actors they share with the films the user liked, by the votes of users who liked the same films and by release year. Users who haven't liked a film yet get the films rated best. Films the user rated are left out.
+// @ID get-recommendations
+// @Accept  json
+// @Produce  json
+// @Param limit query int false "Max number of films, 20 by default, up to 100"
+// @Success 200 {array} domain.Recommendation
+// @Failure 400
+// @Failure default
+// @Router /me/recommendations [get]
+func (h *RecommendationHandler) getRecommendations(w http.ResponseWriter, req *http.Request) {
+	limit, err := queryInt(req.URL.Query(), "limit")
+	if err != nil {
+		newErrorResponse(w, err, "Wrong query params", http.StatusBadRequest)
+		return
+	}
+
+	var recommendations []domain.Recommendation
+	recommendations, err = h.ser.Recommendation.GetRecommendations(req.Context().Value("userID").(int64), limit)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get recommendations", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(recommendations)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse recommendations to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Get similar films
+// @Security ApiKeyAuth
+// @Tags recommendations
+// @Description Get the films most similar to the film by shared cast, by the votes of users who liked both and by release year, best first.
+// @ID get-similar-films
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Film ID"
+// @Param limit query int false "Max number of films, 20 by default, up to 100"
+// @Success 200 {array} domain.Recommendation
+// @Failure 400
+// @Failure default
+// @Router /film/{id}/similar [get]
+func (h *RecommendationHandler) getSimilarFilms(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.ParseInt(req.PathValue("id"), 10, 64)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse id from path", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := queryInt(req.URL.Query(), "limit")
+	if err != nil {
+		newErrorResponse(w, err, "Wrong query params", http.StatusBadRequest)
+		return
+	}
+
+	films, err := h.ser.Recommendation.GetSimilarFilms(id, limit)
+	if err != nil {
+		newErrorResponse(w, err, "Can't get similar films", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(films)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse similar films to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecommendationHandler_getRecommendations(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockRecommendation, userId int64, limit int)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		UserId               int64
+		limit                int
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "?limit=1",
+			mockBehavior: func(r *mock_service.MockRecommendation, userId int64, limit int) {
+				r.EXPECT().GetRecommendations(userId, limit).Return([]domain.Recommendation{
+					{
+						FilmID:      7,
+						Title:       "Брат 2",
+						Year:        2000,
+						Score:       2.25,
+						Explanation: "shares 2 actors with Брат",
+					},
+				}, nil)
+			},
+			UserId:             10,
+			limit:              1,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {
+        "filmId": 7,
+        "title": "Брат 2",
+        "year": 2000,
+        "score": 2.25,
+        "explanation": "shares 2 actors with Брат"
+    }
+]`,
+		},
+		{
+			name:                 "Wrong limit",
+			addToUrl:             "?limit=ten",
+			mockBehavior:         func(r *mock_service.MockRecommendation, userId int64, limit int) {},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong query params"}`,
+		},
+		{
+			name: "Can't get recommendations",
+			mockBehavior: func(r *mock_service.MockRecommendation, userId int64, limit int) {
+				r.EXPECT().GetRecommendations(userId, limit).Return(nil, errors.New(""))
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get recommendations"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRecommendation(c)
+			test.mockBehavior(repo, test.UserId, test.limit)
+
+			services := &service.Service{Recommendation: repo}
+			handler := RecommendationHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /me/recommendations", middlewareLog(http.HandlerFunc(handler.getRecommendations)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/me/recommendations%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestRecommendationHandler_getSimilarFilms(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockRecommendation, filmId int64, limit int)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		FilmId               int64
+		limit                int
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: "/6/similar",
+			mockBehavior: func(r *mock_service.MockRecommendation, filmId int64, limit int) {
+				r.EXPECT().GetSimilarFilms(filmId, limit).Return([]domain.Recommendation{
+					{
+						FilmID:      7,
+						Title:       "Брат 2",
+						Year:        2000,
+						Score:       3.1,
+						Explanation: "shares 2 actors with Брат, liked by 3 users who liked Брат",
+					},
+				}, nil)
+			},
+			FilmId:             6,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {
+        "filmId": 7,
+        "title": "Брат 2",
+        "year": 2000,
+        "score": 3.1,
+        "explanation": "shares 2 actors with Брат, liked by 3 users who liked Брат"
+    }
+]`,
+		},
+		{
+			name:     "Ok no similar films",
+			addToUrl: "/6/similar?limit=5",
+			mockBehavior: func(r *mock_service.MockRecommendation, filmId int64, limit int) {
+				r.EXPECT().GetSimilarFilms(filmId, limit).Return([]domain.Recommendation{}, nil)
+			},
+			FilmId:               6,
+			limit:                5,
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:                 "Bad url",
+			addToUrl:             "/six/similar",
+			mockBehavior:         func(r *mock_service.MockRecommendation, filmId int64, limit int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't parse id from path"}`,
+		},
+		{
+			name:     "Can't get similar films",
+			addToUrl: "/100/similar",
+			mockBehavior: func(r *mock_service.MockRecommendation, filmId int64, limit int) {
+				r.EXPECT().GetSimilarFilms(filmId, limit).Return(nil, errors.New("there is no film with id = 100"))
+			},
+			FilmId:               100,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get similar films"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockRecommendation(c)
+			test.mockBehavior(repo, test.FilmId, test.limit)
+
+			services := &service.Service{Recommendation: repo}
+			handler := RecommendationHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /film/{id}/similar", middlewareLog(http.HandlerFunc(handler.getSimilarFilms)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/film%s", test.addToUrl)
+			req := httptest.NewRequest("GET", url, nil)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+)
+
+type SearchHandler struct {
+	ser *service.Service
+}
+
+// @Summary Full-text search
+// @Security ApiKeyAuth
+// @Tags search
+// @Description Search films by title and information and actors by name and information.
+// @Description Russian word forms are matched, so "брата" finds "Брат". Hits are ordered by rank.
+// @ID search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Search query"
+// @Param limit query int false "Max number of hits"
+// @Success 200 {object} domain.SearchResult
+// @Failure 400
+// @Failure 500
+// @Failure default
+// @Router /search [get]
+func (h *SearchHandler) search(w http.ResponseWriter, req *http.Request) {
+	limit, err := queryInt(req.URL.Query(), "limit")
+	if err != nil {
+		newErrorResponse(w, err, "Wrong query params", http.StatusBadRequest)
+		return
+	}
+
+	var result domain.SearchResult
+	result, err = h.ser.Search.Search(req.URL.Query().Get("q"), limit)
+	if err != nil {
+		newErrorResponse(w, err, "Can't search", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(result)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse search result to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSearchHandler_search(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockSearch, query string, limit int)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		query                string
+		limit                int
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name:     "Ok",
+			addToUrl: `?q=` + url.QueryEscape("брата"),
+			mockBehavior: func(r *mock_service.MockSearch, query string, limit int) {
+				r.EXPECT().Search(query, limit).Return(domain.SearchResult{
+					Hits: []domain.SearchHit{
+						{
+							Type:     domain.SearchHitFilm,
+							ID:       6,
+							Title:    "Брат",
+							Headline: "<b>Брат</b> — 1:40",
+							Rank:     0.6079271,
+						},
+						{
+							Type:     domain.SearchHitFilm,
+							ID:       7,
+							Title:    "Брат 2",
+							Headline: "<b>Брат</b> 2 — 2:07",
+							Rank:     0.6079271,
+						},
+					},
+				}, nil)
+			},
+			query:              "брата",
+			expectedStatusCode: 200,
+			expectedResponseBody: `{
+    "hits": [
+        {
+            "type": "film",
+            "id": 6,
+            "title": "Брат",
+            "headline": "<b>Брат</b> — 1:40",
+            "rank": 0.6079271
+        },
+        {
+            "type": "film",
+            "id": 7,
+            "title": "Брат 2",
+            "headline": "<b>Брат</b> 2 — 2:07",
+            "rank": 0.6079271
+        }
+    ]
+}`,
+		},
+		{
+			name:     "Percent in title",
+			addToUrl: `?q=100`,
+			mockBehavior: func(r *mock_service.MockSearch, query string, limit int) {
+				r.EXPECT().Search(query, limit).Return(domain.SearchResult{
+					Hits: []domain.SearchHit{{Type: domain.SearchHitFilm, ID: 8, Title: "100%d", Headline: "<b>100</b>%d"}},
+				}, nil)
+			},
+			query:                "100",
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"hits": [{"type": "film", "id": 8, "title": "100%d", "headline": "<b>100</b>%d", "rank": 0}]}`,
+		},
+		{
+			name:     "Empty query",
+			addToUrl: ``,
+			mockBehavior: func(r *mock_service.MockSearch, query string, limit int) {
+				r.EXPECT().Search(query, limit).Return(domain.SearchResult{}, errors.New("search query is empty"))
+			},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't search"}`,
+		},
+		{
+			name:                 "Wrong limit",
+			addToUrl:             `?q=brat&limit=ten`,
+			mockBehavior:         func(r *mock_service.MockSearch, query string, limit int) {},
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong query params"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockSearch(c)
+			test.mockBehavior(repo, test.query, test.limit)
+
+			services := &service.Service{Search: repo}
+			handler := SearchHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /search", middlewareLog(http.HandlerFunc(handler.search)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/search%s", test.addToUrl)
+			req := httptest.NewRequest("GET", url, nil)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
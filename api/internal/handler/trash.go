@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	"net/http"
+	"time"
+)
+
+type TrashHandler struct {
+	ser *service.Service
+}
+
+// @Summary Get trash
+// @Security ApiKeyAuth
+// @Tags trash
+// @Description Get deleted films and actors, the last deleted first. You must have admin role.
+// @ID get-trash
+// @Produce  json
+// @Success 200 {array} domain.TrashItem
+// @Failure 400
+// @Failure 500
+// @Failure default
+// @Router /trash [get]
+func (h *TrashHandler) getTrash(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	var items []domain.TrashItem
+	items, err = h.ser.Trash.GetTrash()
+	if err != nil {
+		newErrorResponse(w, err, "Can't get trash", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(items)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse trash to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
+
+// @Summary Purge trash
+// @Security ApiKeyAuth
+// @Tags trash
+// @Description Delete for good the films and actors which were deleted before the retention window, with their credits, votes and images. You must have admin role.
+// @Description A longer window can be given, a shorter one is refused.
+// @ID purge-trash
+// @Produce  json
+// @Param olderThan query string false "Purge items deleted longer ago than this, like 720h"
+// @Success 200 {object} domain.PurgeReport
+// @Failure 400
+// @Failure default
+// @Router /trash [DELETE]
+func (h *TrashHandler) purgeTrash(w http.ResponseWriter, req *http.Request) {
+	isAdmin, err := h.ser.User.IsAdmin(req.Context().Value("userID").(int64))
+	if err != nil {
+		newErrorResponse(w, err, "", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin {
+		newErrorResponse(w, errors.New("you don't have enough permissions"), "", http.StatusBadRequest)
+		return
+	}
+
+	var olderThan time.Duration
+	if value := req.URL.Query().Get("olderThan"); value != "" {
+		olderThan, err = time.ParseDuration(value)
+		if err != nil || olderThan <= 0 {
+			newErrorResponse(w, errors.New("olderThan must be a positive duration like 720h"), "Wrong query params", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.ser.Trash.PurgeTrash(editor(req), olderThan)
+	if err != nil {
+		newErrorResponse(w, err, "Can't purge trash", http.StatusBadRequest)
+		return
+	}
+
+	jsonData, err := json.Marshal(report)
+	if err != nil {
+		newErrorResponse(w, err, "Can't parse purge report to json", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonData)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/service"
+	mock_service "kinoteka/internal/service/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTrashHandler_getTrash(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockTrash)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockTrash) {
+				r.EXPECT().GetTrash().Return([]domain.TrashItem{
+					{Type: domain.TrashFilm, ID: 6, Title: "Брат", DeletedAt: deletedAt},
+					{Type: domain.TrashActor, ID: 12, Title: "Сергей Бодров", DeletedAt: deletedAt.Add(-time.Hour)},
+				}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:             10,
+			expectedStatusCode: 200,
+			expectedResponseBody: `[
+    {"type": "film", "id": 6, "title": "Брат", "deletedAt": "2024-03-01T12:00:00Z"},
+    {"type": "actor", "id": 12, "title": "Сергей Бодров", "deletedAt": "2024-03-01T11:00:00Z"}
+]`,
+		},
+		{
+			name: "Empty",
+			mockBehavior: func(r *mock_service.MockTrash) {
+				r.EXPECT().GetTrash().Return([]domain.TrashItem{}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   200,
+			expectedResponseBody: `[]`,
+		},
+		{
+			name:         "Not admin",
+			mockBehavior: func(r *mock_service.MockTrash) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+		{
+			name: "Can't get trash",
+			mockBehavior: func(r *mock_service.MockTrash) {
+				r.EXPECT().GetTrash().Return(nil, errors.New("connection refused"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't get trash"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockTrash(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Trash: repo, User: repo2}
+			handler := TrashHandler{services}
+
+			// Init Endpoint
+			http.Handle("GET /trash", middlewareLog(http.HandlerFunc(handler.getTrash)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("GET", "/trash", nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
+
+func TestTrashHandler_purgeTrash(t *testing.T) {
+	// Init Test Table
+	type mockBehavior func(r *mock_service.MockTrash, olderThan time.Duration)
+	type mockBehavior2 func(r *mock_service.MockUser, id int64)
+
+	deletedBefore := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		addToUrl             string
+		mockBehavior         mockBehavior
+		mockBehaviorAdmin    mockBehavior2
+		UserId               int64
+		olderThan            time.Duration
+		expectedStatusCode   int
+		expectedResponseBody string
+	}{
+		{
+			name: "Ok",
+			mockBehavior: func(r *mock_service.MockTrash, olderThan time.Duration) {
+				r.EXPECT().PurgeTrash(domain.Editor{UserID: 10}, olderThan).Return(domain.PurgeReport{DeletedBefore: deletedBefore, Films: 2, Actors: 1}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"deletedBefore": "2024-03-01T12:00:00Z", "films": 2, "actors": 1}`,
+		},
+		{
+			name:     "Ok longer window",
+			addToUrl: "?olderThan=2160h",
+			mockBehavior: func(r *mock_service.MockTrash, olderThan time.Duration) {
+				r.EXPECT().PurgeTrash(domain.Editor{UserID: 10}, olderThan).Return(domain.PurgeReport{DeletedBefore: deletedBefore}, nil)
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			olderThan:            90 * 24 * time.Hour,
+			expectedStatusCode:   200,
+			expectedResponseBody: `{"deletedBefore": "2024-03-01T12:00:00Z", "films": 0, "actors": 0}`,
+		},
+		{
+			name:     "Window too short",
+			addToUrl: "?olderThan=1h",
+			mockBehavior: func(r *mock_service.MockTrash, olderThan time.Duration) {
+				r.EXPECT().PurgeTrash(domain.Editor{UserID: 10}, olderThan).Return(domain.PurgeReport{},
+					errors.New("items can't be purged before the retention window ends"))
+			},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			olderThan:            time.Hour,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Can't purge trash"}`,
+		},
+		{
+			name:         "Wrong window",
+			addToUrl:     "?olderThan=month",
+			mockBehavior: func(r *mock_service.MockTrash, olderThan time.Duration) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(true, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"Wrong query params"}`,
+		},
+		{
+			name:         "Not admin",
+			mockBehavior: func(r *mock_service.MockTrash, olderThan time.Duration) {},
+			mockBehaviorAdmin: func(r *mock_service.MockUser, id int64) {
+				r.EXPECT().IsAdmin(id).Return(false, nil)
+			},
+			UserId:               10,
+			expectedStatusCode:   400,
+			expectedResponseBody: `{"message":"you don't have enough permissions"}`,
+		},
+	}
+
+	for _, test := range tests {
+		http.DefaultServeMux = http.NewServeMux()
+		t.Run(test.name, func(t *testing.T) {
+			// Init Dependencies
+			c := gomock.NewController(t)
+			defer c.Finish()
+
+			repo := mock_service.NewMockTrash(c)
+			repo2 := mock_service.NewMockUser(c)
+
+			test.mockBehavior(repo, test.olderThan)
+			test.mockBehaviorAdmin(repo2, test.UserId)
+
+			services := &service.Service{Trash: repo, User: repo2}
+			handler := TrashHandler{services}
+
+			// Init Endpoint
+			http.Handle("DELETE /trash", middlewareLog(http.HandlerFunc(handler.purgeTrash)))
+
+			// Create Request
+			w := httptest.NewRecorder()
+			url := fmt.Sprintf("/trash%s", test.addToUrl)
+			ctx := context.WithValue(context.Background(), "userID", test.UserId)
+			req := httptest.NewRequest("DELETE", url, nil)
+			req = req.WithContext(ctx)
+
+			// Call your handler directly, passing in the ResponseRecorder and Request
+			http.DefaultServeMux.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, w.Code, test.expectedStatusCode)
+			assert.JSONEq(t, w.Body.String(), test.expectedResponseBody)
+		})
+	}
+}
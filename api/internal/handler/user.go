@@ -3,7 +3,6 @@ package handler
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"kinoteka/internal/domain"
 	"kinoteka/internal/service"
 	"net/http"
@@ -61,7 +60,7 @@ func (h *UserHandler) signIn(w http.ResponseWriter, req *http.Request) {
 	jsonData, err := json.Marshal(TokenResponse{Token: token})
 
 	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, string(jsonData))
+	w.Write(jsonData)
 }
 
 // @Summary SignUp
@@ -1,51 +1,284 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"kinoteka/internal/domain"
 	"kinoteka/internal/storage"
 )
 
 type actorService struct {
-	s storage.ActorStorage
+	s              storage.ActorStorage
+	images         images
+	events         CatalogListener
+	requireVersion bool
 }
 
-func NewActorService(s storage.ActorStorage) Actor {
+func NewActorService(s storage.ActorStorage, blobs storage.BlobStore, cfg Config, events CatalogListener) Actor {
 	return &actorService{
-		s: s,
+		s:              s,
+		images:         images{blobs: blobs, maxSize: cfg.MaxImageSize},
+		events:         events,
+		requireVersion: cfg.RequireVersion,
 	}
 }
 
 func (a *actorService) GetActorsWithFilms() ([]domain.ActorFilm, error) {
-	return a.s.GetActorsWithFilms()
+	actors, err := a.s.GetActorsWithFilms()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range actors {
+		actors[i].Actor.Photo = a.images.image(actors[i].Actor.PhotoKey)
+		for j := range actors[i].Films {
+			actors[i].Films[j].Poster = a.images.image(actors[i].Films[j].PosterKey)
+		}
+	}
+
+	return actors, nil
 }
 
-func (a *actorService) GetActors() ([]domain.Actor, error) {
-	return a.s.GetActors()
+func (a *actorService) GetActorsPage(page domain.PageRequest) (domain.ActorPage, error) {
+	limit := pageLimit(page.Limit)
+
+	var afterID int64
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return domain.ActorPage{}, err
+		}
+		afterID = cursor.ID
+	}
+
+	// The time of the last change is read before the actors, as in
+	// GetFilms.
+	updatedAt, err := a.s.ActorsUpdatedAt()
+	if err != nil {
+		return domain.ActorPage{}, err
+	}
+
+	actors, err := a.s.GetActorsPage(limit+1, afterID)
+	if err != nil {
+		return domain.ActorPage{}, err
+	}
+
+	for i := range actors {
+		actors[i].Photo = a.images.image(actors[i].PhotoKey)
+	}
+
+	result := domain.ActorPage{Actors: make([]domain.Actor, 0, len(actors)), UpdatedAt: updatedAt}
+	if len(actors) > limit {
+		actors = actors[:limit]
+		result.NextCursor = domain.Cursor{ID: actors[limit-1].ID}.Encode()
+	}
+	result.Actors = append(result.Actors, actors...)
+
+	if page.WithTotal {
+		total, err := a.s.CountActors()
+		if err != nil {
+			return domain.ActorPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
 }
 
-func (a *actorService) CreateActor(actor domain.Actor) error {
+// GetActorFilms returns a page of the films of the actor, by year unless
+// another order is asked for.
+func (a *actorService) GetActorFilms(actorId int64, q domain.CreditQuery) (domain.FilmCreditPage, error) {
+	switch q.OrderBy {
+	case "title", "rating", "billing":
+	default:
+		q.OrderBy = "year"
+	}
+
+	after, err := pageCursor(q.Page, q.OrderBy, q.Desc)
+	if err != nil {
+		return domain.FilmCreditPage{}, err
+	}
+
+	limit := pageLimit(q.Page.Limit)
+	films, err := a.s.GetActorFilms(actorId, q, limit+1, after)
+	if err != nil {
+		return domain.FilmCreditPage{}, err
+	}
+	if len(films) == 0 && after == nil {
+		// Tell an actor without films from one that doesn't exist.
+		if _, err := a.s.GetActor(actorId); err != nil {
+			return domain.FilmCreditPage{}, err
+		}
+	}
+
+	for i := range films {
+		films[i].Poster = a.images.image(films[i].PosterKey)
+	}
+
+	result := domain.FilmCreditPage{Films: make([]domain.FilmCredit, 0, len(films))}
+	if len(films) > limit {
+		films = films[:limit]
+		last := films[limit-1]
+		value := filmSortValue(last.Film, q.OrderBy)
+		if q.OrderBy == "billing" {
+			value = billingSortValue(last.Part)
+		}
+		result.NextCursor = domain.Cursor{
+			OrderBy: q.OrderBy,
+			Desc:    q.Desc,
+			Value:   value,
+			ID:      last.ID,
+		}.Encode()
+	}
+	result.Films = append(result.Films, films...)
+
+	if q.Page.WithTotal {
+		total, err := a.s.CountActorFilms(actorId)
+		if err != nil {
+			return domain.FilmCreditPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// CreateActor saves the actor and returns them as they were saved, with
+// their ID.
+func (a *actorService) CreateActor(e domain.Editor, actor domain.Actor) (domain.Actor, error) {
 	if !actor.IsValid() {
-		return errors.New("actor is not valid")
+		return domain.Actor{}, errors.New("actor is not valid")
 	}
-	return a.s.CreateActor(actor)
+	created, err := a.s.CreateActor(e, actor)
+	if err != nil {
+		return domain.Actor{}, err
+	}
+
+	a.events.ActorChanged(created.ID)
+	return created, nil
 }
 
 func (a *actorService) GetActor(id int64) (domain.Actor, error) {
-	return a.s.GetActor(id)
+	actor, err := a.s.GetActor(id)
+	actor.Photo = a.images.image(actor.PhotoKey)
+
+	return actor, err
 }
 
-func (a *actorService) UpdateActor(actor domain.Actor) error {
+// UpdateActor saves the actor. If versions are given, the actor must still
+// be of one of them.
+func (a *actorService) UpdateActor(e domain.Editor, actor domain.Actor, versions domain.Versions) error {
 	if !actor.IsValid() {
 		return errors.New("actor is not valid")
 	}
-	return a.s.UpdateActor(actor)
+	if err := checkVersion(versions, a.requireVersion); err != nil {
+		return err
+	}
+	if err := a.s.UpdateActor(e, actor, versions); err != nil {
+		return err
+	}
+
+	a.events.ActorChanged(actor.ID)
+	return nil
+}
+
+// PatchActor applies the JSON merge patch (RFC 7396) to the actor and
+// returns them, as PatchFilm does for films.
+func (a *actorService) PatchActor(e domain.Editor, id int64, versions domain.Versions, patch []byte) (domain.Actor, error) {
+	if err := checkVersion(versions, a.requireVersion); err != nil {
+		return domain.Actor{}, err
+	}
+
+	err := a.s.PatchActor(e, id, versions, func(actor domain.Actor) (domain.Actor, error) {
+		var patched domain.Actor
+		if err := mergePatch(actor, patch, &patched, actorPatchFields); err != nil {
+			return patched, err
+		}
+		patched.ID = actor.ID
+		if !patched.IsValid() {
+			return patched, errors.New("actor is not valid")
+		}
+		return patched, nil
+	})
+	if err != nil {
+		return domain.Actor{}, err
+	}
+
+	a.events.ActorChanged(id)
+	return a.GetActor(id)
+}
+
+// GetActorRevisions returns the revisions of the actor, the latest first,
+// each with the fields it changed.
+func (a *actorService) GetActorRevisions(actorId int64) ([]domain.ActorRevision, error) {
+	if _, err := a.s.GetActor(actorId); err != nil {
+		return nil, err
+	}
+	revisions, err := a.s.GetActorRevisions(actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i+1 < len(revisions); i++ {
+		revisions[i].Diff, err = domain.NewDiff(revisions[i+1].Actor, revisions[i].Actor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+// SetPhoto stores data as the photo of the actor, replacing the previous
+// one.
+func (a *actorService) SetPhoto(e domain.Editor, actorId int64, data []byte) (domain.Image, error) {
+	if _, err := a.s.GetActor(actorId); err != nil {
+		return domain.Image{}, err
+	}
+
+	key, err := a.images.save(fmt.Sprintf("actors/%d/photo", actorId), data)
+	if err != nil {
+		return domain.Image{}, err
+	}
+	photo := sql.NullString{String: key, Valid: true}
+	// The same file uploaded again is stored under the same key, so only a
+	// key the actor doesn't have is removed.
+	previous, err := a.s.SetActorPhoto(e, actorId, key)
+	if err != nil {
+		if previous != photo {
+			a.images.remove(photo)
+		}
+		return domain.Image{}, err
+	}
+	if previous != photo {
+		a.images.remove(previous)
+	}
+
+	return *a.images.image(photo), nil
+}
+
+// DeleteActor moves the actor to the trash. Their photo is kept until the
+// actor is purged. If versions are given, the actor must still be of one of
+// them.
+func (a *actorService) DeleteActor(e domain.Editor, id int64, versions domain.Versions) error {
+	if err := checkVersion(versions, a.requireVersion); err != nil {
+		return err
+	}
+	if err := a.s.DeleteActor(e, id, versions); err != nil {
+		return err
+	}
+
+	a.events.ActorDeleted(id)
+	return nil
 }
 
-func (a *actorService) DeleteActor(id int64) error {
-	if err := a.s.DeleteActorsFilms(id); err != nil {
+// RestoreActor takes the actor out of the trash with their credits.
+func (a *actorService) RestoreActor(e domain.Editor, id int64) error {
+	if err := a.s.RestoreActor(e, id); err != nil {
 		return err
 	}
 
-	return a.s.DeleteActor(id)
+	a.events.ActorRestored(id)
+	return nil
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+)
+
+type auditService struct {
+	s storage.AuditStorage
+}
+
+func NewAuditService(s storage.AuditStorage) Audit {
+	return &auditService{s: s}
+}
+
+// GetAudit returns a page of the audit log, the latest entries first.
+func (a *auditService) GetAudit(q domain.AuditQuery) (domain.AuditPage, error) {
+	switch q.EntityType {
+	case "", domain.AuditFilm, domain.AuditActor:
+	default:
+		return domain.AuditPage{}, errors.New("entityType must be film or actor")
+	}
+	if q.EntityID != 0 && q.EntityType == "" {
+		return domain.AuditPage{}, errors.New("entityId needs entityType")
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return domain.AuditPage{}, errors.New("from must be before to")
+	}
+
+	after, err := pageCursor(q.Page, "id", true)
+	if err != nil {
+		return domain.AuditPage{}, err
+	}
+	var afterID int64
+	if after != nil {
+		afterID = after.ID
+	}
+
+	limit := pageLimit(q.Page.Limit)
+	entries, err := a.s.GetAudit(q, limit+1, afterID)
+	if err != nil {
+		return domain.AuditPage{}, err
+	}
+
+	result := domain.AuditPage{Entries: make([]domain.AuditEntry, 0, len(entries))}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		result.NextCursor = domain.Cursor{OrderBy: "id", Desc: true, ID: entries[limit-1].ID}.Encode()
+	}
+	result.Entries = append(result.Entries, entries...)
+
+	if q.Page.WithTotal {
+		total, err := a.s.CountAudit(q)
+		if err != nil {
+			return domain.AuditPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
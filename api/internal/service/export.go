@@ -0,0 +1,237 @@
+package service
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportFilmColumns and exportActorColumns are the CSV columns of exported
+// films and actors. Imports take them too, skipping id, rating, votes and
+// credits, so an exported CSV or NDJSON file can be imported back.
+var (
+	exportFilmColumns = []string{"id", "title", "year", "information", "rating", "votes", "editorialRating",
+		"runtimeMinutes", "releaseDate", "countries", "originalLanguage"}
+	exportActorColumns = []string{"id", "name", "surname", "patronymic", "birthday", "sex", "information"}
+)
+
+type exportService struct {
+	s              storage.ExportStorage
+	fuzzyThreshold float64
+}
+
+func NewExportService(s storage.ExportStorage, cfg Config) Export {
+	return &exportService{
+		s:              s,
+		fuzzyThreshold: cfg.FuzzyThreshold,
+	}
+}
+
+// ExportFilms writes the films matching q to w as they are read from the
+// database. Credits go to the last CSV column as a JSON array. As with
+// GetFilms, if no title or actor matches, they are matched by similarity.
+func (m *exportService) ExportFilms(q domain.FilmQuery, withCredits bool, format string, w io.Writer) error {
+	columns := exportFilmColumns
+	if withCredits {
+		columns = append(columns[:len(columns):len(columns)], "credits")
+	}
+	e, err := newExportEncoder(format, w, columns)
+	if err != nil {
+		return err
+	}
+
+	write := func(film domain.ExportFilm) error {
+		if format != domain.ExportCSV {
+			return e.encode(film, nil)
+		}
+		record := []string{
+			strconv.FormatInt(film.ID, 10),
+			film.Title,
+			strconv.Itoa(film.Year),
+			nullString(film.Information),
+			nullFloat(film.Rating),
+			strconv.FormatInt(film.Votes, 10),
+			nullFloat(film.EditorialRating),
+			nullInt(film.RuntimeMinutes),
+			nullDate(film.ReleaseDate),
+			strings.Join(film.Countries, ";"),
+			nullString(film.OriginalLanguage),
+		}
+		if withCredits {
+			credits, err := creditsCell(film.Credits)
+			if err != nil {
+				return err
+			}
+			record = append(record, credits)
+		}
+		return e.encode(nil, record)
+	}
+	if err := m.s.ExportFilms(q, withCredits, write); err != nil {
+		return err
+	}
+	// Nothing is written before the first film, so the similar films
+	// follow the header as the matching ones would.
+	if e.rows == 0 && !q.Fuzzy && (q.Title != "" || q.Actor != "") {
+		q.Fuzzy = true
+		q.FuzzyThreshold = m.fuzzyThreshold
+		if err := m.s.ExportFilms(q, withCredits, write); err != nil {
+			return err
+		}
+	}
+
+	return e.close()
+}
+
+// ExportActors writes all actors to w as they are read from the database.
+// Credits go to the last CSV column as a JSON array.
+func (m *exportService) ExportActors(withCredits bool, format string, w io.Writer) error {
+	columns := exportActorColumns
+	if withCredits {
+		columns = append(columns[:len(columns):len(columns)], "credits")
+	}
+	e, err := newExportEncoder(format, w, columns)
+	if err != nil {
+		return err
+	}
+
+	err = m.s.ExportActors(withCredits, func(actor domain.ExportActor) error {
+		if format != domain.ExportCSV {
+			return e.encode(actor, nil)
+		}
+		record := []string{
+			strconv.FormatInt(actor.ID, 10),
+			actor.Name,
+			actor.Surname,
+			nullString(actor.Patronymic),
+			actor.Birthday.Format(time.DateOnly),
+			actor.Sex,
+			nullString(actor.Information),
+		}
+		if withCredits {
+			credits, err := creditsCell(actor.Credits)
+			if err != nil {
+				return err
+			}
+			record = append(record, credits)
+		}
+		return e.encode(nil, record)
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.close()
+}
+
+// exportEncoder writes exported rows in one of the export formats. A CSV row
+// is written from its record, the others from the row itself.
+type exportEncoder struct {
+	format string
+	buf    *bufio.Writer
+	csv    *csv.Writer
+	json   *json.Encoder
+	rows   int
+}
+
+func newExportEncoder(format string, w io.Writer, columns []string) (*exportEncoder, error) {
+	e := &exportEncoder{format: format, buf: bufio.NewWriter(w)}
+
+	switch format {
+	case domain.ExportCSV:
+		e.csv = csv.NewWriter(e.buf)
+		if err := e.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	case domain.ExportNDJSON:
+		e.json = json.NewEncoder(e.buf)
+	case domain.ExportJSON:
+		e.json = json.NewEncoder(e.buf)
+		if _, err := e.buf.WriteString("["); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("can't export to %q", format)
+	}
+
+	return e, nil
+}
+
+func (e *exportEncoder) encode(v any, record []string) error {
+	e.rows++
+
+	switch e.format {
+	case domain.ExportCSV:
+		return e.csv.Write(record)
+	case domain.ExportJSON:
+		if e.rows > 1 {
+			if err := e.buf.WriteByte(','); err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.json.Encode(v)
+}
+
+// close ends the file and writes out what is left in the buffer.
+func (e *exportEncoder) close() error {
+	switch e.format {
+	case domain.ExportCSV:
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	case domain.ExportJSON:
+		if _, err := e.buf.WriteString("]\n"); err != nil {
+			return err
+		}
+	}
+
+	return e.buf.Flush()
+}
+
+// creditsCell turns credits into a CSV cell holding a JSON array. A row
+// without credits has an empty cell.
+func creditsCell[T any](credits []T) (string, error) {
+	if len(credits) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(credits)
+	return string(data), err
+}
+
+func nullString(s sql.NullString) string {
+	if !s.Valid {
+		return ""
+	}
+	return s.String
+}
+
+func nullFloat(f sql.NullFloat64) string {
+	if !f.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
+
+func nullInt(n sql.NullInt64) string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatInt(n.Int64, 10)
+}
+
+func nullDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format(time.DateOnly)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+// catalogStorage exports its films and actors and keeps the ones imported.
+type catalogStorage struct {
+	films          []domain.ExportFilm
+	actors         []domain.ExportActor
+	importedFilms  []domain.Film
+	importedActors []domain.Actor
+}
+
+func (s *catalogStorage) ExportFilms(q domain.FilmQuery, withCredits bool, fn func(domain.ExportFilm) error) error {
+	for _, film := range s.films {
+		if err := fn(film); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *catalogStorage) ExportActors(withCredits bool, fn func(domain.ExportActor) error) error {
+	for _, actor := range s.actors {
+		if err := fn(actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *catalogStorage) ImportFilms(e domain.Editor, films []domain.Film, commit bool) ([]int64, []error, error) {
+	s.importedFilms = films
+	return make([]int64, len(films)), make([]error, len(films)), nil
+}
+
+func (s *catalogStorage) ImportActors(e domain.Editor, actors []domain.Actor, commit bool) ([]int64, []error, error) {
+	s.importedActors = actors
+	return make([]int64, len(actors)), make([]error, len(actors)), nil
+}
+
+func (s *catalogStorage) ImportCredits(e domain.Editor, credits []domain.ImportCredit, commit bool) ([]int64, []error, error) {
+	return make([]int64, len(credits)), make([]error, len(credits)), nil
+}
+
+func TestExportService_ImportBack(t *testing.T) {
+	film := domain.Film{
+		ID:               3,
+		Title:            "Брат",
+		Year:             1997,
+		Information:      sql.NullString{String: "Бандитский Петербург", Valid: true},
+		Rating:           sql.NullFloat64{Float64: 8.2, Valid: true},
+		Votes:            120,
+		EditorialRating:  sql.NullFloat64{Float64: 9, Valid: true},
+		RuntimeMinutes:   sql.NullInt64{Int64: 100, Valid: true},
+		ReleaseDate:      sql.NullTime{Time: time.Date(1997, 5, 17, 0, 0, 0, 0, time.UTC), Valid: true},
+		Countries:        pq.StringArray{"RU"},
+		OriginalLanguage: sql.NullString{String: "ru", Valid: true},
+	}
+	actor := domain.Actor{
+		ID:       5,
+		Name:     "Сергей",
+		Surname:  "Бодров",
+		Birthday: time.Date(1971, 12, 27, 0, 0, 0, 0, time.UTC),
+		Sex:      "male",
+	}
+	part := domain.Part{Character: "Данила", Billing: 1, Type: domain.CreditLead}
+
+	tests := []struct {
+		name        string
+		format      string
+		withCredits bool
+	}{
+		{name: "CSV", format: domain.ExportCSV},
+		{name: "CSV with credits", format: domain.ExportCSV, withCredits: true},
+		{name: "NDJSON", format: domain.ExportNDJSON},
+		{name: "NDJSON with credits", format: domain.ExportNDJSON, withCredits: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &catalogStorage{
+				films:  []domain.ExportFilm{{Film: film}},
+				actors: []domain.ExportActor{{Actor: actor}},
+			}
+			if test.withCredits {
+				s.films[0].Credits = []domain.Credit{{ActorID: actor.ID, Part: part}}
+				s.actors[0].Credits = []domain.ActorCredit{{FilmID: film.ID, Part: part}}
+			}
+			exports := NewExportService(s, Config{})
+			imports := NewImportService(s, CatalogListeners(nil))
+
+			var films, actors bytes.Buffer
+			assert.NoError(t, exports.ExportFilms(domain.FilmQuery{}, test.withCredits, test.format, &films))
+			assert.NoError(t, exports.ExportActors(test.withCredits, test.format, &actors))
+
+			report, err := imports.Import(domain.Editor{UserID: 1}, domain.ImportFilms, test.format, &films, false)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Failed, report.Rows)
+			report, err = imports.Import(domain.Editor{UserID: 1}, domain.ImportActors, test.format, &actors, false)
+			assert.NoError(t, err)
+			assert.Equal(t, 0, report.Failed, report.Rows)
+
+			// Imported rows are new ones: their IDs and votes don't come
+			// with them.
+			expectedFilm := film
+			expectedFilm.ID, expectedFilm.Rating, expectedFilm.Votes = 0, sql.NullFloat64{}, 0
+			expectedActor := actor
+			expectedActor.ID = 0
+			assert.Equal(t, []domain.Film{expectedFilm}, s.importedFilms)
+			assert.Equal(t, []domain.Actor{expectedActor}, s.importedActors)
+		})
+	}
+}
+
+// similarStorage has films only similar to the title asked for, and keeps
+// the queries it's asked.
+type similarStorage struct {
+	catalogStorage
+	queries []domain.FilmQuery
+}
+
+func (s *similarStorage) ExportFilms(q domain.FilmQuery, withCredits bool, fn func(domain.ExportFilm) error) error {
+	s.queries = append(s.queries, q)
+	if !q.Fuzzy {
+		return nil
+	}
+	return s.catalogStorage.ExportFilms(q, withCredits, fn)
+}
+
+func TestExportService_ExportFilms_Similar(t *testing.T) {
+	s := &similarStorage{catalogStorage: catalogStorage{
+		films: []domain.ExportFilm{{Film: domain.Film{ID: 3, Title: "Брат", Year: 1997}}},
+	}}
+	exports := NewExportService(s, Config{FuzzyThreshold: 0.4})
+
+	var films bytes.Buffer
+	err := exports.ExportFilms(domain.FilmQuery{Title: "Брта"}, false, domain.ExportJSON, &films)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.FilmQuery{
+		{Title: "Брта"},
+		{Title: "Брта", Fuzzy: true, FuzzyThreshold: 0.4},
+	}, s.queries)
+	assert.JSONEq(t, `[{"id": 3, "title": "Брат", "year": 1997, "information": {"String": "", "Valid": false},
+		"rating": {"Float64": 0, "Valid": false}, "votes": 0, "editorialRating": {"Float64": 0, "Valid": false},
+		"runtimeMinutes": {"Int64": 0, "Valid": false}, "releaseDate": {"Time": "0001-01-01T00:00:00Z", "Valid": false},
+		"countries": null, "originalLanguage": {"String": "", "Valid": false}}]`, films.String())
+}
@@ -1,71 +1,487 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"kinoteka/internal/domain"
 	"kinoteka/internal/storage"
+	"math"
+	"strconv"
+	"time"
 )
 
 type filmService struct {
-	s storage.FilmStorage
+	s              storage.FilmStorage
+	images         images
+	fuzzyThreshold float64
+	events         CatalogListener
+	requireVersion bool
 }
 
-func NewFilmService(s storage.FilmStorage) Film {
+func NewFilmService(s storage.FilmStorage, blobs storage.BlobStore, cfg Config, events CatalogListener) Film {
 	return &filmService{
-		s: s,
+		s:              s,
+		images:         images{blobs: blobs, maxSize: cfg.MaxImageSize},
+		fuzzyThreshold: cfg.FuzzyThreshold,
+		events:         events,
+		requireVersion: cfg.RequireVersion,
 	}
 }
 
-func (f *filmService) GetFilmsSortLike(orderBy, title string, desc bool) ([]domain.Film, error) {
-	if orderBy != "title" && orderBy != "year" {
-		orderBy = "rating"
+func (f *filmService) GetFilms(q domain.FilmQuery) (domain.FilmPage, error) {
+	byScore := q.OrderBy == ""
+	switch q.OrderBy {
+	case "id", "title", "year", "runtime", "releaseDate":
+	default:
+		q.OrderBy = "rating"
+	}
+	if q.YearFrom != 0 && q.YearTo != 0 && q.YearFrom > q.YearTo {
+		return domain.FilmPage{}, errors.New("yearFrom is greater than yearTo")
+	}
+	if q.MinRating != nil && q.MaxRating != nil && *q.MinRating > *q.MaxRating {
+		return domain.FilmPage{}, errors.New("minRating is greater than maxRating")
+	}
+	if q.MinRuntime != 0 && q.MaxRuntime != 0 && q.MinRuntime > q.MaxRuntime {
+		return domain.FilmPage{}, errors.New("minRuntime is greater than maxRuntime")
+	}
+	if !q.ReleasedFrom.IsZero() && !q.ReleasedTo.IsZero() && q.ReleasedFrom.After(q.ReleasedTo) {
+		return domain.FilmPage{}, errors.New("releasedFrom is after releasedTo")
+	}
+
+	var after *domain.Cursor
+	if q.Page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(q.Page.Cursor)
+		if err != nil {
+			return domain.FilmPage{}, err
+		}
+		if cursor.Fuzzy {
+			q = f.fuzzy(q, byScore)
+		}
+		if cursor.OrderBy != q.OrderBy || cursor.Desc != q.Desc {
+			return domain.FilmPage{}, errors.New("cursor was issued for another sort order")
+		}
+		after = &cursor
+	}
+
+	page, err := f.getFilms(q, after)
+	if err != nil {
+		return domain.FilmPage{}, err
+	}
+
+	if len(page.Films) == 0 && after == nil && !q.Fuzzy && (q.Title != "" || q.Actor != "") {
+		return f.getFilms(f.fuzzy(q, byScore), nil)
+	}
+
+	return page, nil
+}
+
+// fuzzy turns q into a typo-tolerant query. Unless another order was asked
+// for, the most similar films go first.
+func (f *filmService) fuzzy(q domain.FilmQuery, byScore bool) domain.FilmQuery {
+	q.Fuzzy = true
+	q.FuzzyThreshold = f.fuzzyThreshold
+	if byScore {
+		q.OrderBy = "similarity"
+		q.Desc = true
+	}
+
+	return q
+}
+
+func (f *filmService) getFilms(q domain.FilmQuery, after *domain.Cursor) (domain.FilmPage, error) {
+	limit := pageLimit(q.Page.Limit)
+
+	// The time of the last change is read before the films, so that a
+	// change made in between makes the page look older, not newer. Adding
+	// to a watchlist or taking from it changes no film, so a page filtered
+	// by the watchlist has no such time and is told apart by its ETag only.
+	var updatedAt time.Time
+	if q.InWatchlist == nil {
+		var err error
+		if updatedAt, err = f.s.FilmsUpdatedAt(); err != nil {
+			return domain.FilmPage{}, err
+		}
+	}
+
+	films, err := f.s.GetFilms(q, limit+1, after)
+	if err != nil {
+		return domain.FilmPage{}, err
+	}
+
+	for i := range films {
+		films[i].Poster = f.images.image(films[i].PosterKey)
 	}
 
-	return f.s.GetFilmsSortLike(orderBy, title, desc)
+	result := domain.FilmPage{Films: make([]domain.Film, 0, len(films)), Fuzzy: q.Fuzzy, UpdatedAt: updatedAt}
+	if len(films) > limit {
+		films = films[:limit]
+		last := films[limit-1]
+		result.NextCursor = domain.Cursor{
+			OrderBy: q.OrderBy,
+			Desc:    q.Desc,
+			Fuzzy:   q.Fuzzy,
+			Value:   filmSortValue(last, q.OrderBy),
+			ID:      last.ID,
+		}.Encode()
+	}
+	result.Films = append(result.Films, films...)
+
+	if q.Page.WithTotal {
+		total, err := f.s.CountFilms(q)
+		if err != nil {
+			return domain.FilmPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// filmSortValue returns the value of the column films are ordered by, in the
+// form the storage compares it against when the next page is requested.
+func filmSortValue(film domain.Film, orderBy string) string {
+	switch orderBy {
+	case "similarity":
+		if film.Similarity == nil {
+			return "0"
+		}
+		return strconv.FormatFloat(*film.Similarity, 'f', -1, 64)
+	case "id":
+		return strconv.FormatInt(film.ID, 10)
+	case "title":
+		return film.Title
+	case "year":
+		return strconv.Itoa(film.Year)
+	case "runtime":
+		if !film.RuntimeMinutes.Valid {
+			return "-1"
+		}
+		return strconv.FormatInt(film.RuntimeMinutes.Int64, 10)
+	case "releaseDate":
+		if !film.ReleaseDate.Valid {
+			return "-infinity"
+		}
+		return film.ReleaseDate.Time.Format(time.DateOnly)
+	default:
+		if !film.Rating.Valid {
+			return "-1"
+		}
+		return strconv.FormatFloat(film.Rating.Float64, 'f', -1, 64)
+	}
 }
 
-func (f *filmService) GetFilmsLike(title string) ([]domain.Film, error) {
-	return f.s.GetFilmsLike(title)
+func (f *filmService) GetFilm(id, userId int64) (domain.Film, error) {
+	film, err := f.s.GetFilm(id, userId)
+	if err != nil {
+		return film, err
+	}
+	film.Poster = f.images.image(film.PosterKey)
+
+	film.Genres, err = f.s.GetFilmGenres(id)
+	if err != nil {
+		return film, err
+	}
+
+	film.Cast, err = f.s.GetFilmCast(id)
+	for i := range film.Cast {
+		film.Cast[i].Photo = f.images.image(film.Cast[i].PhotoKey)
+	}
+
+	return film, err
 }
 
-func (f *filmService) GetFilmsSort(orderBy string, desc bool) ([]domain.Film, error) {
-	if orderBy != "title" && orderBy != "year" {
-		orderBy = "rating"
+// GetFilmActors returns a page of the cast of the film, by billing unless
+// another order is asked for.
+func (f *filmService) GetFilmActors(filmId int64, q domain.CreditQuery) (domain.CastPage, error) {
+	switch q.OrderBy {
+	case "name", "birthday":
+	default:
+		q.OrderBy = "billing"
+	}
+
+	after, err := pageCursor(q.Page, q.OrderBy, q.Desc)
+	if err != nil {
+		return domain.CastPage{}, err
+	}
+
+	limit := pageLimit(q.Page.Limit)
+	cast, err := f.s.GetFilmActors(filmId, q, limit+1, after)
+	if err != nil {
+		return domain.CastPage{}, err
+	}
+	if len(cast) == 0 && after == nil {
+		// Tell a film without cast from one that doesn't exist.
+		if _, err := f.s.GetFilm(filmId, 0); err != nil {
+			return domain.CastPage{}, err
+		}
+	}
+
+	for i := range cast {
+		cast[i].Photo = f.images.image(cast[i].PhotoKey)
+	}
+
+	result := domain.CastPage{Actors: make([]domain.CastMember, 0, len(cast))}
+	if len(cast) > limit {
+		cast = cast[:limit]
+		last := cast[limit-1]
+		result.NextCursor = domain.Cursor{
+			OrderBy: q.OrderBy,
+			Desc:    q.Desc,
+			Value:   castSortValue(last, q.OrderBy),
+			ID:      last.ID,
+		}.Encode()
 	}
+	result.Actors = append(result.Actors, cast...)
 
-	return f.s.GetFilmsSort(orderBy, desc)
+	if q.Page.WithTotal {
+		total, err := f.s.CountFilmActors(filmId)
+		if err != nil {
+			return domain.CastPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// castSortValue returns the value of the column the cast is ordered by, in
+// the form the storage compares it against when the next page is requested.
+func castSortValue(member domain.CastMember, orderBy string) string {
+	switch orderBy {
+	case "name":
+		return member.Surname + " " + member.Name
+	case "birthday":
+		return member.Birthday.Format(time.DateOnly)
+	default:
+		return billingSortValue(member.Part)
+	}
 }
 
-func (f *filmService) GetFilm(id int64) (domain.Film, error) {
-	return f.s.GetFilm(id)
+// billingSortValue puts the parts with unknown billing after the others.
+func billingSortValue(part domain.Part) string {
+	if part.Billing == 0 {
+		return strconv.Itoa(math.MaxInt32)
+	}
+	return strconv.Itoa(part.Billing)
 }
 
-func (f *filmService) CreateFilm(a domain.Film) error {
+// CreateFilm saves the film and returns it as it was saved, with its ID.
+func (f *filmService) CreateFilm(e domain.Editor, a domain.Film) (domain.Film, error) {
 	if !a.IsValid() {
-		return errors.New("film is not valid")
+		return domain.Film{}, errors.New("film is not valid")
+	}
+	film, err := f.s.CreateFilm(e, a)
+	if err != nil {
+		return domain.Film{}, err
 	}
-	return f.s.CreateFilm(a)
+
+	f.events.FilmChanged(film.ID)
+	return film, nil
 }
 
-func (f *filmService) UpdateFilm(a domain.Film) error {
+// UpdateFilm saves the film. If versions are given, the film must still be
+// of one of them.
+func (f *filmService) UpdateFilm(e domain.Editor, a domain.Film, versions domain.Versions) error {
 	if !a.IsValid() {
 		return errors.New("film is not valid")
 	}
-	return f.s.UpdateFilm(a)
+	if err := checkVersion(versions, f.requireVersion); err != nil {
+		return err
+	}
+	if err := f.s.UpdateFilm(e, a, versions); err != nil {
+		return err
+	}
+
+	f.events.FilmChanged(a.ID)
+	return nil
 }
 
-func (f *filmService) DeleteFilm(id int64) error {
-	if err := f.s.DeleteFilmsActors(id); err != nil {
+// PatchFilm applies the JSON merge patch (RFC 7396) to the film and returns
+// it as GetFilm does. Fields the patch leaves out are kept, null clears them.
+// The film is read, patched and saved in one transaction. If versions are
+// given, the film must still be of one of them.
+func (f *filmService) PatchFilm(e domain.Editor, id int64, versions domain.Versions, patch []byte) (domain.Film, error) {
+	if err := checkVersion(versions, f.requireVersion); err != nil {
+		return domain.Film{}, err
+	}
+
+	err := f.s.PatchFilm(e, id, versions, func(film domain.Film) (domain.Film, error) {
+		var patched domain.Film
+		if err := mergePatch(film, patch, &patched, filmPatchFields); err != nil {
+			return patched, err
+		}
+		patched.ID = film.ID
+		if !patched.IsValid() {
+			return patched, errors.New("film is not valid")
+		}
+		return patched, nil
+	})
+	if err != nil {
+		return domain.Film{}, err
+	}
+
+	f.events.FilmChanged(id)
+	return f.GetFilm(id, e.UserID)
+}
+
+// GetFilmRevisions returns the revisions of the film, the latest first,
+// each with the fields it changed.
+func (f *filmService) GetFilmRevisions(filmId int64) ([]domain.FilmRevision, error) {
+	if _, err := f.s.GetFilm(filmId, 0); err != nil {
+		return nil, err
+	}
+	revisions, err := f.s.GetFilmRevisions(filmId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i+1 < len(revisions); i++ {
+		revisions[i].Diff, err = domain.NewDiff(revisions[i+1].Film, revisions[i].Film)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return revisions, nil
+}
+
+// RevertFilm brings the film back to the revision. The film as it becomes
+// is saved as a new revision.
+func (f *filmService) RevertFilm(e domain.Editor, filmId, rev int64) error {
+	if err := f.s.RevertFilm(e, filmId, rev); err != nil {
 		return err
 	}
 
-	return f.s.DeleteFilm(id)
+	f.events.FilmChanged(filmId)
+	return nil
+}
+
+// SetPoster stores data as the poster of the film, replacing the previous
+// one.
+func (f *filmService) SetPoster(e domain.Editor, filmId int64, data []byte) (domain.Image, error) {
+	if _, err := f.s.GetFilm(filmId, 0); err != nil {
+		return domain.Image{}, err
+	}
+
+	key, err := f.images.save(fmt.Sprintf("films/%d/poster", filmId), data)
+	if err != nil {
+		return domain.Image{}, err
+	}
+	poster := sql.NullString{String: key, Valid: true}
+	// The same file uploaded again is stored under the same key, so only a
+	// key the film doesn't have is removed.
+	previous, err := f.s.SetFilmPoster(e, filmId, key)
+	if err != nil {
+		if previous != poster {
+			f.images.remove(poster)
+		}
+		return domain.Image{}, err
+	}
+	if previous != poster {
+		f.images.remove(previous)
+	}
+
+	return *f.images.image(poster), nil
 }
 
-func (f *filmService) SearchFilmsWithActor(substr string) ([]domain.ActorFilm, error) {
-	return f.s.SearchFilmsWithActor(substr)
+// RateFilm saves the vote of the user for the film and returns its new
+// rating.
+func (f *filmService) RateFilm(filmId, userId int64, vote domain.Vote) (domain.FilmRating, error) {
+	if !vote.IsValid() {
+		return domain.FilmRating{}, errors.New("rating must be from 0 to 10")
+	}
+	rating, err := f.s.RateFilm(filmId, userId, vote.Rating)
+	if err != nil {
+		return domain.FilmRating{}, err
+	}
+
+	f.events.Voted(userId, filmId, vote.Rating)
+	return rating, nil
+}
+
+// DeleteFilm moves the film to the trash. Its poster is kept until the film
+// is purged. If versions are given, the film must still be of one of them.
+func (f *filmService) DeleteFilm(e domain.Editor, id int64, versions domain.Versions) error {
+	if err := checkVersion(versions, f.requireVersion); err != nil {
+		return err
+	}
+	if err := f.s.DeleteFilm(e, id, versions); err != nil {
+		return err
+	}
+
+	f.events.FilmDeleted(id)
+	return nil
+}
+
+// RestoreFilm takes the film out of the trash with its cast.
+func (f *filmService) RestoreFilm(e domain.Editor, id int64) error {
+	if err := f.s.RestoreFilm(e, id); err != nil {
+		return err
+	}
+
+	f.events.FilmRestored(id)
+	return nil
+}
+
+func (f *filmService) AddActorToFilm(e domain.Editor, filmId int64, credits []domain.Credit) error {
+	if err := checkCredits(credits); err != nil {
+		return err
+	}
+	if err := f.s.AddActorToFilm(e, filmId, credits); err != nil {
+		return err
+	}
+
+	f.events.FilmChanged(filmId)
+	return nil
+}
+
+// ReplaceCast makes the credits the whole cast of the film, all of them or
+// none.
+func (f *filmService) ReplaceCast(e domain.Editor, filmId int64, credits []domain.Credit) error {
+	if err := checkCredits(credits); err != nil {
+		return err
+	}
+	if err := f.s.ReplaceFilmCast(e, filmId, credits); err != nil {
+		return err
+	}
+
+	f.events.FilmChanged(filmId)
+	return nil
+}
+
+func (f *filmService) RemoveActorFromFilm(e domain.Editor, filmId, actorId int64) error {
+	if err := f.s.RemoveActorFromFilm(e, filmId, actorId); err != nil {
+		return err
+	}
+
+	f.events.FilmChanged(filmId)
+	return nil
+}
+
+// checkCredits sets the default type of the credits and checks that each is
+// valid and credits a different actor.
+func checkCredits(credits []domain.Credit) error {
+	var errs domain.CreditErrors
+	seen := make(map[int64]bool, len(credits))
+	for i := range credits {
+		if credits[i].Type == "" {
+			credits[i].Type = domain.CreditSupporting
+		}
+		if !credits[i].IsValid() {
+			errs = append(errs, domain.CreditError{ActorID: credits[i].ActorID, Reason: domain.CreditNotValid})
+		} else if seen[credits[i].ActorID] {
+			errs = append(errs, domain.CreditError{ActorID: credits[i].ActorID, Reason: domain.CreditAlreadyPresent})
+		}
+		seen[credits[i].ActorID] = true
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	return nil
 }
 
-func (f *filmService) AddActorToFilm(filmId int64, actorId []int64) error {
-	return f.s.AddActorToFilm(filmId, actorId)
+func (f *filmService) AddGenreToFilm(e domain.Editor, filmId int64, genreId []int64) error {
+	return f.s.AddGenreToFilm(e, filmId, genreId)
 }
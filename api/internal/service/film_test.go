@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+)
+
+// filmsFixture lists its films, changed last at updatedAt. Other calls of
+// the storage aren't made by the tests.
+type filmsFixture struct {
+	storage.FilmStorage
+	films     []domain.Film
+	updatedAt time.Time
+}
+
+func (f filmsFixture) GetFilms(q domain.FilmQuery, limit int, after *domain.Cursor) ([]domain.Film, error) {
+	return f.films, nil
+}
+
+func (f filmsFixture) FilmsUpdatedAt() (time.Time, error) {
+	return f.updatedAt, nil
+}
+
+func TestFilmService_GetFilms_updatedAt(t *testing.T) {
+	updatedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	inWatchlist, notInWatchlist := true, false
+
+	tests := []struct {
+		name     string
+		q        domain.FilmQuery
+		expected time.Time
+	}{
+		{
+			name:     "Catalog",
+			q:        domain.FilmQuery{UserID: 1},
+			expected: updatedAt,
+		},
+		{
+			name: "In watchlist",
+			q:    domain.FilmQuery{UserID: 1, InWatchlist: &inWatchlist},
+		},
+		{
+			name: "Not in watchlist",
+			q:    domain.FilmQuery{UserID: 1, InWatchlist: &notInWatchlist},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fixture := filmsFixture{films: []domain.Film{{ID: 1, Title: "Брат", Year: 1997}}, updatedAt: updatedAt}
+			f := NewFilmService(fixture, nil, Config{}, CatalogListeners(nil))
+
+			page, err := f.GetFilms(test.q)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, page.UpdatedAt)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+)
+
+type genreService struct {
+	s storage.GenreStorage
+}
+
+func NewGenreService(s storage.GenreStorage) Genre {
+	return &genreService{
+		s: s,
+	}
+}
+
+func (g *genreService) GetGenres() ([]domain.Genre, error) {
+	genres, err := g.s.GetGenres()
+	if err != nil {
+		return nil, err
+	}
+
+	return append(make([]domain.Genre, 0, len(genres)), genres...), nil
+}
+
+func (g *genreService) GetGenre(id int64) (domain.Genre, error) {
+	return g.s.GetGenre(id)
+}
+
+func (g *genreService) CreateGenre(genre domain.Genre) error {
+	if !genre.IsValid() {
+		return errors.New("genre is not valid")
+	}
+	return g.s.CreateGenre(genre)
+}
+
+func (g *genreService) UpdateGenre(genre domain.Genre) error {
+	if !genre.IsValid() {
+		return errors.New("genre is not valid")
+	}
+	return g.s.UpdateGenre(genre)
+}
+
+func (g *genreService) DeleteGenre(id int64) error {
+	return g.s.DeleteGenre(id)
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"fmt"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"slices"
+	"sync"
+)
+
+const defaultMaxPathDepth = 6
+
+// graphService finds paths between actors in the graph of actors linked by
+// the films they play in together. The graph is held in memory, any change
+// of casts it is told about as a CatalogListener drops it to be built anew
+// on the next request.
+type graphService struct {
+	s        storage.GraphStorage
+	maxDepth int
+
+	mu    sync.Mutex
+	graph *actorGraph
+}
+
+func newGraphService(s storage.GraphStorage, cfg Config) *graphService {
+	maxDepth := cfg.MaxPathDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxPathDepth
+	}
+
+	return &graphService{
+		s:        s,
+		maxDepth: maxDepth,
+	}
+}
+
+// actorGraph is an adjacency index of actors and films. It isn't changed
+// once built, so it can be searched without holding the lock.
+type actorGraph struct {
+	actors     map[int64]domain.PathActor
+	films      map[int64]domain.PathFilm
+	actorFilms map[int64][]int64 // sorted film IDs
+	filmActors map[int64][]int64 // sorted actor IDs
+}
+
+func (g *graphService) load() (*actorGraph, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.graph != nil {
+		return g.graph, nil
+	}
+
+	actors, err := g.s.GetPathActors()
+	if err != nil {
+		return nil, err
+	}
+	films, err := g.s.GetPathFilms()
+	if err != nil {
+		return nil, err
+	}
+	links, err := g.s.GetPathLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &actorGraph{
+		actors:     make(map[int64]domain.PathActor, len(actors)),
+		films:      make(map[int64]domain.PathFilm, len(films)),
+		actorFilms: make(map[int64][]int64),
+		filmActors: make(map[int64][]int64),
+	}
+	for _, actor := range actors {
+		graph.actors[actor.ID] = actor
+	}
+	for _, film := range films {
+		graph.films[film.ID] = film
+	}
+	for _, link := range links {
+		graph.actorFilms[link.ActorID] = append(graph.actorFilms[link.ActorID], link.FilmID)
+		graph.filmActors[link.FilmID] = append(graph.filmActors[link.FilmID], link.ActorID)
+	}
+	for _, ids := range graph.actorFilms {
+		slices.Sort(ids)
+	}
+	for _, ids := range graph.filmActors {
+		slices.Sort(ids)
+	}
+	g.graph = graph
+
+	return graph, nil
+}
+
+func (g *graphService) drop() {
+	g.mu.Lock()
+	g.graph = nil
+	g.mu.Unlock()
+}
+
+func (g *graphService) FilmChanged(filmId int64)               { g.drop() }
+func (g *graphService) FilmDeleted(filmId int64)               { g.drop() }
+func (g *graphService) FilmRestored(filmId int64)              { g.drop() }
+func (g *graphService) ActorChanged(actorId int64)             { g.drop() }
+func (g *graphService) ActorDeleted(actorId int64)             { g.drop() }
+func (g *graphService) ActorRestored(actorId int64)            { g.drop() }
+func (g *graphService) Voted(userId, filmId int64, rating int) {}
+
+// link is how an actor was reached by a search: through film from actor.
+type link struct {
+	film, actor int64
+}
+
+// search is one direction of a breadth-first search.
+type search struct {
+	visited  map[int64]link
+	frontier []int64
+}
+
+func newSearch(start int64) *search {
+	return &search{
+		visited:  map[int64]link{start: {}},
+		frontier: []int64{start},
+	}
+}
+
+// expand visits the actors one film away from the frontier. It returns the
+// first newly visited actor which the other search has visited, if any.
+func (s *search) expand(graph *actorGraph, other *search) (int64, bool) {
+	var next []int64
+	meet, met := int64(0), false
+	for _, actor := range s.frontier {
+		for _, film := range graph.actorFilms[actor] {
+			for _, partner := range graph.filmActors[film] {
+				if _, ok := s.visited[partner]; ok {
+					continue
+				}
+				s.visited[partner] = link{film: film, actor: actor}
+				next = append(next, partner)
+				if _, ok := other.visited[partner]; ok && !met {
+					meet, met = partner, true
+				}
+			}
+		}
+	}
+	s.frontier = next
+
+	return meet, met
+}
+
+// GetActorPath finds the shortest chain of films linking the actors by a
+// breadth-first search from both of them, each time expanding the side with
+// the smaller frontier.
+func (g *graphService) GetActorPath(actorId, otherId int64) (domain.ActorPath, error) {
+	graph, err := g.load()
+	if err != nil {
+		return domain.ActorPath{}, err
+	}
+
+	start, ok := graph.actors[actorId]
+	if !ok {
+		return domain.ActorPath{}, fmt.Errorf("there is no actor with id = %d", actorId)
+	}
+	if _, ok := graph.actors[otherId]; !ok {
+		return domain.ActorPath{}, fmt.Errorf("there is no actor with id = %d", otherId)
+	}
+	if actorId == otherId {
+		return domain.ActorPath{Start: start, Steps: []domain.PathStep{}}, nil
+	}
+
+	forward, backward := newSearch(actorId), newSearch(otherId)
+	for depth := 1; depth <= g.maxDepth; depth++ {
+		if len(forward.frontier) == 0 || len(backward.frontier) == 0 {
+			break
+		}
+
+		// All the actors of the frontiers are at the same distance from
+		// their starts, so the first meeting makes a shortest path.
+		var meet int64
+		var met bool
+		if len(forward.frontier) <= len(backward.frontier) {
+			meet, met = forward.expand(graph, backward)
+		} else {
+			meet, met = backward.expand(graph, forward)
+		}
+		if met {
+			return graph.path(forward, backward, actorId, otherId, meet), nil
+		}
+	}
+
+	return domain.ActorPath{}, domain.ErrNoActorPath
+}
+
+// path joins the chains the searches found to the actor they met at.
+func (graph *actorGraph) path(forward, backward *search, start, end, meet int64) domain.ActorPath {
+	var steps []domain.PathStep
+	for actor := meet; actor != start; {
+		l := forward.visited[actor]
+		steps = append(steps, domain.PathStep{Film: graph.films[l.film], Actor: graph.actors[actor]})
+		actor = l.actor
+	}
+	slices.Reverse(steps)
+
+	for actor := meet; actor != end; {
+		l := backward.visited[actor]
+		steps = append(steps, domain.PathStep{Film: graph.films[l.film], Actor: graph.actors[l.actor]})
+		actor = l.actor
+	}
+
+	return domain.ActorPath{Degrees: len(steps), Start: graph.actors[start], Steps: steps}
+}
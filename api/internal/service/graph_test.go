@@ -0,0 +1,148 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+// graphFixture is a chain of actors 1 to 7, each playing with the next one,
+// with a shortcut from 2 to 4. Actor 8 plays in nothing.
+type graphFixture struct{}
+
+var (
+	pathActors = map[int64]domain.PathActor{
+		1: {ID: 1, Name: "Сергей", Surname: "Бодров"},
+		2: {ID: 2, Name: "Виктор", Surname: "Сухоруков"},
+		3: {ID: 3, Name: "Светлана", Surname: "Письмиченко"},
+		4: {ID: 4, Name: "Иван", Surname: "Охлобыстин"},
+		5: {ID: 5, Name: "Алексей", Surname: "Серебряков"},
+		6: {ID: 6, Name: "Никита", Surname: "Михалков"},
+		7: {ID: 7, Name: "Дмитрий", Surname: "Дюжев"},
+		8: {ID: 8, Name: "Рената", Surname: "Литвинова"},
+	}
+	pathFilms = map[int64]domain.PathFilm{
+		101: {ID: 101, Title: "Брат", Year: 1997},
+		102: {ID: 102, Title: "Брат 2", Year: 2000},
+		103: {ID: 103, Title: "Сёстры", Year: 2001},
+		104: {ID: 104, Title: "ДМБ", Year: 2000},
+		105: {ID: 105, Title: "Жмурки", Year: 2005},
+		106: {ID: 106, Title: "Утомлённые солнцем 2", Year: 2010},
+		108: {ID: 108, Title: "Война", Year: 2002},
+	}
+	pathLinks = []domain.CastLink{
+		{FilmID: 101, ActorID: 1}, {FilmID: 101, ActorID: 2},
+		{FilmID: 102, ActorID: 2}, {FilmID: 102, ActorID: 3},
+		{FilmID: 103, ActorID: 3}, {FilmID: 103, ActorID: 4},
+		{FilmID: 104, ActorID: 4}, {FilmID: 104, ActorID: 5},
+		{FilmID: 105, ActorID: 5}, {FilmID: 105, ActorID: 6},
+		{FilmID: 106, ActorID: 6}, {FilmID: 106, ActorID: 7},
+		{FilmID: 108, ActorID: 2}, {FilmID: 108, ActorID: 4},
+	}
+)
+
+func (graphFixture) GetPathActors() ([]domain.PathActor, error) {
+	actors := make([]domain.PathActor, 0, len(pathActors))
+	for _, actor := range pathActors {
+		actors = append(actors, actor)
+	}
+	return actors, nil
+}
+
+func (graphFixture) GetPathFilms() ([]domain.PathFilm, error) {
+	films := make([]domain.PathFilm, 0, len(pathFilms))
+	for _, film := range pathFilms {
+		films = append(films, film)
+	}
+	return films, nil
+}
+
+func (graphFixture) GetPathLinks() ([]domain.CastLink, error) {
+	return pathLinks, nil
+}
+
+// pathStep is the step through the film to the actor.
+func pathStep(filmId, actorId int64) domain.PathStep {
+	return domain.PathStep{Film: pathFilms[filmId], Actor: pathActors[actorId]}
+}
+
+func TestGraphService_GetActorPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxDepth    int
+		actorId     int64
+		otherId     int64
+		expected    domain.ActorPath
+		expectedErr error
+	}{
+		{
+			name:     "Same actor",
+			actorId:  3,
+			otherId:  3,
+			expected: domain.ActorPath{Start: pathActors[3], Steps: []domain.PathStep{}},
+		},
+		{
+			name:     "Partners",
+			actorId:  1,
+			otherId:  2,
+			expected: domain.ActorPath{Degrees: 1, Start: pathActors[1], Steps: []domain.PathStep{pathStep(101, 2)}},
+		},
+		{
+			name:    "Meet in the middle",
+			actorId: 1,
+			otherId: 5,
+			expected: domain.ActorPath{Degrees: 3, Start: pathActors[1], Steps: []domain.PathStep{
+				pathStep(101, 2), pathStep(108, 4), pathStep(104, 5),
+			}},
+		},
+		{
+			name:    "Backwards",
+			actorId: 5,
+			otherId: 1,
+			expected: domain.ActorPath{Degrees: 3, Start: pathActors[5], Steps: []domain.PathStep{
+				pathStep(104, 4), pathStep(108, 2), pathStep(101, 1),
+			}},
+		},
+		{
+			name:     "At depth limit",
+			maxDepth: 5,
+			actorId:  1,
+			otherId:  7,
+			expected: domain.ActorPath{Degrees: 5, Start: pathActors[1], Steps: []domain.PathStep{
+				pathStep(101, 2), pathStep(108, 4), pathStep(104, 5), pathStep(105, 6), pathStep(106, 7),
+			}},
+		},
+		{
+			name:        "Beyond depth limit",
+			maxDepth:    4,
+			actorId:     1,
+			otherId:     7,
+			expectedErr: domain.ErrNoActorPath,
+		},
+		{
+			name:        "Unreachable",
+			actorId:     1,
+			otherId:     8,
+			expectedErr: domain.ErrNoActorPath,
+		},
+		{
+			name:        "Unknown actor",
+			actorId:     1,
+			otherId:     99,
+			expectedErr: errors.New("there is no actor with id = 99"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := newGraphService(graphFixture{}, Config{MaxPathDepth: test.maxDepth})
+
+			path, err := g.GetActorPath(test.actorId, test.otherId)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expected, path)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"time"
+)
+
+type historyService struct {
+	s storage.HistoryStorage
+}
+
+func NewHistoryService(s storage.HistoryStorage) History {
+	return &historyService{
+		s: s,
+	}
+}
+
+// GetHistory returns the viewings of the user, the latest first.
+func (h *historyService) GetHistory(userId int64, page domain.PageRequest) (domain.ViewingPage, error) {
+	limit := pageLimit(page.Limit)
+
+	var after *domain.Cursor
+	if page.Cursor != "" {
+		cursor, err := domain.DecodeCursor(page.Cursor)
+		if err != nil {
+			return domain.ViewingPage{}, err
+		}
+		if cursor.OrderBy != "watchedOn" {
+			return domain.ViewingPage{}, errors.New("cursor was issued for another list")
+		}
+		after = &cursor
+	}
+
+	viewings, err := h.s.GetHistory(userId, limit+1, after)
+	if err != nil {
+		return domain.ViewingPage{}, err
+	}
+
+	result := domain.ViewingPage{Viewings: make([]domain.Viewing, 0, len(viewings))}
+	if len(viewings) > limit {
+		viewings = viewings[:limit]
+		last := viewings[limit-1]
+		result.NextCursor = domain.Cursor{
+			OrderBy: "watchedOn",
+			Desc:    true,
+			Value:   last.WatchedOn.Format(time.DateOnly),
+			ID:      last.ID,
+		}.Encode()
+	}
+	result.Viewings = append(result.Viewings, viewings...)
+
+	if page.WithTotal {
+		total, err := h.s.CountHistory(userId)
+		if err != nil {
+			return domain.ViewingPage{}, err
+		}
+		result.Total = &total
+	}
+
+	return result, nil
+}
+
+// AddViewing logs that the user watched a film, today unless another day is
+// given.
+func (h *historyService) AddViewing(userId int64, v domain.Viewing) (domain.Viewing, error) {
+	if v.WatchedOn.IsZero() {
+		v.WatchedOn = time.Now()
+	}
+	v.WatchedOn = time.Date(v.WatchedOn.Year(), v.WatchedOn.Month(), v.WatchedOn.Day(), 0, 0, 0, 0, time.UTC)
+	if !v.IsValid() {
+		return domain.Viewing{}, errors.New("viewing is not valid")
+	}
+
+	return h.s.AddViewing(userId, v)
+}
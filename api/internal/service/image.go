@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"net/http"
+	"path"
+)
+
+// imageTypes maps the sniffed content types of images that can be uploaded
+// to the extension the original is stored with.
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// maxImagePixels bounds the memory a decoded image takes, as a small file
+// can declare huge dimensions.
+const maxImagePixels = 40_000_000
+
+// images keeps uploaded images with their thumbnails in a blob store. An
+// image is stored under a key derived from its content, thumbnails are JPEG
+// files next to the original.
+type images struct {
+	blobs   storage.BlobStore
+	maxSize int64
+}
+
+// save checks that data is an image and stores it under prefix. It returns
+// the key of the original.
+func (m images) save(prefix string, data []byte) (string, error) {
+	if int64(len(data)) > m.maxSize {
+		return "", domain.ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := imageTypes[contentType]
+	if !ok {
+		return "", domain.ErrImageTypeUnsupported
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", domain.ErrImageTypeUnsupported
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return "", domain.ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("can't decode image: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("%s/%x/original%s", prefix, sum[:8], ext)
+	if err := m.blobs.Put(key, bytes.NewReader(data), contentType); err != nil {
+		return "", err
+	}
+
+	for name, width := range domain.ThumbnailWidths {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, thumbnail(img, width), &jpeg.Options{Quality: 85}); err != nil {
+			return "", err
+		}
+		if err := m.blobs.Put(thumbnailKey(key, name), &buf, "image/jpeg"); err != nil {
+			return "", err
+		}
+	}
+
+	return key, nil
+}
+
+// remove deletes the image stored under key with its thumbnails.
+func (m images) remove(key sql.NullString) error {
+	if !key.Valid {
+		return nil
+	}
+
+	errs := []error{m.blobs.Delete(key.String)}
+	for name := range domain.ThumbnailWidths {
+		errs = append(errs, m.blobs.Delete(thumbnailKey(key.String, name)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// image returns the URLs of the image stored under key, or nil if there
+// isn't one.
+func (m images) image(key sql.NullString) *domain.Image {
+	if !key.Valid {
+		return nil
+	}
+
+	img := &domain.Image{
+		URL:        m.blobs.URL(key.String),
+		Thumbnails: make(map[string]string, len(domain.ThumbnailWidths)),
+	}
+	for name := range domain.ThumbnailWidths {
+		img.Thumbnails[name] = m.blobs.URL(thumbnailKey(key.String, name))
+	}
+
+	return img
+}
+
+func thumbnailKey(key, name string) string {
+	return path.Dir(key) + "/" + name + ".jpg"
+}
+
+// thumbnail scales img down to width keeping its aspect ratio. Transparent
+// parts become white, as JPEG has no alpha channel.
+func thumbnail(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	return dst
+}
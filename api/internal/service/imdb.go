@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// imdbColumns lists the columns of each IMDb file the importer reads.
+var imdbColumns = map[string][]string{
+	domain.IMDbTitles:     {"tconst", "titleType", "primaryTitle", "isAdult", "startYear", "runtimeMinutes"},
+	domain.IMDbNames:      {"nconst", "primaryName", "birthYear", "primaryProfession"},
+	domain.IMDbPrincipals: {"tconst", "ordering", "nconst", "category", "characters"},
+}
+
+// imdbFilmTypes are the types of IMDb titles imported as films.
+var imdbFilmTypes = map[string]bool{"movie": true, "tvMovie": true}
+
+// imdbActorSexes maps the professions of actors in the IMDb names and the
+// categories of their parts in the principals to their sex.
+var imdbActorSexes = map[string]string{"actor": "m", "actress": "f"}
+
+// imdbLeads is how many of the first billed principals of a title are its
+// leads, the others are supporting.
+const imdbLeads = 3
+
+// imdbNull is how IMDb writes an unknown value.
+const imdbNull = `\N`
+
+// maxIMDbLine bounds the length of a line of an IMDb file.
+const maxIMDbLine = 1 << 20
+
+type imdbService struct {
+	s         storage.IMDbStorage
+	batchSize int
+}
+
+func NewIMDbService(s storage.IMDbStorage, batchSize int) IMDb {
+	return &imdbService{
+		s:         s,
+		batchSize: batchSize,
+	}
+}
+
+func (m *imdbService) ResetIMDbProgress() error {
+	return m.s.ResetIMDbProgress()
+}
+
+// ImportIMDb streams the gzipped IMDb file from r into the database in
+// batches. Every batch is saved together with the line it ends at, the lines
+// saved by an earlier run are skipped. Rows which don't fit the catalog, like
+// series, adult titles or people who aren't actors, are skipped too.
+func (m *imdbService) ImportIMDb(file string, r io.Reader) (domain.IMDbReport, error) {
+	report := domain.IMDbReport{File: file}
+
+	columns, ok := imdbColumns[file]
+	if !ok {
+		return report, fmt.Errorf("can't import IMDb file %q", file)
+	}
+
+	resumed, err := m.s.GetIMDbProgress(file)
+	if err != nil {
+		return report, err
+	}
+	report.Resumed = resumed
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return report, err
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64<<10), maxIMDbLine)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return report, err
+		}
+		return report, fmt.Errorf("%s is empty", file)
+	}
+	index, width, err := imdbHeader(scanner.Text(), columns)
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", file, err)
+	}
+
+	b := imdbBatch{file: file, size: m.batchSize, s: m.s}
+	line, progress := int64(0), resumed
+	for scanner.Scan() {
+		line++
+		if line <= resumed {
+			continue
+		}
+		report.Read++
+
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < width {
+			return report, fmt.Errorf("%s: line %d has %d columns", file, line+1, len(fields))
+		}
+		row := make(map[string]string, len(columns))
+		for i, column := range columns {
+			row[column] = fields[index[i]]
+		}
+
+		if !b.add(row) {
+			report.Skipped++
+		}
+		if b.len() >= b.size {
+			saved, err := b.save(line)
+			if err != nil {
+				return report, fmt.Errorf("%s: lines up to %d: %w", file, line+1, err)
+			}
+			report.Saved += saved
+			progress = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("%s: line %d: %w", file, line+2, err)
+	}
+
+	if line > progress {
+		saved, err := b.save(line)
+		if err != nil {
+			return report, fmt.Errorf("%s: lines up to %d: %w", file, line+1, err)
+		}
+		report.Saved += saved
+	}
+
+	return report, nil
+}
+
+// imdbHeader finds the columns in the header of an IMDb file. Width is how
+// many columns a line must have to hold them all.
+func imdbHeader(header string, columns []string) (index []int, width int, err error) {
+	positions := make(map[string]int)
+	for i, name := range strings.Split(header, "\t") {
+		positions[name] = i
+	}
+
+	index = make([]int, len(columns))
+	for i, column := range columns {
+		n, ok := positions[column]
+		if !ok {
+			return nil, 0, fmt.Errorf("missing column %q", column)
+		}
+		index[i] = n
+		width = max(width, n+1)
+	}
+
+	return index, width, nil
+}
+
+// imdbBatch collects the rows of a file until they are saved.
+type imdbBatch struct {
+	file    string
+	size    int
+	s       storage.IMDbStorage
+	films   []domain.IMDbFilm
+	actors  []domain.IMDbActor
+	credits []domain.IMDbCredit
+}
+
+func (b *imdbBatch) len() int {
+	return len(b.films) + len(b.actors) + len(b.credits)
+}
+
+// add decodes a row and adds it to the batch. It tells false if the row
+// doesn't fit the catalog.
+func (b *imdbBatch) add(row map[string]string) bool {
+	switch b.file {
+	case domain.IMDbTitles:
+		film, ok := imdbFilm(row)
+		if ok {
+			b.films = append(b.films, film)
+		}
+		return ok
+	case domain.IMDbNames:
+		actor, ok := imdbActor(row)
+		if ok {
+			b.actors = append(b.actors, actor)
+		}
+		return ok
+	default:
+		credit, ok := imdbCredit(row)
+		if ok {
+			b.credits = append(b.credits, credit)
+		}
+		return ok
+	}
+}
+
+// save saves the batch, which ends at line, and empties it. The progress is
+// saved even for a batch of skipped rows.
+func (b *imdbBatch) save(line int64) (int64, error) {
+	var saved int64
+	var err error
+	switch b.file {
+	case domain.IMDbTitles:
+		saved, err = b.s.SaveIMDbFilms(b.file, line, b.films)
+	case domain.IMDbNames:
+		saved, err = b.s.SaveIMDbActors(b.file, line, b.actors)
+	default:
+		saved, err = b.s.SaveIMDbCredits(b.file, line, b.credits)
+	}
+	b.films, b.actors, b.credits = b.films[:0], b.actors[:0], b.credits[:0]
+
+	return saved, err
+}
+
+func imdbFilm(row map[string]string) (domain.IMDbFilm, bool) {
+	if !imdbFilmTypes[row["titleType"]] || row["isAdult"] == "1" {
+		return domain.IMDbFilm{}, false
+	}
+
+	film := domain.IMDbFilm{IMDbID: row["tconst"], Title: row["primaryTitle"]}
+	film.Year = imdbInt(row["startYear"])
+	if runtime := imdbInt(row["runtimeMinutes"]); runtime > 0 {
+		film.RuntimeMinutes = runtime
+	}
+
+	ok := film.Title != "" && utf8.RuneCountInString(film.Title) <= 150 && film.Year > 1000
+	return film, ok
+}
+
+func imdbActor(row map[string]string) (domain.IMDbActor, bool) {
+	actor := domain.IMDbActor{IMDbID: row["nconst"], BirthYear: imdbInt(row["birthYear"])}
+	for _, profession := range strings.Split(row["primaryProfession"], ",") {
+		if sex, ok := imdbActorSexes[profession]; ok {
+			actor.Sex = sex
+			break
+		}
+	}
+
+	// The last word of the name is taken for the surname.
+	name := strings.TrimSpace(row["primaryName"])
+	if i := strings.LastIndexByte(name, ' '); i > 0 {
+		actor.Name, actor.Surname = strings.TrimSpace(name[:i]), name[i+1:]
+	}
+
+	ok := actor.Sex != "" && actor.BirthYear > 1000 && actor.Name != "" && actor.Surname != "" &&
+		utf8.RuneCountInString(actor.Name) <= 256 && utf8.RuneCountInString(actor.Surname) <= 256
+	return actor, ok
+}
+
+func imdbCredit(row map[string]string) (domain.IMDbCredit, bool) {
+	if _, ok := imdbActorSexes[row["category"]]; !ok {
+		return domain.IMDbCredit{}, false
+	}
+
+	credit := domain.IMDbCredit{FilmIMDbID: row["tconst"], ActorIMDbID: row["nconst"]}
+	credit.Billing = imdbInt(row["ordering"])
+	if credit.Billing <= 0 {
+		return credit, false
+	}
+	credit.Type = domain.CreditSupporting
+	if credit.Billing <= imdbLeads {
+		credit.Type = domain.CreditLead
+	}
+
+	// Characters are a JSON array, an actor playing several of them gets
+	// them all in one part.
+	var characters []string
+	if value := row["characters"]; value != imdbNull && json.Unmarshal([]byte(value), &characters) == nil {
+		if character := strings.Join(characters, " / "); utf8.RuneCountInString(character) <= 256 {
+			credit.Character = character
+		}
+	}
+
+	return credit, true
+}
+
+// imdbInt reads a number of an IMDb file. Unknown numbers, written as \N,
+// are 0.
+func imdbInt(value string) int {
+	n, _ := strconv.Atoi(value)
+	return n
+}
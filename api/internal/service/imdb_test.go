@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func TestIMDb_imdbHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		expectedIndex []int
+		expectedWidth int
+		expectedErr   error
+	}{
+		{
+			name:          "Ok",
+			header:        "tconst\tordering\tnconst\tcategory\tjob\tcharacters",
+			expectedIndex: []int{0, 1, 2, 3, 5},
+			expectedWidth: 6,
+		},
+		{
+			name:          "Other order",
+			header:        "characters\tnconst\tcategory\tordering\ttconst\tjob",
+			expectedIndex: []int{4, 3, 1, 2, 0},
+			expectedWidth: 5,
+		},
+		{
+			name:        "Missing column",
+			header:      "tconst\tordering\tnconst\tjob\tcharacters",
+			expectedErr: errors.New(`missing column "category"`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, width, err := imdbHeader(test.header, imdbColumns[domain.IMDbPrincipals])
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.Equal(t, test.expectedIndex, index)
+			assert.Equal(t, test.expectedWidth, width)
+		})
+	}
+}
+
+func TestIMDb_imdbFilm(t *testing.T) {
+	film := func(change func(row map[string]string)) map[string]string {
+		row := map[string]string{
+			"tconst": "tt0118767", "titleType": "movie", "primaryTitle": "Brother",
+			"isAdult": "0", "startYear": "1997", "runtimeMinutes": "100",
+		}
+		if change != nil {
+			change(row)
+		}
+		return row
+	}
+
+	tests := []struct {
+		name       string
+		row        map[string]string
+		expected   domain.IMDbFilm
+		expectedOk bool
+	}{
+		{
+			name:       "Ok",
+			row:        film(nil),
+			expected:   domain.IMDbFilm{IMDbID: "tt0118767", Title: "Brother", Year: 1997, RuntimeMinutes: 100},
+			expectedOk: true,
+		},
+		{
+			name:       "TV movie",
+			row:        film(func(row map[string]string) { row["titleType"] = "tvMovie" }),
+			expected:   domain.IMDbFilm{IMDbID: "tt0118767", Title: "Brother", Year: 1997, RuntimeMinutes: 100},
+			expectedOk: true,
+		},
+		{
+			name:       "Unknown runtime",
+			row:        film(func(row map[string]string) { row["runtimeMinutes"] = `\N` }),
+			expected:   domain.IMDbFilm{IMDbID: "tt0118767", Title: "Brother", Year: 1997},
+			expectedOk: true,
+		},
+		{
+			name:     "Unknown year",
+			row:      film(func(row map[string]string) { row["startYear"] = `\N` }),
+			expected: domain.IMDbFilm{IMDbID: "tt0118767", Title: "Brother", RuntimeMinutes: 100},
+		},
+		{
+			name:     "Wrong year",
+			row:      film(func(row map[string]string) { row["startYear"] = "19x7" }),
+			expected: domain.IMDbFilm{IMDbID: "tt0118767", Title: "Brother", RuntimeMinutes: 100},
+		},
+		{
+			name:     "Too long title",
+			row:      film(func(row map[string]string) { row["primaryTitle"] = strings.Repeat("Б", 151) }),
+			expected: domain.IMDbFilm{IMDbID: "tt0118767", Title: strings.Repeat("Б", 151), Year: 1997, RuntimeMinutes: 100},
+		},
+		{
+			name: "Series",
+			row:  film(func(row map[string]string) { row["titleType"] = "tvSeries" }),
+		},
+		{
+			name: "Adult",
+			row:  film(func(row map[string]string) { row["isAdult"] = "1" }),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			film, ok := imdbFilm(test.row)
+
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expected, film)
+		})
+	}
+}
+
+func TestIMDb_imdbActor(t *testing.T) {
+	tests := []struct {
+		name       string
+		row        map[string]string
+		expected   domain.IMDbActor
+		expectedOk bool
+	}{
+		{
+			name: "Ok",
+			row: map[string]string{"nconst": "nm0091357", "primaryName": "Sergei Bodrov",
+				"birthYear": "1971", "primaryProfession": "actor,director,writer"},
+			expected:   domain.IMDbActor{IMDbID: "nm0091357", Name: "Sergei", Surname: "Bodrov", BirthYear: 1971, Sex: "m"},
+			expectedOk: true,
+		},
+		{
+			name: "Actress after other professions",
+			row: map[string]string{"nconst": "nm0514567", "primaryName": "Renata Muratovna Litvinova",
+				"birthYear": "1967", "primaryProfession": "writer,director,actress"},
+			expected:   domain.IMDbActor{IMDbID: "nm0514567", Name: "Renata Muratovna", Surname: "Litvinova", BirthYear: 1967, Sex: "f"},
+			expectedOk: true,
+		},
+		{
+			name: "Unknown birth year",
+			row: map[string]string{"nconst": "nm0091357", "primaryName": "Sergei Bodrov",
+				"birthYear": `\N`, "primaryProfession": "actor"},
+			expected: domain.IMDbActor{IMDbID: "nm0091357", Name: "Sergei", Surname: "Bodrov", Sex: "m"},
+		},
+		{
+			name: "Unknown profession",
+			row: map[string]string{"nconst": "nm0001", "primaryName": "Aleksei Balabanov",
+				"birthYear": "1959", "primaryProfession": `\N`},
+			expected: domain.IMDbActor{IMDbID: "nm0001", Name: "Aleksei", Surname: "Balabanov", BirthYear: 1959},
+		},
+		{
+			name: "Not an actor",
+			row: map[string]string{"nconst": "nm0001", "primaryName": "Aleksei Balabanov",
+				"birthYear": "1959", "primaryProfession": "director,writer"},
+			expected: domain.IMDbActor{IMDbID: "nm0001", Name: "Aleksei", Surname: "Balabanov", BirthYear: 1959},
+		},
+		{
+			name: "One word name",
+			row: map[string]string{"nconst": "nm0002", "primaryName": "Zemfira",
+				"birthYear": "1976", "primaryProfession": "actress"},
+			expected: domain.IMDbActor{IMDbID: "nm0002", BirthYear: 1976, Sex: "f"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actor, ok := imdbActor(test.row)
+
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expected, actor)
+		})
+	}
+}
+
+func TestIMDb_imdbCredit(t *testing.T) {
+	credit := func(change func(row map[string]string)) map[string]string {
+		row := map[string]string{
+			"tconst": "tt0118767", "ordering": "1", "nconst": "nm0091357",
+			"category": "actor", "characters": `["Danila Bagrov"]`,
+		}
+		if change != nil {
+			change(row)
+		}
+		return row
+	}
+
+	tests := []struct {
+		name       string
+		row        map[string]string
+		expected   domain.IMDbCredit
+		expectedOk bool
+	}{
+		{
+			name: "Lead",
+			row:  credit(nil),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357",
+				Part: domain.Part{Character: "Danila Bagrov", Billing: 1, Type: domain.CreditLead}},
+			expectedOk: true,
+		},
+		{
+			name: "Supporting actress",
+			row: credit(func(row map[string]string) {
+				row["ordering"], row["category"] = "4", "actress"
+			}),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357",
+				Part: domain.Part{Character: "Danila Bagrov", Billing: 4, Type: domain.CreditSupporting}},
+			expectedOk: true,
+		},
+		{
+			name: "Several characters",
+			row:  credit(func(row map[string]string) { row["characters"] = `["Danila","Narrator"]` }),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357",
+				Part: domain.Part{Character: "Danila / Narrator", Billing: 1, Type: domain.CreditLead}},
+			expectedOk: true,
+		},
+		{
+			name: "Unknown characters",
+			row:  credit(func(row map[string]string) { row["characters"] = `\N` }),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357",
+				Part: domain.Part{Billing: 1, Type: domain.CreditLead}},
+			expectedOk: true,
+		},
+		{
+			name: "Wrong characters",
+			row:  credit(func(row map[string]string) { row["characters"] = `["Danila` }),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357",
+				Part: domain.Part{Billing: 1, Type: domain.CreditLead}},
+			expectedOk: true,
+		},
+		{
+			name:     "Unknown ordering",
+			row:      credit(func(row map[string]string) { row["ordering"] = `\N` }),
+			expected: domain.IMDbCredit{FilmIMDbID: "tt0118767", ActorIMDbID: "nm0091357"},
+		},
+		{
+			name: "Director",
+			row:  credit(func(row map[string]string) { row["category"] = "director" }),
+		},
+		{
+			name: "Unknown category",
+			row:  credit(func(row map[string]string) { row["category"] = `\N` }),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			credit, ok := imdbCredit(test.row)
+
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expected, credit)
+		})
+	}
+}
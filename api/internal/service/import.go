@@ -0,0 +1,449 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// importColumns lists the CSV columns of each kind of import, the required
+// ones first. Skipped columns are the ones only exports have, so that an
+// exported file can be imported back: imported rows get new IDs, no votes
+// and no credits.
+var importColumns = map[string]struct{ required, optional, skipped []string }{
+	domain.ImportFilms: {
+		required: []string{"title", "year"},
+		optional: []string{"information", "editorialRating", "runtimeMinutes", "releaseDate", "countries", "originalLanguage"},
+		skipped:  []string{"id", "rating", "votes", "credits"},
+	},
+	domain.ImportActors: {
+		required: []string{"name", "surname", "birthday", "sex"},
+		optional: []string{"patronymic", "information"},
+		skipped:  []string{"id", "credits"},
+	},
+	domain.ImportCredits: {
+		required: []string{"filmId", "actorId"},
+		optional: []string{"character", "billing", "type"},
+	},
+}
+
+// maxImportLine bounds the length of a line of an NDJSON file.
+const maxImportLine = 1 << 20
+
+type importService struct {
+	s      storage.ImportStorage
+	events CatalogListener
+}
+
+func NewImportService(s storage.ImportStorage, events CatalogListener) Import {
+	return &importService{
+		s:      s,
+		events: events,
+	}
+}
+
+// importRecord is a row of an imported file before it is decoded. A CSV row
+// has fields, an NDJSON one has data. A row which can't be split into fields
+// has only the reason why.
+type importRecord struct {
+	line   int
+	fields map[string]string
+	data   []byte
+	broken string
+}
+
+// Import validates every row of the file and saves them all in one
+// transaction. Nothing is saved if a row is wrong or in a dry run, the rows
+// are still run against the database then to find the errors it would give.
+func (m *importService) Import(e domain.Editor, kind, format string, r io.Reader, dryRun bool) (domain.ImportReport, error) {
+	if _, ok := importColumns[kind]; !ok {
+		return domain.ImportReport{}, fmt.Errorf("can't import %q", kind)
+	}
+
+	var records []importRecord
+	var err error
+	switch format {
+	case domain.ImportCSV:
+		records, err = readCSV(kind, r)
+	case domain.ImportNDJSON:
+		records, err = readNDJSON(r)
+	default:
+		return domain.ImportReport{}, fmt.Errorf("can't import from %q", format)
+	}
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	report := domain.ImportReport{
+		Kind:   kind,
+		DryRun: dryRun,
+		Total:  len(records),
+		Rows:   make([]domain.ImportRow, len(records)),
+	}
+	for i, record := range records {
+		report.Rows[i].Line = record.line
+	}
+
+	// valid holds the indexes of the rows which passed validation, in the
+	// order they are passed to the storage.
+	var valid []int
+	var ids []int64
+	var errs []error
+	switch kind {
+	case domain.ImportFilms:
+		var films []domain.Film
+		for i, record := range records {
+			if film, ok := decodeFilm(record, &report.Rows[i]); ok {
+				films, valid = append(films, film), append(valid, i)
+			}
+		}
+		if len(films) != 0 {
+			ids, errs, err = m.s.ImportFilms(e, films, !dryRun && len(films) == len(records))
+		}
+	case domain.ImportActors:
+		var actors []domain.Actor
+		for i, record := range records {
+			if actor, ok := decodeActor(record, &report.Rows[i]); ok {
+				actors, valid = append(actors, actor), append(valid, i)
+			}
+		}
+		if len(actors) != 0 {
+			ids, errs, err = m.s.ImportActors(e, actors, !dryRun && len(actors) == len(records))
+		}
+	case domain.ImportCredits:
+		var credits []domain.ImportCredit
+		for i, record := range records {
+			if credit, ok := decodeCredit(record, &report.Rows[i]); ok {
+				credits, valid = append(credits, credit), append(valid, i)
+			}
+		}
+		if len(credits) != 0 {
+			ids, errs, err = m.s.ImportCredits(e, credits, !dryRun && len(credits) == len(records))
+		}
+		if err == nil {
+			// Credits get no IDs, the films whose casts changed are told
+			// about instead.
+			for j, credit := range credits {
+				ids[j] = credit.FilmID
+			}
+		}
+	}
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	for j, i := range valid {
+		if errs[j] != nil {
+			report.Rows[i].Errors = append(report.Rows[i].Errors, errs[j].Error())
+		} else if kind != domain.ImportCredits {
+			report.Rows[i].ID = ids[j]
+		}
+	}
+	for _, row := range report.Rows {
+		if len(row.Errors) != 0 {
+			report.Failed++
+		}
+	}
+	report.Committed = !dryRun && report.Failed == 0 && report.Total != 0
+
+	if report.Committed {
+		m.notify(kind, ids)
+	}
+
+	return report, nil
+}
+
+// notify tells the listeners about the imported films and actors, or about
+// the films whose casts were imported.
+func (m *importService) notify(kind string, ids []int64) {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if kind == domain.ImportActors {
+			m.events.ActorChanged(id)
+		} else {
+			m.events.FilmChanged(id)
+		}
+	}
+}
+
+func readCSV(kind string, r io.Reader) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read csv header: %w", err)
+	}
+
+	columns := importColumns[kind]
+	known := make(map[string]bool)
+	for _, column := range columns.required {
+		known[column] = true
+	}
+	for _, column := range columns.optional {
+		known[column] = true
+	}
+	for _, column := range columns.skipped {
+		known[column] = true
+	}
+	for i, column := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !known[header[i]] {
+			return nil, fmt.Errorf("unknown column %q", header[i])
+		}
+	}
+	for _, column := range columns.required {
+		if !contains(header, column) {
+			return nil, fmt.Errorf("column %q is required", column)
+		}
+	}
+
+	var records []importRecord
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := importRecord{line: line}
+		if len(values) != len(header) {
+			record.broken = fmt.Sprintf("row has %d fields instead of %d", len(values), len(header))
+			records = append(records, record)
+			continue
+		}
+		record.fields = make(map[string]string, len(header))
+		for i, column := range header {
+			record.fields[column] = strings.TrimSpace(values[i])
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func readNDJSON(r io.Reader) ([]importRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLine)
+
+	var records []importRecord
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		records = append(records, importRecord{line: line, data: append([]byte(nil), data...)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("can't read ndjson: %w", err)
+	}
+
+	return records, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeFilm(record importRecord, row *domain.ImportRow) (domain.Film, bool) {
+	d := newRowDecoder(record)
+	var film domain.Film
+	if record.data != nil {
+		// Exported films are read with their credits, which are skipped
+		// as the other fields imports don't take.
+		var exported domain.ExportFilm
+		d.json(&exported)
+		film = exported.Film
+		film.ID, film.Rating, film.Votes = 0, sql.NullFloat64{}, 0
+	} else {
+		film = domain.Film{
+			Title:            d.fields["title"],
+			Year:             int(d.int("year")),
+			Information:      d.nullString("information"),
+			EditorialRating:  d.nullFloat("editorialRating"),
+			RuntimeMinutes:   d.nullInt("runtimeMinutes"),
+			ReleaseDate:      d.nullDate("releaseDate"),
+			Countries:        d.list("countries"),
+			OriginalLanguage: d.nullString("originalLanguage"),
+		}
+	}
+	if len(d.errs) == 0 && !film.IsValid() {
+		d.errs = append(d.errs, "film is not valid")
+	}
+
+	row.Errors = d.errs
+	return film, len(d.errs) == 0
+}
+
+func decodeActor(record importRecord, row *domain.ImportRow) (domain.Actor, bool) {
+	d := newRowDecoder(record)
+	var actor domain.Actor
+	if record.data != nil {
+		var exported domain.ExportActor
+		d.json(&exported)
+		actor = exported.Actor
+		actor.ID = 0
+	} else {
+		actor = domain.Actor{
+			Name:        d.fields["name"],
+			Surname:     d.fields["surname"],
+			Patronymic:  d.nullString("patronymic"),
+			Birthday:    d.nullDate("birthday").Time,
+			Sex:         d.fields["sex"],
+			Information: d.nullString("information"),
+		}
+	}
+	if len(d.errs) == 0 && !actor.IsValid() {
+		d.errs = append(d.errs, "actor is not valid")
+	}
+
+	row.Errors = d.errs
+	return actor, len(d.errs) == 0
+}
+
+func decodeCredit(record importRecord, row *domain.ImportRow) (domain.ImportCredit, bool) {
+	d := newRowDecoder(record)
+	credit, ok := d.credit()
+
+	row.Errors = d.errs
+	return credit, ok
+}
+
+// rowDecoder decodes the values of a record collecting what's wrong with
+// them.
+type rowDecoder struct {
+	record importRecord
+	fields map[string]string
+	errs   []string
+}
+
+func newRowDecoder(record importRecord) *rowDecoder {
+	d := &rowDecoder{record: record, fields: record.fields}
+	if record.broken != "" {
+		d.errs = append(d.errs, record.broken)
+	}
+	return d
+}
+
+func (d *rowDecoder) credit() (domain.ImportCredit, bool) {
+	var credit domain.ImportCredit
+	if d.record.data != nil {
+		d.json(&credit)
+	} else {
+		credit = domain.ImportCredit{
+			FilmID: d.int("filmId"),
+			Credit: domain.Credit{
+				ActorID: d.int("actorId"),
+				Part: domain.Part{
+					Character: d.fields["character"],
+					Billing:   int(d.nullInt("billing").Int64),
+					Type:      d.fields["type"],
+				},
+			},
+		}
+	}
+	if credit.Type == "" {
+		credit.Type = domain.CreditSupporting
+	}
+	if len(d.errs) == 0 && (credit.FilmID <= 0 || credit.ActorID <= 0) {
+		d.errs = append(d.errs, "filmId and actorId are required")
+	}
+	if len(d.errs) == 0 && !credit.IsValid() {
+		d.errs = append(d.errs, "credit is not valid")
+	}
+
+	return credit, len(d.errs) == 0
+}
+
+func (d *rowDecoder) json(v any) {
+	decoder := json.NewDecoder(bytes.NewReader(d.record.data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		d.errs = append(d.errs, fmt.Sprintf("can't decode json: %s", err))
+	}
+}
+
+func (d *rowDecoder) nullString(name string) sql.NullString {
+	value := d.fields[name]
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func (d *rowDecoder) int(name string) int64 {
+	return d.nullInt(name).Int64
+}
+
+func (d *rowDecoder) nullInt(name string) sql.NullInt64 {
+	value := d.fields[name]
+	if value == "" {
+		return sql.NullInt64{}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		d.errs = append(d.errs, fmt.Sprintf("%s must be a number", name))
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+func (d *rowDecoder) nullFloat(name string) sql.NullFloat64 {
+	value := d.fields[name]
+	if value == "" {
+		return sql.NullFloat64{}
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		d.errs = append(d.errs, fmt.Sprintf("%s must be a number", name))
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: f, Valid: true}
+}
+
+func (d *rowDecoder) nullDate(name string) sql.NullTime {
+	value := d.fields[name]
+	if value == "" {
+		return sql.NullTime{}
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		d.errs = append(d.errs, fmt.Sprintf("%s must be a date like 2006-01-02", name))
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// list splits a cell of values separated by commas or semicolons.
+func (d *rowDecoder) list(name string) []string {
+	var values []string
+	for _, value := range strings.FieldsFunc(d.fields[name], func(r rune) bool { return r == ',' || r == ';' }) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
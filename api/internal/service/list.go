@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+)
+
+type listService struct {
+	s      storage.ListStorage
+	images images
+}
+
+func NewListService(s storage.ListStorage, blobs storage.BlobStore) List {
+	return &listService{
+		s:      s,
+		images: images{blobs: blobs},
+	}
+}
+
+// GetList returns the films in the list of the user. Unless another order is
+// asked for, the films added last go first.
+func (l *listService) GetList(userId int64, list, orderBy string, desc bool) ([]domain.ListEntry, error) {
+	if !domain.IsList(list) {
+		return nil, errors.New("there is no such list")
+	}
+	switch orderBy {
+	case "title", "year", "rating":
+	default:
+		orderBy = "addedAt"
+	}
+
+	entries, err := l.s.GetList(userId, list, orderBy, desc)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Poster = l.images.image(entries[i].PosterKey)
+	}
+
+	return append(make([]domain.ListEntry, 0, len(entries)), entries...), nil
+}
+
+func (l *listService) AddToList(userId, filmId int64, list string) error {
+	if !domain.IsList(list) {
+		return errors.New("there is no such list")
+	}
+	return l.s.AddToList(userId, filmId, list)
+}
+
+func (l *listService) RemoveFromList(userId, filmId int64, list string) error {
+	if !domain.IsList(list) {
+		return errors.New("there is no such list")
+	}
+	return l.s.RemoveFromList(userId, filmId, list)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// filmPatchFields are the members of a film a merge patch may change, the
+// ones an update saves. The others, like rating or cast, are read-only.
+var filmPatchFields = []string{"title", "year", "information", "editorialRating",
+	"runtimeMinutes", "releaseDate", "countries", "originalLanguage"}
+
+// actorPatchFields are the members of an actor a merge patch may change.
+var actorPatchFields = []string{"name", "surname", "patronymic", "birthday", "sex", "information"}
+
+// mergePatch applies the JSON merge patch (RFC 7396) to the JSON form of
+// target and decodes the result into result. Keys the patch leaves out are
+// kept and null removes them, so that a removed field decodes to its zero
+// value. A patch with a member result has no field for, or one which isn't
+// in writable, is refused.
+//
+// Objects are the nullable fields in their {"String","Valid"} form. Unlike
+// RFC 7396 they aren't merged key by key but replace the field as a whole,
+// valid unless Valid is given: merged into a NULL field, {"String":"x"}
+// would keep Valid false and decode to NULL again.
+func mergePatch(target any, patch []byte, result any, writable []string) error {
+	data, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	v, err := decodeJSON(patch)
+	if err != nil {
+		return err
+	}
+	p, ok := v.(map[string]any)
+	if !ok {
+		return errors.New("merge patch must be a JSON object")
+	}
+	// The patch is decoded on its own too, so that an unknown member is
+	// refused even when it is null and removes nothing.
+	d := json.NewDecoder(bytes.NewReader(patch))
+	d.DisallowUnknownFields()
+	if err := d.Decode(reflect.New(reflect.TypeOf(result).Elem()).Interface()); err != nil {
+		return err
+	}
+	for name := range p {
+		if !slices.Contains(writable, name) {
+			return fmt.Errorf("field %q can't be patched", name)
+		}
+	}
+
+	data, err = json.Marshal(mergeValue(doc, p))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+func mergeValue(target any, patch map[string]any) any {
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	for name, value := range patch {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		if nullable, ok := value.(map[string]any); ok {
+			if _, ok := nullable["Valid"]; !ok {
+				nullable["Valid"] = true
+			}
+		}
+		t[name] = value
+	}
+
+	return t
+}
+
+// decodeJSON decodes data keeping numbers as they are written.
+func decodeJSON(data []byte) (any, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var v any
+	err := d.Decode(&v)
+
+	return v, err
+}
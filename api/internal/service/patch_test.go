@@ -0,0 +1,100 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func TestPatch_mergePatch(t *testing.T) {
+	film := domain.Film{
+		ID:          3,
+		Title:       "Брат",
+		Year:        1997,
+		Information: sql.NullString{String: "Бандитский Петербург", Valid: true},
+	}
+
+	tests := []struct {
+		name        string
+		patch       string
+		expected    domain.Film
+		expectedErr error
+	}{
+		{
+			name:     "Ok",
+			patch:    `{"title": "Брат 2", "year": 2000}`,
+			expected: domain.Film{ID: 3, Title: "Брат 2", Year: 2000, Information: film.Information},
+		},
+		{
+			name:     "Null clears",
+			patch:    `{"information": null}`,
+			expected: domain.Film{ID: 3, Title: "Брат", Year: 1997},
+		},
+		{
+			name:     "Nullable replaced",
+			patch:    `{"information": {"String": "Москва"}}`,
+			expected: domain.Film{ID: 3, Title: "Брат", Year: 1997, Information: sql.NullString{String: "Москва", Valid: true}},
+		},
+		{
+			name:  "Null to value",
+			patch: `{"runtimeMinutes": {"Int64": 99}, "originalLanguage": {"String": "ru"}}`,
+			expected: domain.Film{ID: 3, Title: "Брат", Year: 1997, Information: film.Information,
+				RuntimeMinutes: sql.NullInt64{Int64: 99, Valid: true}, OriginalLanguage: sql.NullString{String: "ru", Valid: true}},
+		},
+		{
+			name:     "Nullable cleared by Valid",
+			patch:    `{"information": {"String": "Москва", "Valid": false}}`,
+			expected: domain.Film{ID: 3, Title: "Брат", Year: 1997, Information: sql.NullString{String: "Москва"}},
+		},
+		{
+			name:        "Unknown member",
+			patch:       `{"titel": "Брат 2"}`,
+			expectedErr: errors.New(`json: unknown field "titel"`),
+		},
+		{
+			name:        "Unknown null member",
+			patch:       `{"titel": null}`,
+			expectedErr: errors.New(`json: unknown field "titel"`),
+		},
+		{
+			name:        "Unknown nested member",
+			patch:       `{"information": {"Text": "Москва"}}`,
+			expectedErr: errors.New(`json: unknown field "Text"`),
+		},
+		{
+			name:        "Read-only member",
+			patch:       `{"votes": 1000}`,
+			expectedErr: errors.New(`field "votes" can't be patched`),
+		},
+		{
+			name:        "Read-only null member",
+			patch:       `{"genres": null}`,
+			expectedErr: errors.New(`field "genres" can't be patched`),
+		},
+		{
+			name:        "ID",
+			patch:       `{"id": 4}`,
+			expectedErr: errors.New(`field "id" can't be patched`),
+		},
+		{
+			name:        "Not an object",
+			patch:       `["title"]`,
+			expectedErr: errors.New("merge patch must be a JSON object"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var patched domain.Film
+			err := mergePatch(film, []byte(test.patch), &patched, filmPatchFields)
+
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expected, patched)
+			}
+		})
+	}
+}
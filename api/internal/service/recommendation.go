@@ -0,0 +1,477 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Films are ranked by the actors they share, by how many users liked both
+// of them and by how close their years are. A shared actor weighs about as
+// much as a perfect co-rating, year proximity only separates close films.
+const (
+	sharedActorWeight = 1.0
+	coRatingWeight    = 1.5
+	yearWeight        = 0.5
+	// yearScale is the difference in years which halves year proximity.
+	yearScale = 5.0
+	// likedRating is the lowest vote which counts as liking a film.
+	likedRating = 7
+
+	defaultRecommendations = 20
+	maxRecommendations     = 100
+)
+
+// recommendationService ranks films by an in-memory index of casts and
+// votes. The index is loaded on first use and kept up to date by the
+// changes it is told about as a CatalogListener. When a change can't be
+// applied, the index is loaded anew on the next request.
+type recommendationService struct {
+	s storage.RecommendationStorage
+
+	mu     sync.RWMutex
+	loaded bool
+	films  map[int64]domain.FilmSummary
+	cast   map[int64]map[int64]bool // film ID -> actor IDs
+	roles  map[int64]map[int64]bool // actor ID -> film IDs
+	votes  map[int64]map[int64]int  // user ID -> film ID -> rating
+	voters map[int64]map[int64]int  // film ID -> user ID -> rating
+}
+
+func newRecommendationService(s storage.RecommendationStorage) *recommendationService {
+	return &recommendationService{
+		s: s,
+	}
+}
+
+// match is how a candidate film relates to the film it is compared with.
+type match struct {
+	sharedActors int
+	coLikers     int
+	yearDiff     int
+	score        float64
+}
+
+func (r *recommendationService) load() error {
+	r.mu.RLock()
+	loaded := r.loaded
+	r.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded {
+		return nil
+	}
+
+	films, err := r.s.GetFilmSummaries()
+	if err != nil {
+		return err
+	}
+	links, err := r.s.GetCastLinks()
+	if err != nil {
+		return err
+	}
+	votes, err := r.s.GetVotes()
+	if err != nil {
+		return err
+	}
+
+	r.films = make(map[int64]domain.FilmSummary, len(films))
+	r.cast = make(map[int64]map[int64]bool)
+	r.roles = make(map[int64]map[int64]bool)
+	r.votes = make(map[int64]map[int64]int)
+	r.voters = make(map[int64]map[int64]int)
+	for _, film := range films {
+		r.films[film.ID] = film
+	}
+	for _, link := range links {
+		r.addLink(link)
+	}
+	for _, vote := range votes {
+		r.addVote(vote)
+	}
+	r.loaded = true
+
+	return nil
+}
+
+func (r *recommendationService) addLink(link domain.CastLink) {
+	if r.cast[link.FilmID] == nil {
+		r.cast[link.FilmID] = make(map[int64]bool)
+	}
+	r.cast[link.FilmID][link.ActorID] = true
+	if r.roles[link.ActorID] == nil {
+		r.roles[link.ActorID] = make(map[int64]bool)
+	}
+	r.roles[link.ActorID][link.FilmID] = true
+}
+
+func (r *recommendationService) addVote(vote domain.UserVote) {
+	if r.votes[vote.UserID] == nil {
+		r.votes[vote.UserID] = make(map[int64]int)
+	}
+	r.votes[vote.UserID][vote.FilmID] = vote.Rating
+	if r.voters[vote.FilmID] == nil {
+		r.voters[vote.FilmID] = make(map[int64]int)
+	}
+	r.voters[vote.FilmID][vote.UserID] = vote.Rating
+}
+
+func (r *recommendationService) removeCast(filmId int64) {
+	for actorId := range r.cast[filmId] {
+		delete(r.roles[actorId], filmId)
+	}
+	delete(r.cast, filmId)
+}
+
+// refreshFilm loads the film and its cast anew. It must be called with the
+// lock held.
+func (r *recommendationService) refreshFilm(filmId int64) {
+	film, err := r.s.GetFilmSummary(filmId)
+	if errors.Is(err, sql.ErrNoRows) {
+		r.removeFilm(filmId)
+		return
+	}
+	if err != nil {
+		r.loaded = false
+		return
+	}
+	links, err := r.s.GetFilmCastLinks(filmId)
+	if err != nil {
+		r.loaded = false
+		return
+	}
+
+	r.films[filmId] = film
+	r.removeCast(filmId)
+	for _, link := range links {
+		r.addLink(link)
+	}
+}
+
+func (r *recommendationService) removeFilm(filmId int64) {
+	r.removeCast(filmId)
+	for userId := range r.voters[filmId] {
+		delete(r.votes[userId], filmId)
+	}
+	delete(r.voters, filmId)
+	delete(r.films, filmId)
+}
+
+func (r *recommendationService) FilmChanged(filmId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded {
+		r.refreshFilm(filmId)
+	}
+}
+
+func (r *recommendationService) FilmDeleted(filmId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded {
+		r.removeFilm(filmId)
+	}
+}
+
+// FilmRestored makes the index be built again, as the votes for the film
+// were dropped from it when the film was deleted.
+func (r *recommendationService) FilmRestored(filmId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loaded = false
+}
+
+// ActorChanged is of no interest, as recommendations don't depend on
+// actors but on whom they play with.
+func (r *recommendationService) ActorChanged(actorId int64) {}
+
+func (r *recommendationService) ActorDeleted(actorId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.loaded {
+		return
+	}
+
+	for filmId := range r.roles[actorId] {
+		delete(r.cast[filmId], actorId)
+	}
+	delete(r.roles, actorId)
+}
+
+// ActorRestored makes the index be built again with the actor in the casts
+// of their films.
+func (r *recommendationService) ActorRestored(actorId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loaded = false
+}
+
+func (r *recommendationService) Voted(userId, filmId int64, rating int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.loaded {
+		return
+	}
+
+	if _, ok := r.films[filmId]; !ok {
+		r.refreshFilm(filmId)
+		// A film which can't be loaded is left out until FilmChanged
+		// tells of it, so that it's never recommended without a title.
+		if _, ok := r.films[filmId]; !ok {
+			return
+		}
+	}
+	r.addVote(domain.UserVote{UserID: userId, FilmID: filmId, Rating: rating})
+}
+
+// likers returns the number of users who liked the film.
+func (r *recommendationService) likers(filmId int64) int {
+	n := 0
+	for _, rating := range r.voters[filmId] {
+		if rating >= likedRating {
+			n++
+		}
+	}
+	return n
+}
+
+// similar finds the films which share actors with the film or are liked by
+// the users who liked it, and scores them. It must be called with the lock
+// held.
+func (r *recommendationService) similar(filmId int64) map[int64]*match {
+	matches := make(map[int64]*match)
+	get := func(id int64) *match {
+		m, ok := matches[id]
+		if !ok {
+			m = &match{}
+			matches[id] = m
+		}
+		return m
+	}
+
+	for actorId := range r.cast[filmId] {
+		for other := range r.roles[actorId] {
+			if other != filmId {
+				get(other).sharedActors++
+			}
+		}
+	}
+
+	for userId, rating := range r.voters[filmId] {
+		if rating < likedRating {
+			continue
+		}
+		for other, otherRating := range r.votes[userId] {
+			if other != filmId && otherRating >= likedRating {
+				get(other).coLikers++
+			}
+		}
+	}
+
+	film := r.films[filmId]
+	likers := r.likers(filmId)
+	for id, m := range matches {
+		other, ok := r.films[id]
+		if !ok {
+			delete(matches, id)
+			continue
+		}
+		m.yearDiff = other.Year - film.Year
+		if m.yearDiff < 0 {
+			m.yearDiff = -m.yearDiff
+		}
+
+		m.score = sharedActorWeight*float64(m.sharedActors) +
+			yearWeight/(1+float64(m.yearDiff)/yearScale)
+		if m.coLikers > 0 {
+			// The cosine similarity of the sets of users who liked the films.
+			m.score += coRatingWeight * float64(m.coLikers) /
+				math.Sqrt(float64(likers)*float64(r.likers(id)))
+		}
+	}
+
+	return matches
+}
+
+// explain tells why a film matches the film titled title.
+func explain(m *match, title string) string {
+	var reasons []string
+	if m.sharedActors == 1 {
+		reasons = append(reasons, fmt.Sprintf("shares 1 actor with %s", title))
+	} else if m.sharedActors > 1 {
+		reasons = append(reasons, fmt.Sprintf("shares %d actors with %s", m.sharedActors, title))
+	}
+	if m.coLikers == 1 {
+		reasons = append(reasons, fmt.Sprintf("liked by 1 user who liked %s", title))
+	} else if m.coLikers > 1 {
+		reasons = append(reasons, fmt.Sprintf("liked by %d users who liked %s", m.coLikers, title))
+	}
+	if m.yearDiff == 0 {
+		reasons = append(reasons, "released the same year")
+	} else if m.yearDiff == 1 && len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("released 1 year apart from %s", title))
+	} else if m.yearDiff <= 3 && len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("released %d years apart from %s", m.yearDiff, title))
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+func recommendationLimit(limit int) int {
+	if limit <= 0 {
+		return defaultRecommendations
+	}
+	if limit > maxRecommendations {
+		return maxRecommendations
+	}
+	return limit
+}
+
+// top returns the limit best recommendations, equal scores in order of IDs.
+func top(recommendations []domain.Recommendation, limit int) []domain.Recommendation {
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].FilmID < recommendations[j].FilmID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	for i := range recommendations {
+		recommendations[i].Score = math.Round(recommendations[i].Score*1000) / 1000
+	}
+
+	return recommendations
+}
+
+func (r *recommendationService) GetSimilarFilms(filmId int64, limit int) ([]domain.Recommendation, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	film, ok := r.films[filmId]
+	if !ok {
+		return nil, fmt.Errorf("there is no film with id = %d", filmId)
+	}
+
+	matches := r.similar(filmId)
+	result := make([]domain.Recommendation, 0, len(matches))
+	for id, m := range matches {
+		other := r.films[id]
+		result = append(result, domain.Recommendation{
+			FilmID:      id,
+			Title:       other.Title,
+			Year:        other.Year,
+			Score:       m.score,
+			Explanation: explain(m, film.Title),
+		})
+	}
+
+	return top(result, recommendationLimit(limit)), nil
+}
+
+// GetRecommendations ranks the films similar to the ones the user liked,
+// the better the user rated a film the more its similar films count. Users
+// who haven't liked anything yet get the films rated best by others.
+func (r *recommendationService) GetRecommendations(userId int64, limit int) ([]domain.Recommendation, error) {
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rated := r.votes[userId]
+	scores := make(map[int64]float64)
+	type reason struct {
+		seed  int64
+		match *match
+		score float64
+	}
+	reasons := make(map[int64]reason)
+
+	for seed, rating := range rated {
+		if rating < likedRating {
+			continue
+		}
+		weight := float64(rating - likedRating + 1)
+		for id, m := range r.similar(seed) {
+			if _, ok := rated[id]; ok {
+				continue
+			}
+			score := weight * m.score
+			scores[id] += score
+			if best, ok := reasons[id]; !ok || score > best.score || score == best.score && seed < best.seed {
+				reasons[id] = reason{seed: seed, match: m, score: score}
+			}
+		}
+	}
+
+	if len(scores) == 0 {
+		return r.popular(rated, recommendationLimit(limit)), nil
+	}
+
+	result := make([]domain.Recommendation, 0, len(scores))
+	for id, score := range scores {
+		film := r.films[id]
+		best := reasons[id]
+		result = append(result, domain.Recommendation{
+			FilmID:      id,
+			Title:       film.Title,
+			Year:        film.Year,
+			Score:       score,
+			Explanation: explain(best.match, r.films[best.seed].Title),
+		})
+	}
+
+	return top(result, recommendationLimit(limit)), nil
+}
+
+// popular ranks the films not rated by the user by their average rating,
+// pulled towards the middle of the scale while they have few votes.
+func (r *recommendationService) popular(rated map[int64]int, limit int) []domain.Recommendation {
+	const priorVotes, priorRating = 3, 5.0
+
+	result := make([]domain.Recommendation, 0)
+	for id, voters := range r.voters {
+		if _, ok := rated[id]; ok || len(voters) == 0 {
+			continue
+		}
+		film, ok := r.films[id]
+		if !ok {
+			continue
+		}
+		sum := 0
+		for _, rating := range voters {
+			sum += rating
+		}
+		average := float64(sum) / float64(len(voters))
+		explanation := fmt.Sprintf("rated %.1f on average by %d users", average, len(voters))
+		if len(voters) == 1 {
+			explanation = fmt.Sprintf("rated %.1f by 1 user", average)
+		}
+		result = append(result, domain.Recommendation{
+			FilmID:      id,
+			Title:       film.Title,
+			Year:        film.Year,
+			Score:       (float64(sum) + priorVotes*priorRating) / float64(len(voters)+priorVotes),
+			Explanation: explanation,
+		})
+	}
+
+	return top(result, limit)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+// recommendationFixture is a catalog small enough to score by hand.
+type recommendationFixture struct {
+	films []domain.FilmSummary
+	links []domain.CastLink
+	votes []domain.UserVote
+}
+
+func (f recommendationFixture) GetFilmSummaries() ([]domain.FilmSummary, error) {
+	return f.films, nil
+}
+
+func (f recommendationFixture) GetFilmSummary(id int64) (domain.FilmSummary, error) {
+	for _, film := range f.films {
+		if film.ID == id {
+			return film, nil
+		}
+	}
+	return domain.FilmSummary{}, sql.ErrNoRows
+}
+
+func (f recommendationFixture) GetCastLinks() ([]domain.CastLink, error) {
+	return f.links, nil
+}
+
+func (f recommendationFixture) GetFilmCastLinks(filmId int64) ([]domain.CastLink, error) {
+	var links []domain.CastLink
+	for _, link := range f.links {
+		if link.FilmID == filmId {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (f recommendationFixture) GetVotes() ([]domain.UserVote, error) {
+	return f.votes, nil
+}
+
+var recommendationFilms = []domain.FilmSummary{
+	{ID: 1, Title: "Брат", Year: 1997},
+	{ID: 2, Title: "Брат 2", Year: 2000},
+	{ID: 3, Title: "Сёстры", Year: 2001},
+	{ID: 4, Title: "Война", Year: 2002},
+	{ID: 5, Title: "Жмурки", Year: 2005},
+}
+
+func TestRecommendationService_GetSimilarFilms(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  recommendationFixture
+		filmId   int64
+		expected []domain.Recommendation
+	}{
+		{
+			name: "Shared actors",
+			fixture: recommendationFixture{
+				films: recommendationFilms,
+				links: []domain.CastLink{
+					{FilmID: 1, ActorID: 10}, {FilmID: 1, ActorID: 11},
+					{FilmID: 2, ActorID: 10}, {FilmID: 2, ActorID: 11},
+					{FilmID: 5, ActorID: 11},
+				},
+			},
+			filmId: 1,
+			// 2 actors + 0.5/(1+3/5) and 1 actor + 0.5/(1+8/5).
+			expected: []domain.Recommendation{
+				{FilmID: 2, Title: "Брат 2", Year: 2000, Score: 2.313, Explanation: "shares 2 actors with Брат"},
+				{FilmID: 5, Title: "Жмурки", Year: 2005, Score: 1.192, Explanation: "shares 1 actor with Брат"},
+			},
+		},
+		{
+			name: "Year proximity",
+			fixture: recommendationFixture{
+				films: []domain.FilmSummary{
+					{ID: 1, Title: "Брат", Year: 1997},
+					{ID: 2, Title: "Вор", Year: 1997},
+					{ID: 3, Title: "Бумер", Year: 2003},
+					{ID: 4, Title: "Война", Year: 2002},
+				},
+				links: []domain.CastLink{
+					{FilmID: 1, ActorID: 10}, {FilmID: 2, ActorID: 10},
+					{FilmID: 3, ActorID: 10}, {FilmID: 4, ActorID: 10},
+				},
+			},
+			filmId: 1,
+			// 1 actor each, the closer the year the more of 0.5 is added.
+			expected: []domain.Recommendation{
+				{FilmID: 2, Title: "Вор", Year: 1997, Score: 1.5, Explanation: "shares 1 actor with Брат, released the same year"},
+				{FilmID: 4, Title: "Война", Year: 2002, Score: 1.25, Explanation: "shares 1 actor with Брат"},
+				{FilmID: 3, Title: "Бумер", Year: 2003, Score: 1.227, Explanation: "shares 1 actor with Брат"},
+			},
+		},
+		{
+			name: "Cosine of co-likes",
+			fixture: recommendationFixture{
+				films: recommendationFilms,
+				votes: []domain.UserVote{
+					{UserID: 100, FilmID: 1, Rating: 9}, {UserID: 100, FilmID: 2, Rating: 8},
+					{UserID: 101, FilmID: 1, Rating: 8}, {UserID: 101, FilmID: 2, Rating: 7}, {UserID: 101, FilmID: 3, Rating: 9},
+					{UserID: 102, FilmID: 1, Rating: 5}, {UserID: 102, FilmID: 3, Rating: 10},
+				},
+			},
+			filmId: 1,
+			// Both likers of Брат liked Брат 2, which has 2 likers: 1.5*2/2.
+			// One of them liked Сёстры, which has 2 likers: 1.5*1/2. The
+			// vote of 5 isn't a like.
+			expected: []domain.Recommendation{
+				{FilmID: 2, Title: "Брат 2", Year: 2000, Score: 1.813, Explanation: "liked by 2 users who liked Брат"},
+				{FilmID: 3, Title: "Сёстры", Year: 2001, Score: 1.028, Explanation: "liked by 1 user who liked Брат"},
+			},
+		},
+		{
+			name: "Nothing in common",
+			fixture: recommendationFixture{
+				films: recommendationFilms,
+				links: []domain.CastLink{{FilmID: 1, ActorID: 10}, {FilmID: 2, ActorID: 11}},
+			},
+			filmId:   1,
+			expected: []domain.Recommendation{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRecommendationService(test.fixture)
+
+			recommendations, err := r.GetSimilarFilms(test.filmId, 0)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, recommendations)
+		})
+	}
+}
+
+func TestRecommendationService_GetRecommendations(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  recommendationFixture
+		userId   int64
+		limit    int
+		expected []domain.Recommendation
+	}{
+		{
+			name: "Seed weighting",
+			fixture: recommendationFixture{
+				films: []domain.FilmSummary{
+					{ID: 1, Title: "Брат", Year: 1997},
+					{ID: 2, Title: "Брат 2", Year: 2000},
+					{ID: 3, Title: "Вор", Year: 1997},
+					{ID: 4, Title: "Сёстры", Year: 2000},
+				},
+				links: []domain.CastLink{
+					{FilmID: 1, ActorID: 10}, {FilmID: 3, ActorID: 10},
+					{FilmID: 2, ActorID: 11}, {FilmID: 4, ActorID: 11},
+				},
+				votes: []domain.UserVote{
+					{UserID: 100, FilmID: 1, Rating: 10}, {UserID: 100, FilmID: 2, Rating: 7},
+				},
+			},
+			userId: 100,
+			// Both match their seed by 1.5, a 10 weighs 4 times as much as
+			// a 7. Films the user rated aren't recommended.
+			expected: []domain.Recommendation{
+				{FilmID: 3, Title: "Вор", Year: 1997, Score: 6, Explanation: "shares 1 actor with Брат, released the same year"},
+				{FilmID: 4, Title: "Сёстры", Year: 2000, Score: 1.5, Explanation: "shares 1 actor with Брат 2, released the same year"},
+			},
+		},
+		{
+			name: "Scores of seeds add up",
+			fixture: recommendationFixture{
+				films: []domain.FilmSummary{
+					{ID: 1, Title: "Брат", Year: 1997},
+					{ID: 2, Title: "Брат 2", Year: 1997},
+					{ID: 3, Title: "Вор", Year: 1997},
+				},
+				links: []domain.CastLink{
+					{FilmID: 1, ActorID: 10}, {FilmID: 2, ActorID: 11},
+					{FilmID: 3, ActorID: 10}, {FilmID: 3, ActorID: 11},
+				},
+				votes: []domain.UserVote{
+					{UserID: 100, FilmID: 1, Rating: 8}, {UserID: 100, FilmID: 2, Rating: 9},
+				},
+			},
+			userId: 100,
+			// 2*1.5 from Брат and 3*1.5 from Брат 2, which explains it.
+			expected: []domain.Recommendation{
+				{FilmID: 3, Title: "Вор", Year: 1997, Score: 7.5, Explanation: "shares 1 actor with Брат 2, released the same year"},
+			},
+		},
+		{
+			name: "Popular fallback",
+			fixture: recommendationFixture{
+				films: recommendationFilms,
+				votes: []domain.UserVote{
+					{UserID: 100, FilmID: 1, Rating: 9}, {UserID: 100, FilmID: 2, Rating: 8},
+					{UserID: 101, FilmID: 2, Rating: 6}, {UserID: 101, FilmID: 3, Rating: 10},
+					{UserID: 200, FilmID: 1, Rating: 3},
+				},
+			},
+			userId: 200,
+			// Averages are pulled towards 5 by 3 votes, (14+15)/5 and
+			// (10+15)/4. Брат is rated by the user already.
+			expected: []domain.Recommendation{
+				{FilmID: 3, Title: "Сёстры", Year: 2001, Score: 6.25, Explanation: "rated 10.0 by 1 user"},
+				{FilmID: 2, Title: "Брат 2", Year: 2000, Score: 5.8, Explanation: "rated 7.0 on average by 2 users"},
+			},
+		},
+		{
+			name: "Popular fallback limited",
+			fixture: recommendationFixture{
+				films: recommendationFilms,
+				votes: []domain.UserVote{
+					{UserID: 100, FilmID: 2, Rating: 8}, {UserID: 101, FilmID: 3, Rating: 10},
+				},
+			},
+			userId: 200,
+			limit:  1,
+			expected: []domain.Recommendation{
+				{FilmID: 3, Title: "Сёстры", Year: 2001, Score: 6.25, Explanation: "rated 10.0 by 1 user"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newRecommendationService(test.fixture)
+
+			recommendations, err := r.GetRecommendations(test.userId, test.limit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, recommendations)
+		})
+	}
+}
+
+// lateFilmFixture has a film created after the index was built: it isn't
+// among the summaries but can be loaded on its own.
+type lateFilmFixture struct {
+	recommendationFixture
+}
+
+func (f lateFilmFixture) GetFilmSummaries() ([]domain.FilmSummary, error) {
+	return f.films[:len(f.films)-1], nil
+}
+
+func TestRecommendationService_Voted(t *testing.T) {
+	r := newRecommendationService(lateFilmFixture{recommendationFixture{films: recommendationFilms}})
+	_, err := r.GetRecommendations(200, 0)
+	assert.NoError(t, err)
+
+	// Жмурки is loaded on its vote, the film 9 can't be and is skipped.
+	r.Voted(100, 5, 9)
+	r.Voted(100, 9, 10)
+
+	recommendations, err := r.GetRecommendations(200, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Recommendation{
+		{FilmID: 5, Title: "Жмурки", Year: 2005, Score: 6, Explanation: "rated 9.0 by 1 user"},
+	}, recommendations)
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"strings"
+)
+
+type searchService struct {
+	s storage.SearchStorage
+}
+
+func NewSearchService(s storage.SearchStorage) Search {
+	return &searchService{
+		s: s,
+	}
+}
+
+func (s *searchService) Search(query string, limit int) (domain.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return domain.SearchResult{}, errors.New("search query is empty")
+	}
+
+	hits, err := s.s.Search(query, pageLimit(limit))
+	if err != nil {
+		return domain.SearchResult{}, err
+	}
+
+	return domain.SearchResult{Hits: append(make([]domain.SearchHit, 0, len(hits)), hits...)}, nil
+}
@@ -1,8 +1,11 @@
 package service
 
 import (
+	"errors"
+	"io"
 	"kinoteka/internal/domain"
 	"kinoteka/internal/storage"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=mocks/mock.go
@@ -16,35 +19,259 @@ type User interface {
 
 type Actor interface {
 	GetActorsWithFilms() ([]domain.ActorFilm, error)
-	CreateActor(actor domain.Actor) error
+	CreateActor(e domain.Editor, actor domain.Actor) (domain.Actor, error)
 	GetActor(id int64) (domain.Actor, error)
-	UpdateActor(actor domain.Actor) error
-	DeleteActor(id int64) error
-	GetActors() ([]domain.Actor, error)
+	UpdateActor(e domain.Editor, actor domain.Actor, versions domain.Versions) error
+	PatchActor(e domain.Editor, id int64, versions domain.Versions, patch []byte) (domain.Actor, error)
+	GetActorRevisions(actorId int64) ([]domain.ActorRevision, error)
+	SetPhoto(e domain.Editor, actorId int64, data []byte) (domain.Image, error)
+	DeleteActor(e domain.Editor, id int64, versions domain.Versions) error
+	RestoreActor(e domain.Editor, id int64) error
+	GetActorsPage(page domain.PageRequest) (domain.ActorPage, error)
+	GetActorFilms(actorId int64, q domain.CreditQuery) (domain.FilmCreditPage, error)
 }
 
 type Film interface {
-	GetFilmsSortLike(orderBy, title string, desc bool) ([]domain.Film, error)
-	GetFilmsLike(title string) ([]domain.Film, error)
-	GetFilmsSort(orderBy string, desc bool) ([]domain.Film, error)
-	GetFilm(id int64) (domain.Film, error)
-	CreateFilm(a domain.Film) error
-	UpdateFilm(a domain.Film) error
-	DeleteFilm(id int64) error
-	SearchFilmsWithActor(substr string) ([]domain.ActorFilm, error)
-	AddActorToFilm(filmId int64, actorId []int64) error
+	GetFilms(q domain.FilmQuery) (domain.FilmPage, error)
+	GetFilm(id, userId int64) (domain.Film, error)
+	GetFilmActors(filmId int64, q domain.CreditQuery) (domain.CastPage, error)
+	CreateFilm(e domain.Editor, a domain.Film) (domain.Film, error)
+	UpdateFilm(e domain.Editor, a domain.Film, versions domain.Versions) error
+	PatchFilm(e domain.Editor, id int64, versions domain.Versions, patch []byte) (domain.Film, error)
+	GetFilmRevisions(filmId int64) ([]domain.FilmRevision, error)
+	RevertFilm(e domain.Editor, filmId, rev int64) error
+	SetPoster(e domain.Editor, filmId int64, data []byte) (domain.Image, error)
+	RateFilm(filmId, userId int64, vote domain.Vote) (domain.FilmRating, error)
+	DeleteFilm(e domain.Editor, id int64, versions domain.Versions) error
+	RestoreFilm(e domain.Editor, id int64) error
+	AddActorToFilm(e domain.Editor, filmId int64, credits []domain.Credit) error
+	ReplaceCast(e domain.Editor, filmId int64, credits []domain.Credit) error
+	RemoveActorFromFilm(e domain.Editor, filmId, actorId int64) error
+	AddGenreToFilm(e domain.Editor, filmId int64, genreId []int64) error
+}
+
+type Genre interface {
+	GetGenres() ([]domain.Genre, error)
+	GetGenre(id int64) (domain.Genre, error)
+	CreateGenre(genre domain.Genre) error
+	UpdateGenre(genre domain.Genre) error
+	DeleteGenre(id int64) error
+}
+
+type Search interface {
+	Search(query string, limit int) (domain.SearchResult, error)
+}
+
+// List manages the personal lists of films of users.
+type List interface {
+	GetList(userId int64, list, orderBy string, desc bool) ([]domain.ListEntry, error)
+	AddToList(userId, filmId int64, list string) error
+	RemoveFromList(userId, filmId int64, list string) error
+}
+
+// History keeps the films users watched.
+type History interface {
+	GetHistory(userId int64, page domain.PageRequest) (domain.ViewingPage, error)
+	AddViewing(userId int64, v domain.Viewing) (domain.Viewing, error)
+}
+
+// Import loads catalog data from files.
+type Import interface {
+	Import(e domain.Editor, kind, format string, r io.Reader, dryRun bool) (domain.ImportReport, error)
+}
+
+// Trash lists and purges the deleted films and actors.
+type Trash interface {
+	GetTrash() ([]domain.TrashItem, error)
+	PurgeTrash(e domain.Editor, olderThan time.Duration) (domain.PurgeReport, error)
+}
+
+// IMDb seeds the catalog from the IMDb datasets.
+type IMDb interface {
+	ImportIMDb(file string, r io.Reader) (domain.IMDbReport, error)
+	ResetIMDbProgress() error
+}
+
+// Export streams the catalog to files.
+type Export interface {
+	ExportFilms(q domain.FilmQuery, withCredits bool, format string, w io.Writer) error
+	ExportActors(withCredits bool, format string, w io.Writer) error
+}
+
+// Audit searches the audit log of the changes of films and actors.
+type Audit interface {
+	GetAudit(q domain.AuditQuery) (domain.AuditPage, error)
+}
+
+// Graph finds how actors are linked by the films they play in together.
+type Graph interface {
+	GetActorPath(actorId, otherId int64) (domain.ActorPath, error)
+}
+
+// Recommendation suggests films to users.
+type Recommendation interface {
+	GetRecommendations(userId int64, limit int) ([]domain.Recommendation, error)
+	GetSimilarFilms(filmId int64, limit int) ([]domain.Recommendation, error)
+}
+
+// CatalogListener is told about the changes of films, their casts and votes,
+// so that in-memory indexes built from them stay up to date.
+type CatalogListener interface {
+	FilmChanged(filmId int64)
+	FilmDeleted(filmId int64)
+	FilmRestored(filmId int64)
+	ActorChanged(actorId int64)
+	ActorDeleted(actorId int64)
+	ActorRestored(actorId int64)
+	Voted(userId, filmId int64, rating int)
+}
+
+// CatalogListeners passes every change to each of the listeners.
+type CatalogListeners []CatalogListener
+
+func (l CatalogListeners) FilmChanged(filmId int64) {
+	for _, listener := range l {
+		listener.FilmChanged(filmId)
+	}
+}
+
+func (l CatalogListeners) FilmDeleted(filmId int64) {
+	for _, listener := range l {
+		listener.FilmDeleted(filmId)
+	}
+}
+
+func (l CatalogListeners) FilmRestored(filmId int64) {
+	for _, listener := range l {
+		listener.FilmRestored(filmId)
+	}
+}
+
+func (l CatalogListeners) ActorChanged(actorId int64) {
+	for _, listener := range l {
+		listener.ActorChanged(actorId)
+	}
+}
+
+func (l CatalogListeners) ActorDeleted(actorId int64) {
+	for _, listener := range l {
+		listener.ActorDeleted(actorId)
+	}
+}
+
+func (l CatalogListeners) ActorRestored(actorId int64) {
+	for _, listener := range l {
+		listener.ActorRestored(actorId)
+	}
+}
+
+func (l CatalogListeners) Voted(userId, filmId int64, rating int) {
+	for _, listener := range l {
+		listener.Voted(userId, filmId, rating)
+	}
+}
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// pageLimit returns the number of rows to put on a page.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+// pageCursor decodes the cursor of the page, if there is one, and checks that
+// it was issued for the same order.
+func pageCursor(page domain.PageRequest, orderBy string, desc bool) (*domain.Cursor, error) {
+	if page.Cursor == "" {
+		return nil, nil
+	}
+
+	cursor, err := domain.DecodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.OrderBy != orderBy || cursor.Desc != desc {
+		return nil, errors.New("cursor was issued for another sort order")
+	}
+
+	return &cursor, nil
+}
+
+// checkVersion tells whether a film or an actor may be changed without
+// knowing its version. The versions themselves are checked by the storage.
+func checkVersion(versions domain.Versions, required bool) error {
+	if versions == nil && required {
+		return domain.ErrVersionRequired
+	}
+	return nil
+}
+
+// Config holds the tunables of the services.
+type Config struct {
+	// FuzzyThreshold is the minimal word similarity, from 0 to 1, of a film
+	// title or an actor name to the searched one for a fuzzy match.
+	FuzzyThreshold float64
+	// MaxImageSize is the largest size, in bytes, of an uploaded poster or
+	// photo.
+	MaxImageSize int64
+	// MaxPathDepth is the largest number of films a path between two actors
+	// is searched through.
+	MaxPathDepth int
+	// TrashRetention is how long deleted films and actors are kept before
+	// they can be purged.
+	TrashRetention time.Duration
+	// RequireVersion makes films and actors be updated and deleted only
+	// with the version they are expected to be of.
+	RequireVersion bool
 }
 
 type Service struct {
 	User
 	Actor
 	Film
+	Genre
+	Search
+	List
+	History
+	Recommendation
+	Graph
+	Import
+	Export
+	Trash
+	Audit
+
+	// Config is what the services were made with, handlers bound requests
+	// by it too.
+	Config Config
 }
 
-func NewService(s *storage.Storage) *Service {
+func NewService(s *storage.Storage, cfg Config) *Service {
+	recommendation := newRecommendationService(s.RecommendationStorage)
+	graph := newGraphService(s.GraphStorage, cfg)
+	events := CatalogListeners{recommendation, graph}
+
 	return &Service{
-		User:  NewUserService(s.UserStorage),
-		Actor: NewActorService(s.ActorStorage),
-		Film:  NewFilmService(s.FilmStorage),
+		User:           NewUserService(s.UserStorage),
+		Actor:          NewActorService(s.ActorStorage, s.BlobStore, cfg, events),
+		Film:           NewFilmService(s.FilmStorage, s.BlobStore, cfg, events),
+		Genre:          NewGenreService(s.GenreStorage),
+		Search:         NewSearchService(s.SearchStorage),
+		List:           NewListService(s.ListStorage, s.BlobStore),
+		History:        NewHistoryService(s.HistoryStorage),
+		Recommendation: recommendation,
+		Graph:          graph,
+		Import:         NewImportService(s.ImportStorage, events),
+		Export:         NewExportService(s.ExportStorage, cfg),
+		Trash:          NewTrashService(s.TrashStorage, s.BlobStore, cfg),
+		Audit:          NewAuditService(s.AuditStorage),
+		Config:         cfg,
 	}
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"kinoteka/internal/domain"
+	"kinoteka/internal/storage"
+	"time"
+)
+
+type trashService struct {
+	s         storage.TrashStorage
+	images    images
+	retention time.Duration
+}
+
+func NewTrashService(s storage.TrashStorage, blobs storage.BlobStore, cfg Config) Trash {
+	return &trashService{
+		s:         s,
+		images:    images{blobs: blobs, maxSize: cfg.MaxImageSize},
+		retention: cfg.TrashRetention,
+	}
+}
+
+// GetTrash returns the deleted films and actors, the last deleted first.
+func (t *trashService) GetTrash() ([]domain.TrashItem, error) {
+	items, err := t.s.GetTrash()
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		// An empty trash is an empty list rather than null.
+		items = []domain.TrashItem{}
+	}
+
+	return items, nil
+}
+
+// PurgeTrash deletes for good the films and actors which were deleted more
+// than olderThan ago, together with their images. Each purge is recorded in
+// the audit log as made by e. Items are never purged
+// before the retention window ends, a shorter olderThan is refused.
+func (t *trashService) PurgeTrash(e domain.Editor, olderThan time.Duration) (domain.PurgeReport, error) {
+	if olderThan == 0 {
+		olderThan = t.retention
+	}
+	if olderThan < t.retention {
+		return domain.PurgeReport{}, errors.New("items can't be purged before the retention window ends")
+	}
+
+	report, keys, err := t.s.PurgeTrash(e, time.Now().Add(-olderThan))
+	if err != nil {
+		return domain.PurgeReport{}, err
+	}
+
+	// Images of purged items which can't be removed are only left behind,
+	// the items themselves are gone.
+	for _, key := range keys {
+		t.images.remove(key)
+	}
+
+	return report, nil
+}
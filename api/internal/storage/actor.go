@@ -2,7 +2,9 @@ package storage
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"kinoteka/internal/domain"
 	"time"
 )
@@ -17,16 +19,38 @@ func NewActorStorage(conn *sqlx.DB) ActorStorage {
 	}
 }
 
-const getActors = `SELECT * FROM actors`
+const getActorsPage = `SELECT id, name, surname, patronymic, birthday, sex, information, photo_key
+FROM actors WHERE id > $1 AND deleted_at IS NULL ORDER BY id LIMIT $2`
 
-func (s *actorStorage) GetActors() ([]domain.Actor, error) {
+func (s *actorStorage) GetActorsPage(limit int, afterID int64) ([]domain.Actor, error) {
 	var actors []domain.Actor
-	err := s.db.Select(&actors, getActors)
+	err := s.db.Select(&actors, getActorsPage, afterID, limit)
 
 	return actors, err
 }
 
-const getActor = `SELECT * FROM actors WHERE id = $1`
+const countActors = `SELECT COUNT(*) FROM actors WHERE deleted_at IS NULL`
+
+func (s *actorStorage) CountActors() (int64, error) {
+	var count int64
+	err := s.db.Get(&count, countActors)
+
+	return count, err
+}
+
+// actorsUpdatedAt is the last change of the actors, those in the trash
+// included.
+const actorsUpdatedAt = `SELECT MAX(updated_at) FROM actors`
+
+func (s *actorStorage) ActorsUpdatedAt() (time.Time, error) {
+	var updatedAt sql.NullTime
+	err := s.db.Get(&updatedAt, actorsUpdatedAt)
+
+	return updatedAt.Time, err
+}
+
+const getActor = `SELECT ` + actorColumns + `
+FROM actors WHERE id = $1 AND deleted_at IS NULL`
 
 func (s *actorStorage) GetActor(id int64) (domain.Actor, error) {
 	var actor domain.Actor
@@ -35,32 +59,260 @@ func (s *actorStorage) GetActor(id int64) (domain.Actor, error) {
 	return actor, err
 }
 
+const actorColumns = `id, name, surname, patronymic, birthday, sex, information, photo_key, version, updated_at`
+
 const saveActor = `INSERT INTO actors (name, surname, patronymic, birthday, sex, information)
-VALUES ($1, $2, $3, $4, $5, $6);`
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING ` + actorColumns
 
-func (s *actorStorage) CreateActor(a domain.Actor) error {
-	_, err := s.db.Exec(saveActor, a.Name, a.Surname, a.Patronymic, a.Birthday, a.Sex, a.Information)
+// CreateActor saves the actor and their audit entry in one transaction and
+// returns the actor as they were saved, with their ID.
+func (s *actorStorage) CreateActor(e domain.Editor, a domain.Actor) (domain.Actor, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return domain.Actor{}, err
+	}
+	defer tx.Rollback()
 
-	return err
+	actor, err := createActor(tx, a)
+	if err != nil {
+		return domain.Actor{}, err
+	}
+	if err := addAudit(tx, e, domain.AuditCreate, domain.AuditActor, actor.ID, nil, actor); err != nil {
+		return domain.Actor{}, err
+	}
+
+	return actor, tx.Commit()
 }
 
-const updateActor = `UPDATE actors SET name=$1, surname=$2, patronymic=$3, birthday=$4, sex=$5, information=$6
-WHERE id=$7;`
+// createActor saves the actor by q, the database or a transaction. Actors
+// are imported by it too.
+func createActor(q sqlx.Queryer, a domain.Actor) (domain.Actor, error) {
+	var actor domain.Actor
+	err := sqlx.Get(q, &actor, saveActor, a.Name, a.Surname, a.Patronymic, a.Birthday, a.Sex, a.Information)
 
-func (s *actorStorage) UpdateActor(a domain.Actor) error {
-	_, err := s.db.Exec(updateActor, a.Name, a.Surname, a.Patronymic, a.Birthday, a.Sex, a.Information, a.ID)
+	return actor, err
+}
 
-	return err
+const updateActor = `UPDATE actors SET name=$1, surname=$2, patronymic=$3, birthday=$4, sex=$5, information=$6,
+    version=version + 1
+WHERE id=$7 AND deleted_at IS NULL;`
+
+// UpdateActor saves the actor, their new revision and their audit entry in
+// one transaction. It returns domain.ErrVersionMismatch unless the actor is
+// of one of versions.
+func (s *actorStorage) UpdateActor(e domain.Editor, a domain.Actor, versions domain.Versions) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var actor domain.Actor
+	if err := tx.Get(&actor, lockActorRow, a.ID); err != nil {
+		return err
+	}
+	if !versions.Match(actor.Version) {
+		return domain.ErrVersionMismatch
+	}
+	if err := updateActorTx(tx, a); err != nil {
+		return err
+	}
+	if err := auditActor(tx, e, domain.AuditUpdate, a.ID, actor); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const getActorRow = `SELECT ` + actorColumns + ` FROM actors WHERE id = $1`
+
+const lockActorRow = `SELECT ` + actorColumns + `
+FROM actors WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+// PatchActor saves the actor apply makes of the stored one, with their new
+// revision and their audit entry, in one transaction, as PatchFilm does.
+func (s *actorStorage) PatchActor(e domain.Editor, id int64, versions domain.Versions, apply func(domain.Actor) (domain.Actor, error)) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var actor domain.Actor
+	if err := tx.Get(&actor, lockActorRow, id); err != nil {
+		return err
+	}
+	if !versions.Match(actor.Version) {
+		return domain.ErrVersionMismatch
+	}
+	a, err := apply(actor)
+	if err != nil {
+		return err
+	}
+	if err := updateActorTx(tx, a); err != nil {
+		return err
+	}
+	if err := auditActor(tx, e, domain.AuditUpdate, id, actor); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-const deleteActor = `DELETE from actors WHERE id=$1;`
+// auditActor adds the change of the actor to the audit log, comparing
+// before with the actor as tx has saved them.
+func auditActor(tx *sqlx.Tx, e domain.Editor, action string, id int64, before any) error {
+	var after domain.Actor
+	if err := tx.Get(&after, getActorRow, id); err != nil {
+		return err
+	}
 
-func (s *actorStorage) DeleteActor(id int64) error {
-	_, err := s.db.Exec(deleteActor, id)
+	return addAudit(tx, e, action, domain.AuditActor, id, before, after)
+}
+
+// updateActorTx saves the locked actor and their new revision.
+func updateActorTx(tx *sqlx.Tx, a domain.Actor) error {
+	if _, err := tx.Exec(saveFirstActorRevision, a.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(updateActor, a.Name, a.Surname, a.Patronymic, a.Birthday, a.Sex, a.Information, a.ID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(saveActorRevision, a.ID)
 
 	return err
 }
 
+const actorRevisionColumns = `name, surname, patronymic, birthday, sex, information`
+
+// saveFirstActorRevision saves the actor as they are before their first
+// update.
+const saveFirstActorRevision = `INSERT INTO actor_revisions (actor_id, rev, ` + actorRevisionColumns + `)
+SELECT id, 1, ` + actorRevisionColumns + ` FROM actors
+WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM actor_revisions WHERE actor_id = $1)`
+
+const saveActorRevision = `INSERT INTO actor_revisions (actor_id, rev, ` + actorRevisionColumns + `)
+SELECT id, (SELECT MAX(rev) + 1 FROM actor_revisions WHERE actor_id = $1), ` + actorRevisionColumns + `
+FROM actors WHERE id = $1`
+
+const getActorRevisions = `SELECT rev, created_at, actor_id AS id, ` + actorRevisionColumns + `
+FROM actor_revisions
+WHERE actor_id = $1
+ORDER BY rev DESC`
+
+// actorRevision is a row of actor_revisions.
+type actorRevision struct {
+	Rev       int64
+	CreatedAt time.Time `db:"created_at"`
+	domain.Actor
+}
+
+// GetActorRevisions returns the revisions of the actor, the latest first.
+func (s *actorStorage) GetActorRevisions(actorId int64) ([]domain.ActorRevision, error) {
+	var rows []actorRevision
+	if err := s.db.Select(&rows, getActorRevisions, actorId); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]domain.ActorRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, domain.ActorRevision{Rev: row.Rev, CreatedAt: row.CreatedAt, Actor: row.Actor})
+	}
+
+	return revisions, nil
+}
+
+// setActorPhoto moves the version of the actor too, as setFilmPoster does.
+const setActorPhoto = `UPDATE actors SET photo_key=$1, version=version + 1 WHERE id=$2;`
+
+// photoState is the photo of an actor as it's recorded in the audit log.
+type photoState struct {
+	Photo sql.NullString `json:"photo"`
+}
+
+// SetActorPhoto saves the key of the photo of the actor and their audit
+// entry in one transaction. It returns the key the actor had while they were
+// locked, even if the photo wasn't saved.
+func (s *actorStorage) SetActorPhoto(e domain.Editor, id int64, key string) (sql.NullString, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	defer tx.Rollback()
+
+	var actor domain.Actor
+	if err := tx.Get(&actor, lockActorRow, id); err != nil {
+		return sql.NullString{}, err
+	}
+	if _, err := tx.Exec(setActorPhoto, key, id); err != nil {
+		return actor.PhotoKey, err
+	}
+	after := photoState{Photo: sql.NullString{String: key, Valid: true}}
+	if err := addAudit(tx, e, domain.AuditPhoto, domain.AuditActor, id, photoState{Photo: actor.PhotoKey}, after); err != nil {
+		return actor.PhotoKey, err
+	}
+
+	return actor.PhotoKey, tx.Commit()
+}
+
+// deleteActor moves the actor to the trash. Their credits are kept, so they
+// come back with the actor when they are restored.
+const deleteActor = `UPDATE actors SET deleted_at = now() WHERE id = $1`
+
+// DeleteActor moves the actor to the trash and saves their audit entry in
+// one transaction. It returns domain.ErrVersionMismatch unless the actor is
+// of one of versions.
+func (s *actorStorage) DeleteActor(e domain.Editor, id int64, versions domain.Versions) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var actor domain.Actor
+	if err := tx.Get(&actor, lockActorRow, id); err != nil {
+		return err
+	}
+	if !versions.Match(actor.Version) {
+		return domain.ErrVersionMismatch
+	}
+	if _, err := tx.Exec(deleteActor, id); err != nil {
+		return err
+	}
+	if err := addAudit(tx, e, domain.AuditDelete, domain.AuditActor, id, actor, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const lockDeletedActor = `SELECT id FROM actors WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+
+const restoreActor = `UPDATE actors SET deleted_at = NULL WHERE id = $1`
+
+// RestoreActor takes the actor out of the trash and saves their audit entry
+// in one transaction. It returns sql.ErrNoRows if the actor isn't there.
+func (s *actorStorage) RestoreActor(e domain.Editor, id int64) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Get(&id, lockDeletedActor, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(restoreActor, id); err != nil {
+		return err
+	}
+	if err := auditActor(tx, e, domain.AuditRestore, id, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 const getActorsWithFilms = `SELECT
     a.id AS actor_id,
     a.name,
@@ -69,24 +321,41 @@ const getActorsWithFilms = `SELECT
     a.birthday,
     a.sex,
     a.information AS actor_information,
+    a.photo_key,
     f.id AS film_id,
     f.title,
     f.year,
     f.information AS film_information,
-    f.rating
+    f.rating,
+    f.votes,
+    f.editorial_rating,
+    f.runtime_minutes,
+    f.release_date,
+    f.countries,
+    f.original_language,
+    f.poster_key,
+    COALESCE(fa.character_name, '') AS character_name,
+    COALESCE(fa.billing_order, 0) AS billing_order,
+    fa.credit_type
 FROM
     actors a
         JOIN
     films_actors fa ON a.id = fa.actor_id
         JOIN
-    films f ON fa.film_id = f.id;`
+    films f ON fa.film_id = f.id
+WHERE a.deleted_at IS NULL AND f.deleted_at IS NULL
+ORDER BY a.id, f.year, f.id;`
 
 func (s *actorStorage) GetActorsWithFilms() ([]domain.ActorFilm, error) {
 	rows, err := s.db.Queryx(getActorsWithFilms)
 	if err != nil {
 		return nil, err
 	}
-	actorsFilms := make(map[int64]domain.ActorFilm)
+	defer rows.Close()
+	// actors keeps the order of the rows, so the same data always gives the
+	// same body; index only finds the actor a row belongs to.
+	var actors []domain.ActorFilm
+	index := make(map[int64]int)
 
 	for rows.Next() {
 		var (
@@ -97,15 +366,27 @@ func (s *actorStorage) GetActorsWithFilms() ([]domain.ActorFilm, error) {
 			birthday         time.Time
 			sex              string
 			actorInformation sql.NullString
+			photoKey         sql.NullString
 			filmId           int64
 			title            string
 			year             int
 			filmInformation  sql.NullString
 			rating           sql.NullFloat64
+			votes            int64
+			editorialRating  sql.NullFloat64
+			runtimeMinutes   sql.NullInt64
+			releaseDate      sql.NullTime
+			countries        pq.StringArray
+			originalLanguage sql.NullString
+			posterKey        sql.NullString
+			part             domain.Part
 		)
 
 		err := rows.Scan(&actorId, &name, &surname, &patronymic, &birthday, &sex,
-			&actorInformation, &filmId, &title, &year, &filmInformation, &rating)
+			&actorInformation, &photoKey, &filmId, &title, &year, &filmInformation,
+			&rating, &votes, &editorialRating,
+			&runtimeMinutes, &releaseDate, &countries, &originalLanguage, &posterKey,
+			&part.Character, &part.Billing, &part.Type)
 		if err != nil {
 			return nil, err
 		}
@@ -118,35 +399,91 @@ func (s *actorStorage) GetActorsWithFilms() ([]domain.ActorFilm, error) {
 			Birthday:    birthday,
 			Sex:         sex,
 			Information: actorInformation,
+			PhotoKey:    photoKey,
 		}
 		film := domain.Film{
-			ID:          filmId,
-			Title:       title,
-			Year:        year,
-			Information: filmInformation,
-			Rating:      rating,
+			ID:               filmId,
+			Title:            title,
+			Year:             year,
+			Information:      filmInformation,
+			Rating:           rating,
+			Votes:            votes,
+			EditorialRating:  editorialRating,
+			RuntimeMinutes:   runtimeMinutes,
+			ReleaseDate:      releaseDate,
+			Countries:        countries,
+			OriginalLanguage: originalLanguage,
+			PosterKey:        posterKey,
 		}
 
-		actorFilm, ok := actorsFilms[actor.ID]
+		i, ok := index[actor.ID]
 		if !ok {
-			actorFilm = domain.ActorFilm{Actor: actor}
+			i = len(actors)
+			index[actor.ID] = i
+			actors = append(actors, domain.ActorFilm{Actor: actor})
 		}
-		actorFilm.Films = append(actorFilm.Films, film)
-		actorsFilms[actor.ID] = actorFilm
+		actors[i].Films = append(actors[i].Films, domain.FilmCredit{Film: film, Part: part})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return actors, nil
+}
+
+// filmographySortKeys maps the columns the films of an actor can be ordered by
+// to the expression used for ordering and the type the cursor value is cast
+// to. Films without a rating or billing go after the others in ascending
+// order.
+var filmographySortKeys = map[string]struct{ expr, cast string }{
+	"year":    {"f.year", "int"},
+	"title":   {"f.title", "text"},
+	"rating":  {"COALESCE(f.rating, -1)", "numeric"},
+	"billing": {"COALESCE(fa.billing_order, 2147483647)", "int"},
+}
+
+const getActorFilms = `SELECT f.id, f.title, f.year, f.information, f.rating, f.votes, f.editorial_rating,
+    f.runtime_minutes, f.release_date, f.countries, f.original_language, f.poster_key,
+    COALESCE(fa.character_name, '') AS character_name,
+    COALESCE(fa.billing_order, 0) AS billing_order,
+    fa.credit_type
+FROM films_actors fa
+    JOIN films f ON f.id = fa.film_id AND f.deleted_at IS NULL
+WHERE fa.actor_id = $1 %s
+ORDER BY %s %s, f.id %[3]s
+LIMIT $2`
+
+func (s *actorStorage) GetActorFilms(actorId int64, q domain.CreditQuery, limit int, after *domain.Cursor) ([]domain.FilmCredit, error) {
+	key, ok := filmographySortKeys[q.OrderBy]
+	if !ok {
+		return nil, fmt.Errorf("can't order films by %q", q.OrderBy)
 	}
-	var actorsFilmsArray []domain.ActorFilm
 
-	for _, v := range actorsFilms {
-		actorsFilmsArray = append(actorsFilmsArray, v)
+	direction, cmp := "ASC", ">"
+	if q.Desc {
+		direction, cmp = "DESC", "<"
 	}
 
-	return actorsFilmsArray, nil
+	args := []any{actorId, limit}
+	var page string
+	if after != nil {
+		page = fmt.Sprintf("AND (%s, f.id) %s ($3::%s, $4)", key.expr, cmp, key.cast)
+		args = append(args, after.Value, after.ID)
+	}
+
+	var films []domain.FilmCredit
+	err := s.db.Select(&films, fmt.Sprintf(getActorFilms, page, key.expr, direction), args...)
+
+	return films, err
 }
 
-const deleteActorsFilms = `DELETE FROM films_actors WHERE actor_id = $1`
+const countActorFilms = `SELECT COUNT(*) FROM films_actors fa
+    JOIN films f ON f.id = fa.film_id AND f.deleted_at IS NULL
+WHERE fa.actor_id = $1`
 
-func (s *actorStorage) DeleteActorsFilms(id int64) error {
-	_, err := s.db.Exec(deleteActorsFilms, id)
+func (s *actorStorage) CountActorFilms(actorId int64) (int64, error) {
+	var count int64
+	err := s.db.Get(&count, countActorFilms, actorId)
 
-	return err
+	return count, err
 }
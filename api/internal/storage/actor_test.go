@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var actorFilmColumns = []string{"actor_id", "name", "surname", "patronymic", "birthday", "sex",
+	"actor_information", "photo_key", "film_id", "title", "year", "film_information",
+	"rating", "votes", "editorial_rating", "runtime_minutes", "release_date", "countries",
+	"original_language", "poster_key", "character_name", "billing_order", "credit_type"}
+
+// TestActorStorage_GetActorsWithFilms reads the same rows twice: the ETag of
+// GET /actor?withFilms=true is taken over the body, so both reads must give
+// the same body with the actors in the order of the rows.
+func TestActorStorage_GetActorsWithFilms(t *testing.T) {
+	birthday := time.Date(1971, 12, 27, 0, 0, 0, 0, time.UTC)
+	rows := func() *sqlmock.Rows {
+		rows := sqlmock.NewRows(actorFilmColumns)
+		for id := int64(1); id <= 8; id++ {
+			for film := int64(1); film <= 2; film++ {
+				rows.AddRow(id, "Сергей", "Бодров", nil, birthday, "male", nil, nil,
+					id*10+film, "Брат", 1997, nil, nil, 0, nil, nil, nil, "{}", nil, nil, "Данила", 1, "actor")
+			}
+		}
+		return rows
+	}
+
+	db, mock := newMockDB(t)
+	mock.ExpectQuery(`SELECT\s+a.id AS actor_id`).WillReturnRows(rows())
+	mock.ExpectQuery(`SELECT\s+a.id AS actor_id`).WillReturnRows(rows())
+	s := NewActorStorage(db)
+
+	first, err := s.GetActorsWithFilms()
+	assert.NoError(t, err)
+	second, err := s.GetActorsWithFilms()
+	assert.NoError(t, err)
+
+	for i, actor := range first {
+		assert.Equal(t, int64(i+1), actor.Actor.ID)
+		assert.Len(t, actor.Films, 2)
+	}
+	firstJSON, err := json.Marshal(first)
+	assert.NoError(t, err)
+	secondJSON, err := json.Marshal(second)
+	assert.NoError(t, err)
+	assert.Equal(t, string(firstJSON), string(secondJSON))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
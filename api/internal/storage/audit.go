@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+	"strings"
+)
+
+type auditStorage struct {
+	db *sqlx.DB
+}
+
+func NewAuditStorage(conn *sqlx.DB) AuditStorage {
+	return &auditStorage{
+		db: conn,
+	}
+}
+
+const addAuditEntry = `INSERT INTO audit_log (user_id, request_id, action, entity_type, entity_id, diff)
+VALUES ($1, $2, $3, $4, $5, $6)`
+
+// addAudit adds the change of the entity to the audit log by tx, so that the
+// entry is saved with the change or not at all. The states before and after
+// it are compared field by field, nil means the entity didn't exist.
+func addAudit(tx sqlx.Execer, e domain.Editor, action, entityType string, entityId int64, before, after any) error {
+	d, err := domain.NewDiff(before, after)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(addAuditEntry, e.UserID, e.RequestID, action, entityType, entityId, d)
+
+	return err
+}
+
+const getAudit = `SELECT id, user_id, request_id, action, entity_type, entity_id, diff, created_at
+FROM audit_log
+WHERE %s
+ORDER BY id DESC
+LIMIT %d`
+
+func (s *auditStorage) GetAudit(q domain.AuditQuery, limit int, afterID int64) ([]domain.AuditEntry, error) {
+	where, args := auditFilter(q)
+	if afterID != 0 {
+		args = append(args, afterID)
+		where = append(where, fmt.Sprintf("id < $%d", len(args)))
+	}
+
+	var entries []domain.AuditEntry
+	err := s.db.Select(&entries, fmt.Sprintf(getAudit, strings.Join(where, " AND "), limit), args...)
+
+	return entries, err
+}
+
+const countAudit = `SELECT COUNT(*) FROM audit_log WHERE %s`
+
+func (s *auditStorage) CountAudit(q domain.AuditQuery) (int64, error) {
+	where, args := auditFilter(q)
+
+	var count int64
+	err := s.db.Get(&count, fmt.Sprintf(countAudit, strings.Join(where, " AND ")), args...)
+
+	return count, err
+}
+
+// auditFilter returns the WHERE conditions of q with their positional
+// arguments.
+func auditFilter(q domain.AuditQuery) ([]string, []any) {
+	where := []string{"TRUE"}
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.EntityType != "" {
+		add("entity_type = $%d", q.EntityType)
+	}
+	if q.EntityID != 0 {
+		add("entity_id = $%d", q.EntityID)
+	}
+	if q.UserID != 0 {
+		add("user_id = $%d", q.UserID)
+	}
+	if !q.From.IsZero() {
+		add("created_at >= $%d", q.From)
+	}
+	if !q.To.IsZero() {
+		add("created_at < $%d", q.To)
+	}
+
+	return where, args
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type exportStorage struct {
+	db *sqlx.DB
+}
+
+func NewExportStorage(conn *sqlx.DB) ExportStorage {
+	return &exportStorage{
+		db: conn,
+	}
+}
+
+const filmCredits = `(
+    SELECT json_agg(json_build_object(
+        'actorId', fa.actor_id,
+        'character', COALESCE(fa.character_name, ''),
+        'billing', COALESCE(fa.billing_order, 0),
+        'type', fa.credit_type) ORDER BY fa.billing_order NULLS LAST, fa.actor_id)
+    FROM films_actors fa JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+    WHERE fa.film_id = f.id) AS credits`
+
+// exportFilmRow is a film read for export, its credits come as JSON.
+type exportFilmRow struct {
+	domain.Film
+	Credits []byte `db:"credits"`
+}
+
+// ExportFilms passes the films matching q to fn one by one in order of IDs,
+// reading them from the database as fn takes them. A fuzzy q matches titles
+// and actors by similarity, as GetFilms does.
+func (s *exportStorage) ExportFilms(q domain.FilmQuery, withCredits bool, fn func(domain.ExportFilm) error) error {
+	var b filmQueryBuilder
+	b.filter(q)
+
+	var columns string
+	if withCredits {
+		columns = ", " + filmCredits
+	}
+
+	return queryFilms(s.db, q, func(db sqlx.Queryer) error {
+		rows, err := db.Queryx(b.sql(fmt.Sprintf(selectFilms, columns))+" ORDER BY f.id", b.args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var row exportFilmRow
+			if err := rows.StructScan(&row); err != nil {
+				return err
+			}
+
+			film := domain.ExportFilm{Film: row.Film}
+			if row.Credits != nil {
+				if err := json.Unmarshal(row.Credits, &film.Credits); err != nil {
+					return err
+				}
+			}
+			if err := fn(film); err != nil {
+				return err
+			}
+		}
+
+		return rows.Err()
+	})
+}
+
+const selectExportActors = `SELECT a.id, a.name, a.surname, a.patronymic, a.birthday, a.sex, a.information, a.photo_key%s
+FROM actors a WHERE a.deleted_at IS NULL ORDER BY a.id`
+
+const actorCredits = `(
+    SELECT json_agg(json_build_object(
+        'filmId', fa.film_id,
+        'character', COALESCE(fa.character_name, ''),
+        'billing', COALESCE(fa.billing_order, 0),
+        'type', fa.credit_type) ORDER BY fa.film_id)
+    FROM films_actors fa JOIN films f ON f.id = fa.film_id AND f.deleted_at IS NULL
+    WHERE fa.actor_id = a.id) AS credits`
+
+// exportActorRow is an actor read for export, the credits come as JSON.
+type exportActorRow struct {
+	domain.Actor
+	Credits []byte `db:"credits"`
+}
+
+// ExportActors passes all actors to fn one by one in order of IDs, reading
+// them from the database as fn takes them.
+func (s *exportStorage) ExportActors(withCredits bool, fn func(domain.ExportActor) error) error {
+	var columns string
+	if withCredits {
+		columns = ", " + actorCredits
+	}
+	rows, err := s.db.Queryx(fmt.Sprintf(selectExportActors, columns))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row exportActorRow
+		if err := rows.StructScan(&row); err != nil {
+			return err
+		}
+
+		actor := domain.ExportActor{Actor: row.Actor}
+		if row.Credits != nil {
+			if err := json.Unmarshal(row.Credits, &actor.Credits); err != nil {
+				return err
+			}
+		}
+		if err := fn(actor); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
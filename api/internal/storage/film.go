@@ -2,11 +2,10 @@ package storage
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"kinoteka/internal/domain"
-	"strings"
 	"time"
 )
 
@@ -20,202 +19,736 @@ func NewFilmStorage(conn *sqlx.DB) FilmStorage {
 	}
 }
 
-const getFilmsLike = `SELECT * FROM films WHERE LOWER(title) LIKE '%' || LOWER($1) || '%';`
+// getFilmId reads the film with the vote of the user. Its updated_at is the
+// last change of the film or of an actor of its cast, as both are shown.
+const getFilmId = `SELECT f.id, f.title, f.year, f.information, f.rating, f.votes, f.editorial_rating,
+    f.runtime_minutes, f.release_date, f.countries, f.original_language, f.poster_key, f.version,
+    GREATEST(f.updated_at, (
+        SELECT MAX(a.updated_at) FROM films_actors fa JOIN actors a ON a.id = fa.actor_id
+        WHERE fa.film_id = f.id
+    )) AS updated_at,
+    r.rating AS user_rating
+FROM films f
+    LEFT JOIN ratings r ON r.film_id = f.id AND r.user_id = $2
+WHERE f.id = $1 AND f.deleted_at IS NULL`
+
+func (s *filmStorage) GetFilm(id, userId int64) (domain.Film, error) {
+	var film domain.Film
+	err := s.db.Get(&film, getFilmId, id, userId)
 
-func (s *filmStorage) GetFilmsLike(title string) ([]domain.Film, error) {
+	return film, err
+}
 
-	var films []domain.Film
-	err := s.db.Select(&films, getFilmsLike, title)
+// filmsUpdatedAt is the last change of the films or the actors, as films are
+// searched by their actors too. Films in the trash count, since moving them
+// there changes the lists.
+const filmsUpdatedAt = `SELECT GREATEST((SELECT MAX(updated_at) FROM films), (SELECT MAX(updated_at) FROM actors))`
 
-	return films, err
+func (s *filmStorage) FilmsUpdatedAt() (time.Time, error) {
+	var updatedAt sql.NullTime
+	err := s.db.Get(&updatedAt, filmsUpdatedAt)
+
+	return updatedAt.Time, err
 }
 
-const getFilmsSort = `SELECT id, title, year, information, rating FROM films ORDER BY`
+// filmColumns are the columns of a film of its own, without the vote of a
+// user.
+const filmColumns = `id, title, year, information, rating, votes, editorial_rating,
+    runtime_minutes, release_date, countries, original_language, poster_key, version, updated_at`
 
-func (s *filmStorage) GetFilmsSort(orderBy string, desc bool) ([]domain.Film, error) {
-	var sql string
+const saveFilm = `INSERT INTO films (title, year, information, editorial_rating,
+    runtime_minutes, release_date, countries, original_language)
+VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::varchar(2)[], '{}'), $8)
+RETURNING ` + filmColumns
 
-	if desc {
-		sql = fmt.Sprintf("%s %s %s", getFilmsSort, orderBy, "DESC;")
-	} else {
-		sql = fmt.Sprintf("%s %s %s", getFilmsSort, orderBy, "ASC;")
+// CreateFilm saves the film and its audit entry in one transaction and
+// returns the film as it was saved, with its ID.
+func (s *filmStorage) CreateFilm(e domain.Editor, a domain.Film) (domain.Film, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return domain.Film{}, err
 	}
+	defer tx.Rollback()
 
-	var films []domain.Film
-	err := s.db.Select(&films, sql)
+	film, err := createFilm(tx, a)
+	if err != nil {
+		return domain.Film{}, err
+	}
+	if err := addAudit(tx, e, domain.AuditCreate, domain.AuditFilm, film.ID, nil, film); err != nil {
+		return domain.Film{}, err
+	}
 
-	return films, err
+	return film, tx.Commit()
 }
 
-const getFilmsSortLike = `SELECT * FROM films WHERE LOWER(title) LIKE '%' || LOWER($1) || '%' ORDER BY`
+// createFilm saves the film by q, the database or a transaction. Films are
+// imported by it too.
+func createFilm(q sqlx.Queryer, a domain.Film) (domain.Film, error) {
+	var film domain.Film
+	err := sqlx.Get(q, &film, saveFilm, a.Title, a.Year, a.Information, a.EditorialRating,
+		a.RuntimeMinutes, a.ReleaseDate, a.Countries, a.OriginalLanguage)
+
+	return film, err
+}
+
+const updateFilm = `UPDATE films SET title=$1, year=$2, information=$3, editorial_rating=$4,
+    runtime_minutes=$5, release_date=$6, countries=COALESCE($7::varchar(2)[], '{}'), original_language=$8,
+    version=version + 1
+WHERE id=$9 AND deleted_at IS NULL;`
+
+// UpdateFilm saves the film, its new revision and its audit entry in one
+// transaction. It returns domain.ErrVersionMismatch unless the film is of
+// one of versions.
+func (s *filmStorage) UpdateFilm(e domain.Editor, a domain.Film, versions domain.Versions) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var film domain.Film
+	if err := tx.Get(&film, lockFilmRow, a.ID); err != nil {
+		return err
+	}
+	if !versions.Match(film.Version) {
+		return domain.ErrVersionMismatch
+	}
+	if err := updateFilmTx(tx, a); err != nil {
+		return err
+	}
+	if err := auditFilm(tx, e, domain.AuditUpdate, a.ID, film); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const getFilmRow = `SELECT ` + filmColumns + ` FROM films WHERE id = $1`
+
+const lockFilmRow = `SELECT ` + filmColumns + `
+FROM films WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+// PatchFilm saves the film apply makes of the stored one, with its new
+// revision and its audit entry, in one transaction, so that nothing changes
+// the film in between. It returns domain.ErrVersionMismatch unless the film
+// is of one of versions.
+func (s *filmStorage) PatchFilm(e domain.Editor, id int64, versions domain.Versions, apply func(domain.Film) (domain.Film, error)) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-func (s *filmStorage) GetFilmsSortLike(orderBy, title string, desc bool) ([]domain.Film, error) {
-	if orderBy != "title" && orderBy != "year" {
-		orderBy = "rating"
+	var film domain.Film
+	if err := tx.Get(&film, lockFilmRow, id); err != nil {
+		return err
+	}
+	if !versions.Match(film.Version) {
+		return domain.ErrVersionMismatch
+	}
+	a, err := apply(film)
+	if err != nil {
+		return err
+	}
+	if err := updateFilmTx(tx, a); err != nil {
+		return err
+	}
+	if err := auditFilm(tx, e, domain.AuditUpdate, id, film); err != nil {
+		return err
 	}
 
-	var sql string
+	return tx.Commit()
+}
 
-	if desc {
-		sql = fmt.Sprintf("%s %s %s", getFilmsSortLike, orderBy, "DESC")
-	} else {
-		sql = fmt.Sprintf("%s %s %s", getFilmsSortLike, orderBy, "ASC")
+// auditFilm adds the change of the film to the audit log, comparing before
+// with the film as tx has saved it.
+func auditFilm(tx *sqlx.Tx, e domain.Editor, action string, id int64, before any) error {
+	var after domain.Film
+	if err := tx.Get(&after, getFilmRow, id); err != nil {
+		return err
 	}
 
-	var films []domain.Film
-	err := s.db.Select(&films, sql, title)
+	return addAudit(tx, e, action, domain.AuditFilm, id, before, after)
+}
 
-	return films, err
+// updateFilmTx saves the locked film and its new revision.
+func updateFilmTx(tx *sqlx.Tx, a domain.Film) error {
+	if _, err := tx.Exec(saveFirstFilmRevision, a.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(updateFilm, a.Title, a.Year, a.Information, a.EditorialRating,
+		a.RuntimeMinutes, a.ReleaseDate, a.Countries, a.OriginalLanguage, a.ID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(saveFilmRevision, a.ID)
+
+	return err
 }
 
-const getFilmId = `SELECT id, title, year, information, rating
+const filmRevisionColumns = `title, year, information, editorial_rating,
+    runtime_minutes, release_date, countries, original_language`
+
+// saveFirstFilmRevision saves the film as it is before its first update.
+const saveFirstFilmRevision = `INSERT INTO film_revisions (film_id, rev, ` + filmRevisionColumns + `)
+SELECT id, 1, ` + filmRevisionColumns + ` FROM films
+WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM film_revisions WHERE film_id = $1)`
+
+const saveFilmRevision = `INSERT INTO film_revisions (film_id, rev, ` + filmRevisionColumns + `)
+SELECT id, (SELECT MAX(rev) + 1 FROM film_revisions WHERE film_id = $1), ` + filmRevisionColumns + `
 FROM films WHERE id = $1`
 
-func (s *filmStorage) GetFilm(id int64) (domain.Film, error) {
+const getFilmRevisions = `SELECT rev, created_at, film_id AS id, ` + filmRevisionColumns + `
+FROM film_revisions
+WHERE film_id = $1
+ORDER BY rev DESC`
+
+// filmRevision is a row of film_revisions.
+type filmRevision struct {
+	Rev       int64
+	CreatedAt time.Time `db:"created_at"`
+	domain.Film
+}
+
+// GetFilmRevisions returns the revisions of the film, the latest first.
+func (s *filmStorage) GetFilmRevisions(filmId int64) ([]domain.FilmRevision, error) {
+	var rows []filmRevision
+	if err := s.db.Select(&rows, getFilmRevisions, filmId); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]domain.FilmRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, domain.FilmRevision{Rev: row.Rev, CreatedAt: row.CreatedAt, Film: row.Film})
+	}
+
+	return revisions, nil
+}
+
+const revertFilm = `UPDATE films f SET title = r.title, year = r.year, information = r.information,
+    editorial_rating = r.editorial_rating, runtime_minutes = r.runtime_minutes,
+    release_date = r.release_date, countries = r.countries, original_language = r.original_language,
+    version = f.version + 1
+FROM film_revisions r
+WHERE f.id = $1 AND r.film_id = f.id AND r.rev = $2`
+
+// RevertFilm brings the film back to the revision and saves it as a new
+// revision with its audit entry, in one transaction. It returns
+// sql.ErrNoRows if the film or the revision doesn't exist.
+func (s *filmStorage) RevertFilm(e domain.Editor, filmId, rev int64) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	var film domain.Film
-	err := s.db.Get(&film, getFilmId, id)
+	if err := tx.Get(&film, lockFilmRow, filmId); err != nil {
+		return err
+	}
+	res, err := tx.Exec(revertFilm, filmId, rev)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec(saveFilmRevision, filmId); err != nil {
+		return err
+	}
+	if err := auditFilm(tx, e, domain.AuditRevert, filmId, film); err != nil {
+		return err
+	}
 
-	return film, err
+	return tx.Commit()
 }
 
-const saveFilm = `INSERT INTO films (title, year, information, rating)
-VALUES ($1, $2, $3, $4);`
+// setFilmPoster moves the version of the film too, so that its ETags read
+// before the upload no longer match.
+const setFilmPoster = `UPDATE films SET poster_key=$1, version=version + 1 WHERE id=$2;`
 
-func (s *filmStorage) CreateFilm(a domain.Film) error {
-	_, err := s.db.Exec(saveFilm, a.Title, a.Year, a.Information, a.Rating)
+// posterState is the poster of a film as it's recorded in the audit log.
+type posterState struct {
+	Poster sql.NullString `json:"poster"`
+}
 
-	return err
+// SetFilmPoster saves the key of the poster of the film and its audit entry
+// in one transaction. It returns the key the film had while it was locked,
+// even if the poster wasn't saved, so that the caller knows which file is
+// still in use.
+func (s *filmStorage) SetFilmPoster(e domain.Editor, id int64, key string) (sql.NullString, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	defer tx.Rollback()
+
+	var film domain.Film
+	if err := tx.Get(&film, lockFilmRow, id); err != nil {
+		return sql.NullString{}, err
+	}
+	if _, err := tx.Exec(setFilmPoster, key, id); err != nil {
+		return film.PosterKey, err
+	}
+	after := posterState{Poster: sql.NullString{String: key, Valid: true}}
+	if err := addAudit(tx, e, domain.AuditPoster, domain.AuditFilm, id, posterState{Poster: film.PosterKey}, after); err != nil {
+		return film.PosterKey, err
+	}
+
+	return film.PosterKey, tx.Commit()
 }
 
-const updateFilm = `UPDATE films SET title=$1, year=$2, information=$3, rating=$4 WHERE id=$5;`
+// liveFilm finds a film which isn't in the trash.
+const liveFilm = `SELECT id FROM films WHERE id = $1 AND deleted_at IS NULL`
 
-func (s *filmStorage) UpdateFilm(a domain.Film) error {
-	_, err := s.db.Exec(updateFilm, a.Title, a.Year, a.Information, a.Rating, a.ID)
+// touchFilm tells that the cast or the genres of the film were changed. The
+// trigger of films moves its updated_at.
+const touchFilm = `UPDATE films SET updated_at = now() WHERE id = $1`
 
-	return err
+const lockFilm = liveFilm + ` FOR UPDATE`
+
+const saveVote = `INSERT INTO ratings (user_id, film_id, rating) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, film_id) DO UPDATE SET rating = EXCLUDED.rating, rated_at = now()`
+
+const updateFilmRating = `UPDATE films SET (rating, votes) = (
+    SELECT ROUND(AVG(rating), 1), COUNT(*) FROM ratings WHERE film_id = $1)
+WHERE id = $1
+RETURNING rating, votes, $2::smallint AS user_rating`
+
+// RateFilm saves the vote of the user and recomputes the rating of the film.
+// The film row is locked first, so concurrent votes are all counted.
+func (s *filmStorage) RateFilm(filmId, userId int64, rating int) (domain.FilmRating, error) {
+	var result domain.FilmRating
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, lockFilm, filmId); err != nil {
+		return result, err
+	}
+	if _, err := tx.Exec(saveVote, userId, filmId, rating); err != nil {
+		return result, err
+	}
+	if err := tx.Get(&result, updateFilmRating, filmId, rating); err != nil {
+		return result, err
+	}
+
+	return result, tx.Commit()
 }
 
-const deleteFilm = `DELETE from films WHERE id=$1;`
+// deleteFilm moves the film to the trash. Its cast, genres and votes are
+// kept, so it comes back whole when it is restored.
+const deleteFilm = `UPDATE films SET deleted_at = now() WHERE id = $1`
 
-func (s *filmStorage) DeleteFilm(id int64) error {
-	_, err := s.db.Exec(deleteFilm, id)
+const deleteFilmsListEntries = `WITH deleted AS (DELETE FROM watchlist WHERE film_id = $1)
+DELETE FROM favorites WHERE film_id = $1`
 
-	return err
+// DeleteFilm moves the film to the trash, takes it off every watchlist and
+// favorites list and saves its audit entry in one transaction. It returns domain.ErrVersionMismatch unless the film is of
+// one of versions.
+func (s *filmStorage) DeleteFilm(e domain.Editor, id int64, versions domain.Versions) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var film domain.Film
+	if err := tx.Get(&film, lockFilmRow, id); err != nil {
+		return err
+	}
+	if !versions.Match(film.Version) {
+		return domain.ErrVersionMismatch
+	}
+	if _, err := tx.Exec(deleteFilm, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(deleteFilmsListEntries, id); err != nil {
+		return err
+	}
+	if err := addAudit(tx, e, domain.AuditDelete, domain.AuditFilm, id, film, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-const searchFilmsWithActor = `
-SELECT
-    a.id AS actor_id,
-    a.name,
-    a.surname,
-    a.patronymic,
-    a.birthday,
-    a.sex,
-    a.information AS actor_information,
-    f.id AS film_id,
-    f.title,
-    f.year,
-    f.information AS film_information,
-    f.rating
-FROM
-    actors a
-        JOIN
-    films_actors fa ON a.id = fa.actor_id
-        JOIN
-    films f ON fa.film_id = f.id
-WHERE LOWER(name) like '%' || $1 || '%' or
-      LOWER(surname) like '%' || $1 || '%' or
-      LOWER(patronymic) like '%' || $1 || '%';
-`
-
-func (s *filmStorage) SearchFilmsWithActor(substr string) ([]domain.ActorFilm, error) {
-	rows, err := s.db.Queryx(searchFilmsWithActor, strings.ToLower(substr))
+const lockDeletedFilm = `SELECT id FROM films WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`
+
+const restoreFilm = `UPDATE films SET deleted_at = NULL WHERE id = $1`
+
+// RestoreFilm takes the film out of the trash and saves its audit entry in
+// one transaction. It returns sql.ErrNoRows if the film isn't there.
+func (s *filmStorage) RestoreFilm(e domain.Editor, id int64) error {
+	tx, err := s.db.Beginx()
 	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.Get(&id, lockDeletedFilm, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(restoreFilm, id); err != nil {
+		return err
+	}
+	if err := auditFilm(tx, e, domain.AuditRestore, id, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const addActorToFilm = `INSERT INTO films_actors (film_id, actor_id, character_name, billing_order, credit_type)
+VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), $5)`
+
+const getKnownActors = `SELECT id FROM actors WHERE id = ANY($1) AND deleted_at IS NULL`
+
+const getCastActors = `SELECT actor_id FROM films_actors WHERE film_id = $1 AND actor_id = ANY($2)`
+
+// AddActorToFilm adds the credits to the cast of the film and saves the
+// audit entry in one transaction. If an actor doesn't exist or is in the
+// cast already, none of them is added.
+func (s *filmStorage) AddActorToFilm(e domain.Editor, filmId int64, credits []domain.Credit) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, lockFilm, filmId); err != nil {
+		return err
+	}
+	before, err := getCastState(tx, filmId)
+	if err != nil {
+		return err
+	}
+
+	errs, err := checkCredits(tx, credits)
+	if err != nil {
+		return err
+	}
+	var present []int64
+	if err := tx.Select(&present, getCastActors, filmId, pq.Array(creditActors(credits))); err != nil {
+		return err
+	}
+	for _, actorId := range present {
+		errs = append(errs, domain.CreditError{ActorID: actorId, Reason: domain.CreditAlreadyPresent})
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	if err := addCredits(tx, filmId, credits); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(touchFilm, filmId); err != nil {
+		return err
+	}
+	if err := auditCast(tx, e, domain.AuditCastAdd, filmId, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteFilmCast removes the cast of the film, except the credits of actors
+// in the trash, which come back with them.
+const deleteFilmCast = `DELETE FROM films_actors fa USING actors a
+WHERE fa.film_id = $1 AND a.id = fa.actor_id AND a.deleted_at IS NULL`
+
+// ReplaceFilmCast makes the credits the whole cast of the film and saves the
+// audit entry in one transaction. If an actor doesn't exist, the cast is
+// left as it was.
+func (s *filmStorage) ReplaceFilmCast(e domain.Editor, filmId int64, credits []domain.Credit) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, lockFilm, filmId); err != nil {
+		return err
+	}
+	before, err := getCastState(tx, filmId)
+	if err != nil {
+		return err
+	}
+
+	errs, err := checkCredits(tx, credits)
+	if err != nil {
+		return err
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+
+	if _, err := tx.Exec(deleteFilmCast, filmId); err != nil {
+		return err
+	}
+	if err := addCredits(tx, filmId, credits); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(touchFilm, filmId); err != nil {
+		return err
+	}
+	if err := auditCast(tx, e, domain.AuditCastReplace, filmId, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const removeActorFromFilm = `DELETE FROM films_actors WHERE film_id = $1 AND actor_id = $2`
+
+// RemoveActorFromFilm removes the actor from the cast of the film and saves
+// the audit entry in one transaction.
+func (s *filmStorage) RemoveActorFromFilm(e domain.Editor, filmId, actorId int64) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, lockFilm, filmId); err != nil {
+		return err
+	}
+	before, err := getCastState(tx, filmId)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(removeActorFromFilm, filmId, actorId)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.CreditErrors{{ActorID: actorId, Reason: domain.CreditNotInCast}}
+	}
+	if _, err := tx.Exec(touchFilm, filmId); err != nil {
+		return err
+	}
+	if err := auditCast(tx, e, domain.AuditCastRemove, filmId, before); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// checkCredits returns the errors of the credits of actors which don't
+// exist.
+func checkCredits(tx *sqlx.Tx, credits []domain.Credit) (domain.CreditErrors, error) {
+	var known []int64
+	if err := tx.Select(&known, getKnownActors, pq.Array(creditActors(credits))); err != nil {
 		return nil, err
 	}
-	actorsFilms := make(map[int64]domain.ActorFilm)
-
-	for rows.Next() {
-		var (
-			actorId          int64
-			name             string
-			surname          string
-			patronymic       sql.NullString
-			birthday         time.Time
-			sex              string
-			actorInformation sql.NullString
-			filmId           int64
-			title            string
-			year             int
-			filmInformation  sql.NullString
-			rating           sql.NullFloat64
-		)
-
-		err := rows.Scan(&actorId, &name, &surname, &patronymic, &birthday, &sex,
-			&actorInformation, &filmId, &title, &year, &filmInformation, &rating)
-		if err != nil {
-			return nil, err
-		}
 
-		actor := domain.Actor{
-			ID:          actorId,
-			Name:        name,
-			Surname:     surname,
-			Patronymic:  patronymic,
-			Birthday:    birthday,
-			Sex:         sex,
-			Information: actorInformation,
-		}
-		film := domain.Film{
-			ID:          filmId,
-			Title:       title,
-			Year:        year,
-			Information: filmInformation,
-			Rating:      rating,
+	exists := make(map[int64]bool, len(known))
+	for _, actorId := range known {
+		exists[actorId] = true
+	}
+
+	var errs domain.CreditErrors
+	for _, credit := range credits {
+		if !exists[credit.ActorID] {
+			errs = append(errs, domain.CreditError{ActorID: credit.ActorID, Reason: domain.CreditUnknownActor})
 		}
+	}
 
-		actorFilm, ok := actorsFilms[actor.ID]
-		if !ok {
-			actorFilm = domain.ActorFilm{Actor: actor}
+	return errs, nil
+}
+
+// castState is the cast of a film as it's recorded in the audit log.
+type castState struct {
+	Cast []domain.Credit `json:"cast"`
+}
+
+func getCastState(tx *sqlx.Tx, filmId int64) (castState, error) {
+	var cast []domain.CastMember
+	if err := tx.Select(&cast, getFilmCast, filmId); err != nil {
+		return castState{}, err
+	}
+
+	state := castState{Cast: make([]domain.Credit, 0, len(cast))}
+	for _, member := range cast {
+		state.Cast = append(state.Cast, domain.Credit{ActorID: member.ID, Part: member.Part})
+	}
+
+	return state, nil
+}
+
+// auditCast adds the change of the cast of the film to the audit log,
+// comparing before with the cast as tx has saved it.
+func auditCast(tx *sqlx.Tx, e domain.Editor, action string, filmId int64, before castState) error {
+	after, err := getCastState(tx, filmId)
+	if err != nil {
+		return err
+	}
+
+	return addAudit(tx, e, action, domain.AuditFilm, filmId, before, after)
+}
+
+func addCredits(tx *sqlx.Tx, filmId int64, credits []domain.Credit) error {
+	for _, el := range credits {
+		_, err := tx.Exec(addActorToFilm, filmId, el.ActorID, el.Character, el.Billing, el.Type)
+		if err != nil {
+			return fmt.Errorf("can't add actor with id = %d to film with id = %d: %w", el.ActorID, filmId, err)
 		}
-		actorFilm.Films = append(actorFilm.Films, film)
-		actorsFilms[actor.ID] = actorFilm
 	}
-	var actorsFilmsArray []domain.ActorFilm
 
-	for _, v := range actorsFilms {
-		actorsFilmsArray = append(actorsFilmsArray, v)
+	return nil
+}
+
+func creditActors(credits []domain.Credit) []int64 {
+	ids := make([]int64, 0, len(credits))
+	for _, credit := range credits {
+		ids = append(ids, credit.ActorID)
 	}
+	return ids
+}
 
-	return actorsFilmsArray, nil
+const getFilmCast = `SELECT
+    a.id, a.name, a.surname, a.patronymic, a.birthday, a.sex, a.information, a.photo_key,
+    COALESCE(fa.character_name, '') AS character_name,
+    COALESCE(fa.billing_order, 0) AS billing_order,
+    fa.credit_type
+FROM films_actors fa
+    JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+WHERE fa.film_id = $1
+ORDER BY fa.billing_order NULLS LAST, a.surname, a.name, a.id`
+
+func (s *filmStorage) GetFilmCast(id int64) ([]domain.CastMember, error) {
+	var cast []domain.CastMember
+	err := s.db.Select(&cast, getFilmCast, id)
+
+	return cast, err
 }
 
-const deleteFilmsActors = `DELETE FROM films_actors WHERE film_id = $1`
+// castSortKeys maps the columns the cast of a film can be ordered by to the
+// expression used for ordering and the type the cursor value is cast to.
+// Actors without billing go after the others in ascending order.
+var castSortKeys = map[string]struct{ expr, cast string }{
+	"billing":  {"COALESCE(fa.billing_order, 2147483647)", "int"},
+	"name":     {"a.surname || ' ' || a.name", "text"},
+	"birthday": {"a.birthday", "date"},
+}
 
-func (s *filmStorage) DeleteFilmsActors(id int64) error {
-	_, err := s.db.Exec(deleteFilmsActors, id)
+const getFilmActors = `SELECT
+    a.id, a.name, a.surname, a.patronymic, a.birthday, a.sex, a.information, a.photo_key,
+    COALESCE(fa.character_name, '') AS character_name,
+    COALESCE(fa.billing_order, 0) AS billing_order,
+    fa.credit_type
+FROM films_actors fa
+    JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+WHERE fa.film_id = $1 %s
+ORDER BY %s %s, a.id %[3]s
+LIMIT $2`
+
+func (s *filmStorage) GetFilmActors(filmId int64, q domain.CreditQuery, limit int, after *domain.Cursor) ([]domain.CastMember, error) {
+	key, ok := castSortKeys[q.OrderBy]
+	if !ok {
+		return nil, fmt.Errorf("can't order actors by %q", q.OrderBy)
+	}
 
-	return err
+	direction, cmp := "ASC", ">"
+	if q.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	args := []any{filmId, limit}
+	var page string
+	if after != nil {
+		page = fmt.Sprintf("AND (%s, a.id) %s ($3::%s, $4)", key.expr, cmp, key.cast)
+		args = append(args, after.Value, after.ID)
+	}
+
+	var cast []domain.CastMember
+	err := s.db.Select(&cast, fmt.Sprintf(getFilmActors, page, key.expr, direction), args...)
+
+	return cast, err
+}
+
+const countFilmActors = `SELECT COUNT(*) FROM films_actors fa
+    JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+WHERE fa.film_id = $1`
+
+func (s *filmStorage) CountFilmActors(filmId int64) (int64, error) {
+	var count int64
+	err := s.db.Get(&count, countFilmActors, filmId)
+
+	return count, err
+}
+
+const getFilmGenres = `SELECT g.id, g.name FROM genres g
+JOIN films_genres fg ON fg.genre_id = g.id
+WHERE fg.film_id = $1 ORDER BY g.name`
+
+func (s *filmStorage) GetFilmGenres(id int64) ([]domain.Genre, error) {
+	var genres []domain.Genre
+	err := s.db.Select(&genres, getFilmGenres, id)
+
+	return genres, err
+}
+
+const addGenreToFilm = `INSERT INTO films_genres (film_id, genre_id) VALUES ($1, $2)`
+
+// genresState is the genres of a film as they're recorded in the audit log.
+type genresState struct {
+	Genres []domain.Genre `json:"genres"`
 }
 
-const addActorToFilm = `INSERT INTO films_actors (film_id, actor_id) VALUES ($1, $2)`
+func getGenresState(tx *sqlx.Tx, filmId int64) (genresState, error) {
+	state := genresState{Genres: []domain.Genre{}}
+	err := tx.Select(&state.Genres, getFilmGenres, filmId)
 
-func (s *filmStorage) AddActorToFilm(filmId int64, actorId []int64) error {
+	return state, err
+}
+
+// AddGenreToFilm adds the genres to the film and saves its audit entry in
+// one transaction.
+func (s *filmStorage) AddGenreToFilm(e domain.Editor, filmId int64, genreId []int64) error {
 	tx, err := s.db.Beginx()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, lockFilm, filmId); err != nil {
+		return err
+	}
+	before, err := getGenresState(tx, filmId)
+	if err != nil {
+		return err
+	}
 
-	for _, el := range actorId {
-		_, err := s.db.Exec(addActorToFilm, filmId, el)
+	for _, el := range genreId {
+		_, err := tx.Exec(addGenreToFilm, filmId, el)
 		if err != nil {
-			err = tx.Rollback()
-			if err != nil {
-				return err
-			}
-			return errors.New(fmt.Sprintf("Can't add actor with id = %d to film with id = %d", el, filmId))
+			return fmt.Errorf("can't add genre %d to film %d: %w", el, filmId, err)
 		}
 	}
+	if _, err := tx.Exec(touchFilm, filmId); err != nil {
+		return err
+	}
+
+	after, err := getGenresState(tx, filmId)
+	if err != nil {
+		return err
+	}
+	if err := addAudit(tx, e, domain.AuditGenreAdd, domain.AuditFilm, filmId, before, after); err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"kinoteka/internal/domain"
+	"strings"
+)
+
+// filmSortKeys maps the columns films can be ordered by to the expression used
+// for ordering and the type the cursor value is cast to. Films without a
+// rating, runtime or release date go after the others in ascending order.
+var filmSortKeys = map[string]struct{ expr, cast string }{
+	"id":          {"f.id", "bigint"},
+	"rating":      {"COALESCE(f.rating, -1)", "numeric"},
+	"title":       {"f.title", "text"},
+	"year":        {"f.year", "int"},
+	"runtime":     {"COALESCE(f.runtime_minutes, -1)", "int"},
+	"releaseDate": {"COALESCE(f.release_date, '-infinity')", "date"},
+}
+
+const selectFilms = `SELECT f.id, f.title, f.year, f.information, f.rating, f.votes, f.editorial_rating,
+    f.runtime_minutes, f.release_date, f.countries, f.original_language, f.poster_key%s
+FROM films f`
+
+const filmUserRating = `(
+    SELECT r.rating FROM ratings r WHERE r.film_id = f.id AND r.user_id = %s) AS user_rating`
+
+const countFilms = `SELECT COUNT(*) FROM films f`
+
+const filmHasActor = `EXISTS (
+    SELECT 1 FROM films_actors fa JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+    WHERE fa.film_id = f.id AND (
+        LOWER(a.name) LIKE %[1]s OR
+        LOWER(a.surname) LIKE %[1]s OR
+        LOWER(a.patronymic) LIKE %[1]s OR
+        LOWER(a.name || ' ' || a.surname) LIKE %[1]s))`
+
+const filmHasAnyGenre = `EXISTS (
+    SELECT 1 FROM films_genres fg JOIN genres g ON g.id = fg.genre_id
+    WHERE fg.film_id = f.id AND LOWER(g.name) = ANY(%s))`
+
+const filmHasAllGenres = `(
+    SELECT COUNT(DISTINCT LOWER(g.name)) FROM films_genres fg JOIN genres g ON g.id = fg.genre_id
+    WHERE fg.film_id = f.id AND LOWER(g.name) = ANY(%s)) = %s`
+
+const filmInWatchlist = `EXISTS (
+    SELECT 1 FROM watchlist w WHERE w.film_id = f.id AND w.user_id = %s)`
+
+const filmHasSimilarActor = `EXISTS (
+    SELECT 1 FROM films_actors fa JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+    WHERE fa.film_id = f.id AND %[1]s <%% (a.name || ' ' || a.surname))`
+
+const filmActorSimilarity = `(
+    SELECT MAX(word_similarity(%[1]s, a.name || ' ' || a.surname))
+    FROM films_actors fa JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL
+    WHERE fa.film_id = f.id)`
+
+// setSimilarityThreshold makes the <% operator, which the trigram indexes
+// support, match by the threshold of the query until the transaction ends.
+const setSimilarityThreshold = `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`
+
+// filmQueryBuilder collects the WHERE conditions of a film query together
+// with their positional arguments.
+type filmQueryBuilder struct {
+	where      []string
+	args       []any
+	similarity string
+}
+
+func (b *filmQueryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// filter adds the conditions of q. Films in the trash never match.
+func (b *filmQueryBuilder) filter(q domain.FilmQuery) {
+	b.where = append(b.where, "f.deleted_at IS NULL")
+	if q.Fuzzy {
+		b.fuzzyFilter(q)
+	} else {
+		if q.Title != "" {
+			b.where = append(b.where, "LOWER(f.title) LIKE "+b.arg(likePattern(q.Title)))
+		}
+		if q.Actor != "" {
+			b.where = append(b.where, fmt.Sprintf(filmHasActor, b.arg(likePattern(q.Actor))))
+		}
+	}
+	if len(q.Genres) != 0 {
+		b.genreFilter(q.Genres, q.AllGenres)
+	}
+	if q.YearFrom != 0 {
+		b.where = append(b.where, "f.year >= "+b.arg(q.YearFrom))
+	}
+	if q.YearTo != 0 {
+		b.where = append(b.where, "f.year <= "+b.arg(q.YearTo))
+	}
+	if q.MinRating != nil {
+		b.where = append(b.where, "f.rating >= "+b.arg(*q.MinRating))
+	}
+	if q.MaxRating != nil {
+		b.where = append(b.where, "f.rating <= "+b.arg(*q.MaxRating))
+	}
+	if q.HasRating != nil {
+		if *q.HasRating {
+			b.where = append(b.where, "f.rating IS NOT NULL")
+		} else {
+			b.where = append(b.where, "f.rating IS NULL")
+		}
+	}
+	if q.MinRuntime != 0 {
+		b.where = append(b.where, "f.runtime_minutes >= "+b.arg(q.MinRuntime))
+	}
+	if q.MaxRuntime != 0 {
+		b.where = append(b.where, "f.runtime_minutes <= "+b.arg(q.MaxRuntime))
+	}
+	if !q.ReleasedFrom.IsZero() {
+		b.where = append(b.where, "f.release_date >= "+b.arg(q.ReleasedFrom))
+	}
+	if !q.ReleasedTo.IsZero() {
+		b.where = append(b.where, "f.release_date <= "+b.arg(q.ReleasedTo))
+	}
+	if q.Country != "" {
+		b.where = append(b.where, b.arg(strings.ToUpper(q.Country))+" = ANY(f.countries)")
+	}
+	if q.Language != "" {
+		b.where = append(b.where, "f.original_language = "+b.arg(strings.ToLower(q.Language)))
+	}
+	if q.InWatchlist != nil {
+		inWatchlist := fmt.Sprintf(filmInWatchlist, b.arg(q.UserID))
+		if !*q.InWatchlist {
+			inWatchlist = "NOT " + inWatchlist
+		}
+		b.where = append(b.where, inWatchlist)
+	}
+}
+
+func (b *filmQueryBuilder) genreFilter(genres []string, all bool) {
+	names := make([]string, 0, len(genres))
+	seen := make(map[string]bool, len(genres))
+	for _, genre := range genres {
+		name := strings.ToLower(genre)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	if all {
+		b.where = append(b.where, fmt.Sprintf(filmHasAllGenres, b.arg(pq.Array(names)), b.arg(len(names))))
+	} else {
+		b.where = append(b.where, fmt.Sprintf(filmHasAnyGenre, b.arg(pq.Array(names))))
+	}
+}
+
+// fuzzyFilter matches title and actor by word similarity and builds the
+// similarity score of a film. When both are given, a film scores as its worst
+// match.
+func (b *filmQueryBuilder) fuzzyFilter(q domain.FilmQuery) {
+	var scores []string
+	if q.Title != "" {
+		title := b.arg(q.Title)
+		b.where = append(b.where, title+" <% f.title")
+		scores = append(scores, fmt.Sprintf("word_similarity(%s, f.title)", title))
+	}
+	if q.Actor != "" {
+		actor := b.arg(q.Actor)
+		b.where = append(b.where, fmt.Sprintf(filmHasSimilarActor, actor))
+		scores = append(scores, fmt.Sprintf(filmActorSimilarity, actor))
+	}
+
+	switch len(scores) {
+	case 0:
+		b.similarity = "1::real"
+	case 1:
+		b.similarity = scores[0]
+	default:
+		b.similarity = "LEAST(" + strings.Join(scores, ", ") + ")"
+	}
+}
+
+func (b *filmQueryBuilder) sql(base string) string {
+	if len(b.where) == 0 {
+		return base
+	}
+	return base + " WHERE " + strings.Join(b.where, " AND ")
+}
+
+// likePattern turns s into a case-insensitive substring pattern for LIKE.
+func likePattern(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(s))
+	return "%" + s + "%"
+}
+
+// queryFilms runs fn against db. Fuzzy queries are run in a transaction
+// with the similarity threshold of q.
+func queryFilms(db *sqlx.DB, q domain.FilmQuery, fn func(db sqlx.Queryer) error) error {
+	if !q.Fuzzy {
+		return fn(db)
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(setSimilarityThreshold, fmt.Sprint(q.FuzzyThreshold)); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *filmStorage) GetFilms(q domain.FilmQuery, limit int, after *domain.Cursor) ([]domain.Film, error) {
+	var b filmQueryBuilder
+	b.filter(q)
+
+	key, ok := filmSortKeys[q.OrderBy]
+	if q.OrderBy == "similarity" && q.Fuzzy {
+		key.expr, key.cast, ok = b.similarity, "real", true
+	}
+	if !ok {
+		return nil, fmt.Errorf("can't order films by %q", q.OrderBy)
+	}
+
+	direction, cmp := "ASC", ">"
+	if q.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	if after != nil {
+		b.where = append(b.where, fmt.Sprintf("(%s, f.id) %s (%s::%s, %s)",
+			key.expr, cmp, b.arg(after.Value), key.cast, b.arg(after.ID)))
+	}
+
+	var columns string
+	if q.Fuzzy {
+		columns = ", " + b.similarity + " AS similarity"
+	}
+	if q.UserID != 0 {
+		columns += ", " + fmt.Sprintf(filmUserRating, b.arg(q.UserID))
+	}
+	sql := fmt.Sprintf("%s ORDER BY %s %s, f.id %s LIMIT %s",
+		b.sql(fmt.Sprintf(selectFilms, columns)), key.expr, direction, direction, b.arg(limit))
+
+	var films []domain.Film
+	err := queryFilms(s.db, q, func(db sqlx.Queryer) error {
+		return sqlx.Select(db, &films, sql, b.args...)
+	})
+
+	return films, err
+}
+
+func (s *filmStorage) CountFilms(q domain.FilmQuery) (int64, error) {
+	var b filmQueryBuilder
+	b.filter(q)
+
+	var count int64
+	err := queryFilms(s.db, q, func(db sqlx.Queryer) error {
+		return sqlx.Get(db, &count, b.sql(countFilms), b.args...)
+	})
+
+	return count, err
+}
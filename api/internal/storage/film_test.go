@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func newMockDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+// countriesParam is how the countries of a film must be bound: an untyped
+// $7 in COALESCE resolves to text, which can't be saved to varchar(2)[].
+var countriesParam = regexp.QuoteMeta(`COALESCE($7::varchar(2)[], '{}')`)
+
+var editor = domain.Editor{UserID: 1, RequestID: "req-1"}
+
+var filmRowColumns = []string{"id", "title", "year", "countries", "version"}
+
+func TestFilmStorage_CreateFilm(t *testing.T) {
+	tests := []struct {
+		name      string
+		film      domain.Film
+		countries any
+	}{
+		{
+			name:      "With countries",
+			film:      domain.Film{Title: "Брат", Year: 1997, Countries: pq.StringArray{"RU"}},
+			countries: `{"RU"}`,
+		},
+		{
+			name:      "Without countries",
+			film:      domain.Film{Title: "Брат", Year: 1997},
+			countries: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`INSERT INTO films .*`+countriesParam+`.*RETURNING id, title`).
+				WithArgs(test.film.Title, test.film.Year, test.film.Information, test.film.EditorialRating,
+					test.film.RuntimeMinutes, test.film.ReleaseDate, test.countries, test.film.OriginalLanguage).
+				WillReturnRows(sqlmock.NewRows(filmRowColumns).AddRow(7, test.film.Title, test.film.Year, "{}", 1))
+			mock.ExpectExec(`INSERT INTO audit_log`).
+				WithArgs(editor.UserID, editor.RequestID, domain.AuditCreate, domain.AuditFilm, int64(7), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+
+			film, err := NewFilmStorage(db).CreateFilm(editor, test.film)
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(7), film.ID)
+			assert.Equal(t, int64(1), film.Version)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilmStorage_UpdateFilm(t *testing.T) {
+	film := domain.Film{
+		ID:          3,
+		Title:       "Брат 2",
+		Year:        2000,
+		Information: sql.NullString{String: "2:07", Valid: true},
+		Countries:   pq.StringArray{"RU", "US"},
+	}
+
+	tests := []struct {
+		name        string
+		versions    domain.Versions
+		auditErr    error
+		expectedErr error
+	}{
+		{
+			name: "Ok",
+		},
+		{
+			name:     "Listed version",
+			versions: domain.Versions{1, 2},
+		},
+		{
+			name:        "Stale version",
+			versions:    domain.Versions{1},
+			expectedErr: domain.ErrVersionMismatch,
+		},
+		{
+			name:        "No version matches",
+			versions:    domain.Versions{},
+			expectedErr: domain.ErrVersionMismatch,
+		},
+		{
+			name:        "Audit fails",
+			auditErr:    errors.New("audit_log is full"),
+			expectedErr: errors.New("audit_log is full"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT id, title, .* FROM films WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).
+				WithArgs(film.ID).
+				WillReturnRows(sqlmock.NewRows(filmRowColumns).AddRow(film.ID, "Брат", 1997, "{RU}", 2))
+			if test.expectedErr != domain.ErrVersionMismatch {
+				mock.ExpectExec(`INSERT INTO film_revisions`).WithArgs(film.ID).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`UPDATE films SET .*`+countriesParam).
+					WithArgs(film.Title, film.Year, film.Information, film.EditorialRating,
+						film.RuntimeMinutes, film.ReleaseDate, `{"RU","US"}`, film.OriginalLanguage, film.ID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO film_revisions`).WithArgs(film.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT id, title, .* FROM films WHERE id = \$1$`).WithArgs(film.ID).
+					WillReturnRows(sqlmock.NewRows(filmRowColumns).AddRow(film.ID, film.Title, film.Year, "{RU,US}", 3))
+				audit := mock.ExpectExec(`INSERT INTO audit_log`).
+					WithArgs(editor.UserID, editor.RequestID, domain.AuditUpdate, domain.AuditFilm, film.ID,
+						[]byte(`{"countries":{"before":["RU"],"after":["RU","US"]},"title":{"before":"Брат","after":"Брат 2"},"year":{"before":1997,"after":2000}}`))
+				if test.auditErr != nil {
+					audit.WillReturnError(test.auditErr)
+				} else {
+					audit.WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectCommit()
+				}
+			}
+			if test.expectedErr != nil {
+				mock.ExpectRollback()
+			}
+
+			err := NewFilmStorage(db).UpdateFilm(editor, film, test.versions)
+
+			assert.Equal(t, test.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilmStorage_SetFilmPoster(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, title, .* FROM films WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "year", "poster_key"}).AddRow(3, "Брат", 1997, "films/3/poster/old.jpg"))
+	mock.ExpectExec(`UPDATE films SET poster_key=\$1, version=version \+ 1`).WithArgs("films/3/poster/new.jpg", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditPoster, domain.AuditFilm, int64(3),
+			[]byte(`{"poster":{"before":{"String":"films/3/poster/old.jpg","Valid":true},"after":{"String":"films/3/poster/new.jpg","Valid":true}}}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	previous, err := NewFilmStorage(db).SetFilmPoster(editor, 3, "films/3/poster/new.jpg")
+
+	assert.NoError(t, err)
+	assert.Equal(t, sql.NullString{String: "films/3/poster/old.jpg", Valid: true}, previous)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilmStorage_DeleteFilm(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, title, .* FROM films WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "year", "version"}).AddRow(3, "Брат", 1997, 2))
+	mock.ExpectExec(`UPDATE films SET deleted_at = now\(\) WHERE id = \$1`).WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM watchlist WHERE film_id = \$1\)\s+DELETE FROM favorites WHERE film_id = \$1`).
+		WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditDelete, domain.AuditFilm, int64(3), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := NewFilmStorage(db).DeleteFilm(editor, 3, nil)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFilmStorage_AddGenreToFilm(t *testing.T) {
+	genreColumns := []string{"id", "name"}
+
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM films WHERE id = \$1 AND deleted_at IS NULL FOR UPDATE`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectQuery(`SELECT g.id, g.name FROM genres g`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(genreColumns))
+	mock.ExpectExec(`INSERT INTO films_genres`).WithArgs(int64(3), int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE films SET updated_at = now\(\) WHERE id = \$1`).WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT g.id, g.name FROM genres g`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(genreColumns).AddRow(2, "Драма"))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditGenreAdd, domain.AuditFilm, int64(3),
+			[]byte(`{"genres":{"before":[],"after":[{"id":2,"name":"Драма"}]}}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := NewFilmStorage(db).AddGenreToFilm(editor, 3, []int64{2})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type genreStorage struct {
+	db *sqlx.DB
+}
+
+func NewGenreStorage(conn *sqlx.DB) GenreStorage {
+	return &genreStorage{
+		db: conn,
+	}
+}
+
+const getGenres = `SELECT id, name FROM genres ORDER BY name`
+
+func (s *genreStorage) GetGenres() ([]domain.Genre, error) {
+	var genres []domain.Genre
+	err := s.db.Select(&genres, getGenres)
+
+	return genres, err
+}
+
+const getGenre = `SELECT id, name FROM genres WHERE id = $1`
+
+func (s *genreStorage) GetGenre(id int64) (domain.Genre, error) {
+	var genre domain.Genre
+	err := s.db.Get(&genre, getGenre, id)
+
+	return genre, err
+}
+
+const saveGenre = `INSERT INTO genres (name) VALUES ($1);`
+
+func (s *genreStorage) CreateGenre(g domain.Genre) error {
+	_, err := s.db.Exec(saveGenre, g.Name)
+
+	return err
+}
+
+// updateGenre renames the genre and, as the genres of a film are a part of
+// it, moves updated_at of the films of the genre in the same statement.
+const updateGenre = `WITH genre AS (UPDATE genres SET name=$1 WHERE id=$2 RETURNING id)
+UPDATE films SET updated_at = now()
+WHERE id IN (SELECT film_id FROM films_genres WHERE genre_id IN (SELECT id FROM genre));`
+
+func (s *genreStorage) UpdateGenre(g domain.Genre) error {
+	_, err := s.db.Exec(updateGenre, g.Name, g.ID)
+
+	return err
+}
+
+const deleteGenresFilms = `WITH deleted AS (DELETE FROM films_genres WHERE genre_id = $1 RETURNING film_id)
+UPDATE films SET updated_at = now() WHERE id IN (SELECT film_id FROM deleted)`
+
+const deleteGenre = `DELETE FROM genres WHERE id=$1;`
+
+// DeleteGenre takes the genre away from its films, moving their updated_at,
+// and deletes it in one transaction.
+func (s *genreStorage) DeleteGenre(id int64) error {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(deleteGenresFilms, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(deleteGenre, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
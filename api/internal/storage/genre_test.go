@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func TestGenreStorage_UpdateGenre(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectExec(`UPDATE genres SET name=\$1 WHERE id=\$2 .*\s+UPDATE films SET updated_at = now\(\)`).
+		WithArgs("Драма", int64(2)).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := NewGenreStorage(db).UpdateGenre(domain.Genre{ID: 2, Name: "Драма"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGenreStorage_DeleteGenre(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM films_genres WHERE genre_id = \$1 RETURNING film_id\)\s+UPDATE films SET updated_at = now\(\)`).
+		WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`DELETE FROM genres WHERE id=\$1`).WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := NewGenreStorage(db).DeleteGenre(2)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type graphStorage struct {
+	db *sqlx.DB
+}
+
+func NewGraphStorage(conn *sqlx.DB) GraphStorage {
+	return &graphStorage{
+		db: conn,
+	}
+}
+
+const getPathActors = `SELECT id, name, surname FROM actors WHERE deleted_at IS NULL`
+
+func (s *graphStorage) GetPathActors() ([]domain.PathActor, error) {
+	var actors []domain.PathActor
+	err := s.db.Select(&actors, getPathActors)
+
+	return actors, err
+}
+
+const getPathFilms = `SELECT DISTINCT f.id, f.title, f.year FROM films f
+JOIN films_actors fa ON fa.film_id = f.id
+WHERE f.deleted_at IS NULL`
+
+func (s *graphStorage) GetPathFilms() ([]domain.PathFilm, error) {
+	var films []domain.PathFilm
+	err := s.db.Select(&films, getPathFilms)
+
+	return films, err
+}
+
+// getPathLinks are the edges of the graph: the credits of actors and films
+// which aren't in the trash.
+const getPathLinks = `SELECT fa.film_id, fa.actor_id FROM films_actors fa
+    JOIN films f ON f.id = fa.film_id AND f.deleted_at IS NULL
+    JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL`
+
+func (s *graphStorage) GetPathLinks() ([]domain.CastLink, error) {
+	var links []domain.CastLink
+	err := s.db.Select(&links, getPathLinks)
+
+	return links, err
+}
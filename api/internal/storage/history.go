@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type historyStorage struct {
+	db *sqlx.DB
+}
+
+func NewHistoryStorage(conn *sqlx.DB) HistoryStorage {
+	return &historyStorage{
+		db: conn,
+	}
+}
+
+// selectViewings tells rewatches by an earlier viewing of the same film.
+const selectViewings = `SELECT v.id, v.film_id, f.title, v.watched_on, v.note,
+    EXISTS (
+        SELECT 1 FROM viewings p
+        WHERE p.user_id = v.user_id AND p.film_id = v.film_id
+          AND (p.watched_on, p.id) < (v.watched_on, v.id)) AS rewatch
+FROM viewings v
+    JOIN films f ON f.id = v.film_id AND f.deleted_at IS NULL`
+
+const getHistory = selectViewings + `
+WHERE v.user_id = $1 %s
+ORDER BY v.watched_on DESC, v.id DESC
+LIMIT $2`
+
+func (s *historyStorage) GetHistory(userId int64, limit int, after *domain.Cursor) ([]domain.Viewing, error) {
+	args := []any{userId, limit}
+	var page string
+	if after != nil {
+		page = "AND (v.watched_on, v.id) < ($3::date, $4)"
+		args = append(args, after.Value, after.ID)
+	}
+
+	var viewings []domain.Viewing
+	err := s.db.Select(&viewings, fmt.Sprintf(getHistory, page), args...)
+
+	return viewings, err
+}
+
+const countHistory = `SELECT COUNT(*) FROM viewings v
+    JOIN films f ON f.id = v.film_id AND f.deleted_at IS NULL
+WHERE v.user_id = $1`
+
+func (s *historyStorage) CountHistory(userId int64) (int64, error) {
+	var count int64
+	err := s.db.Get(&count, countHistory, userId)
+
+	return count, err
+}
+
+const saveViewing = `INSERT INTO viewings (user_id, film_id, watched_on, note)
+VALUES ($1, $2, $3, $4) RETURNING id`
+
+const removeWatched = `DELETE FROM watchlist WHERE user_id = $1 AND film_id = $2`
+
+const getViewing = selectViewings + `
+WHERE v.id = $1`
+
+// AddViewing saves the viewing and takes the film off the watchlist of the
+// user.
+func (s *historyStorage) AddViewing(userId int64, v domain.Viewing) (domain.Viewing, error) {
+	var viewing domain.Viewing
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return viewing, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err := tx.Get(&id, liveFilm, v.FilmID); err != nil {
+		return viewing, err
+	}
+	if err := tx.Get(&id, saveViewing, userId, v.FilmID, v.WatchedOn, v.Note); err != nil {
+		return viewing, err
+	}
+	if _, err := tx.Exec(removeWatched, userId, v.FilmID); err != nil {
+		return viewing, err
+	}
+	if err := tx.Get(&viewing, getViewing, id); err != nil {
+		return viewing, err
+	}
+
+	return viewing, tx.Commit()
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"kinoteka/internal/domain"
+)
+
+type imdbStorage struct {
+	db *sqlx.DB
+}
+
+func NewIMDbStorage(conn *sqlx.DB) IMDbStorage {
+	return &imdbStorage{
+		db: conn,
+	}
+}
+
+const getIMDbProgress = `SELECT COALESCE((SELECT line FROM imdb_progress WHERE file = $1), 0)`
+
+func (s *imdbStorage) GetIMDbProgress(file string) (int64, error) {
+	var line int64
+	err := s.db.Get(&line, getIMDbProgress, file)
+
+	return line, err
+}
+
+const resetIMDbProgress = `DELETE FROM imdb_progress`
+
+func (s *imdbStorage) ResetIMDbProgress() error {
+	_, err := s.db.Exec(resetIMDbProgress)
+
+	return err
+}
+
+const saveIMDbProgress = `INSERT INTO imdb_progress (file, line) VALUES ($1, $2)
+ON CONFLICT (file) DO UPDATE SET line = excluded.line, updated_at = now()`
+
+// saveIMDbBatch runs the query saving a batch and moves the progress of file
+// to line in one transaction, so a batch is either saved and never read
+// again or read again after a restart.
+func (s *imdbStorage) saveIMDbBatch(file string, line int64, query string, args ...any) (int64, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	saved, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(saveIMDbProgress, file, line); err != nil {
+		return 0, err
+	}
+
+	return saved, tx.Commit()
+}
+
+// saveIMDbFilms inserts the films which aren't in the database yet. A film
+// without an IMDb ID which has the title and the year of an imported one is
+// taken for it and gets its IMDb ID instead.
+const saveIMDbFilms = `WITH rows AS (
+    SELECT * FROM unnest($1::text[], $2::text[], $3::int[], $4::int[]) AS r(imdb_id, title, year, runtime_minutes)
+    WHERE NOT EXISTS (SELECT 1 FROM films f WHERE f.imdb_id = r.imdb_id)
+), matches AS (
+    SELECT DISTINCT ON (f.id) f.id, r.imdb_id
+    FROM rows r JOIN films f ON f.imdb_id IS NULL AND f.year = r.year AND LOWER(f.title) = LOWER(r.title)
+    ORDER BY f.id, r.imdb_id
+), linked AS (
+    UPDATE films f SET imdb_id = m.imdb_id
+    FROM (SELECT DISTINCT ON (imdb_id) id, imdb_id FROM matches ORDER BY imdb_id, id) m
+    WHERE f.id = m.id
+    RETURNING f.imdb_id
+)
+INSERT INTO films (imdb_id, title, year, runtime_minutes)
+SELECT r.imdb_id, r.title, r.year, NULLIF(r.runtime_minutes, 0) FROM rows r
+WHERE r.imdb_id NOT IN (SELECT imdb_id FROM linked)
+ON CONFLICT (imdb_id) DO NOTHING`
+
+func (s *imdbStorage) SaveIMDbFilms(file string, line int64, films []domain.IMDbFilm) (int64, error) {
+	ids := make([]string, len(films))
+	titles := make([]string, len(films))
+	years := make([]int64, len(films))
+	runtimes := make([]int64, len(films))
+	for i, f := range films {
+		ids[i], titles[i], years[i], runtimes[i] = f.IMDbID, f.Title, int64(f.Year), int64(f.RuntimeMinutes)
+	}
+
+	return s.saveIMDbBatch(file, line, saveIMDbFilms,
+		pq.Array(ids), pq.Array(titles), pq.Array(years), pq.Array(runtimes))
+}
+
+// saveIMDbActors inserts the actors which aren't in the database yet. An
+// actor without an IMDb ID who has the name and the birth year of an
+// imported one is taken for them and gets their IMDb ID instead.
+const saveIMDbActors = `WITH rows AS (
+    SELECT * FROM unnest($1::text[], $2::text[], $3::text[], $4::int[], $5::text[]) AS r(imdb_id, name, surname, birth_year, sex)
+    WHERE NOT EXISTS (SELECT 1 FROM actors a WHERE a.imdb_id = r.imdb_id)
+), matches AS (
+    SELECT DISTINCT ON (a.id) a.id, r.imdb_id
+    FROM rows r JOIN actors a ON a.imdb_id IS NULL AND EXTRACT(YEAR FROM a.birthday) = r.birth_year
+        AND LOWER(a.name) = LOWER(r.name) AND LOWER(a.surname) = LOWER(r.surname)
+    ORDER BY a.id, r.imdb_id
+), linked AS (
+    UPDATE actors a SET imdb_id = m.imdb_id
+    FROM (SELECT DISTINCT ON (imdb_id) id, imdb_id FROM matches ORDER BY imdb_id, id) m
+    WHERE a.id = m.id
+    RETURNING a.imdb_id
+)
+INSERT INTO actors (imdb_id, name, surname, birthday, sex)
+SELECT r.imdb_id, r.name, r.surname, make_date(r.birth_year, 1, 1), r.sex FROM rows r
+WHERE r.imdb_id NOT IN (SELECT imdb_id FROM linked)
+ON CONFLICT (imdb_id) DO NOTHING`
+
+func (s *imdbStorage) SaveIMDbActors(file string, line int64, actors []domain.IMDbActor) (int64, error) {
+	ids := make([]string, len(actors))
+	names := make([]string, len(actors))
+	surnames := make([]string, len(actors))
+	years := make([]int64, len(actors))
+	sexes := make([]string, len(actors))
+	for i, a := range actors {
+		ids[i], names[i], surnames[i], years[i], sexes[i] = a.IMDbID, a.Name, a.Surname, int64(a.BirthYear), a.Sex
+	}
+
+	return s.saveIMDbBatch(file, line, saveIMDbActors,
+		pq.Array(ids), pq.Array(names), pq.Array(surnames), pq.Array(years), pq.Array(sexes))
+}
+
+// saveIMDbCredits adds the parts of actors to the films they are in. Parts
+// of films or actors which weren't imported are left out.
+const saveIMDbCredits = `INSERT INTO films_actors (film_id, actor_id, character_name, billing_order, credit_type)
+SELECT f.id, a.id, NULLIF(r.character_name, ''), r.billing_order, r.credit_type
+FROM unnest($1::text[], $2::text[], $3::text[], $4::int[], $5::text[])
+    AS r(film_imdb_id, actor_imdb_id, character_name, billing_order, credit_type)
+JOIN films f ON f.imdb_id = r.film_imdb_id
+JOIN actors a ON a.imdb_id = r.actor_imdb_id
+ON CONFLICT (film_id, actor_id) DO NOTHING`
+
+func (s *imdbStorage) SaveIMDbCredits(file string, line int64, credits []domain.IMDbCredit) (int64, error) {
+	films := make([]string, len(credits))
+	actors := make([]string, len(credits))
+	characters := make([]string, len(credits))
+	billings := make([]int64, len(credits))
+	types := make([]string, len(credits))
+	for i, c := range credits {
+		films[i], actors[i], characters[i], billings[i], types[i] = c.FilmIMDbID, c.ActorIMDbID, c.Character, int64(c.Billing), c.Type
+	}
+
+	return s.saveIMDbBatch(file, line, saveIMDbCredits,
+		pq.Array(films), pq.Array(actors), pq.Array(characters), pq.Array(billings), pq.Array(types))
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"kinoteka/internal/domain"
+)
+
+type importStorage struct {
+	db *sqlx.DB
+}
+
+func NewImportStorage(conn *sqlx.DB) ImportStorage {
+	return &importStorage{
+		db: conn,
+	}
+}
+
+// ImportFilms saves the films with their audit entries as CreateFilm does.
+func (s *importStorage) ImportFilms(e domain.Editor, films []domain.Film, commit bool) ([]int64, []error, error) {
+	return s.importRows(len(films), commit, func(tx *sqlx.Tx, i int) (int64, error) {
+		film, err := createFilm(tx, films[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if err := addAudit(tx, e, domain.AuditCreate, domain.AuditFilm, film.ID, nil, film); err != nil {
+			return 0, err
+		}
+
+		return film.ID, nil
+	})
+}
+
+// ImportActors saves the actors with their audit entries as CreateActor
+// does.
+func (s *importStorage) ImportActors(e domain.Editor, actors []domain.Actor, commit bool) ([]int64, []error, error) {
+	return s.importRows(len(actors), commit, func(tx *sqlx.Tx, i int) (int64, error) {
+		actor, err := createActor(tx, actors[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if err := addAudit(tx, e, domain.AuditCreate, domain.AuditActor, actor.ID, nil, actor); err != nil {
+			return 0, err
+		}
+
+		return actor.ID, nil
+	})
+}
+
+const liveCredit = `SELECT EXISTS (SELECT 1 FROM films WHERE id = $1 AND deleted_at IS NULL)
+    AND EXISTS (SELECT 1 FROM actors WHERE id = $2 AND deleted_at IS NULL)`
+
+// ImportCredits adds the credits to the casts of the films, each with the
+// audit entry of the change of the cast.
+func (s *importStorage) ImportCredits(e domain.Editor, credits []domain.ImportCredit, commit bool) ([]int64, []error, error) {
+	return s.importRows(len(credits), commit, func(tx *sqlx.Tx, i int) (int64, error) {
+		c := credits[i]
+		var live bool
+		if err := tx.Get(&live, liveCredit, c.FilmID, c.ActorID); err != nil {
+			return 0, err
+		}
+		if !live {
+			return 0, errors.New("film or actor doesn't exist")
+		}
+		before, err := getCastState(tx, c.FilmID)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(addActorToFilm, c.FilmID, c.ActorID, c.Character, c.Billing, c.Type); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(touchFilm, c.FilmID); err != nil {
+			return 0, err
+		}
+
+		return 0, auditCast(tx, e, domain.AuditCastAdd, c.FilmID, before)
+	})
+}
+
+const (
+	saveImportRow     = `SAVEPOINT import_row`
+	releaseImportRow  = `RELEASE SAVEPOINT import_row`
+	rollbackImportRow = `ROLLBACK TO SAVEPOINT import_row`
+)
+
+// importRows inserts n rows in one transaction. Every row is inserted under
+// a savepoint, so a failed row doesn't hide the errors of the rows after it.
+// The transaction is committed only if commit is set and no row failed.
+func (s *importStorage) importRows(n int, commit bool, insert func(tx *sqlx.Tx, i int) (int64, error)) ([]int64, []error, error) {
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, n)
+	errs := make([]error, n)
+	failed := false
+	for i := 0; i < n; i++ {
+		if _, err := tx.Exec(saveImportRow); err != nil {
+			return nil, nil, err
+		}
+
+		ids[i], errs[i] = insert(tx, i)
+		if errs[i] != nil {
+			errs[i] = importError(errs[i])
+			failed = true
+			if _, err := tx.Exec(rollbackImportRow); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		if _, err := tx.Exec(releaseImportRow); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if commit && !failed {
+		if err := tx.Commit(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return ids, errs, nil
+}
+
+// importError tells what's wrong with a row in words an editor understands.
+func importError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "foreign_key_violation":
+		return errors.New("film or actor doesn't exist")
+	case "unique_violation":
+		return errors.New("already present")
+	case "check_violation":
+		return errors.New("value is out of range")
+	case "string_data_right_truncation":
+		return errors.New("value is too long")
+	default:
+		return errors.New(pqErr.Message)
+	}
+}
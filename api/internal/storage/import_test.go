@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func TestImportStorage_ImportFilms(t *testing.T) {
+	films := []domain.Film{
+		{Title: "Брат", Year: 1997, Countries: pq.StringArray{"RU"}},
+		{Title: "Брат 2", Year: 2000},
+	}
+
+	tests := []struct {
+		name         string
+		commit       bool
+		secondErr    error
+		auditErr     error
+		expectedIDs  []int64
+		expectedErrs []error
+	}{
+		{
+			name:         "Ok",
+			commit:       true,
+			expectedIDs:  []int64{7, 8},
+			expectedErrs: []error{nil, nil},
+		},
+		{
+			name:         "Dry run",
+			expectedIDs:  []int64{7, 8},
+			expectedErrs: []error{nil, nil},
+		},
+		{
+			name:         "Wrong row",
+			commit:       true,
+			secondErr:    &pq.Error{Code: "23514"},
+			expectedIDs:  []int64{7, 0},
+			expectedErrs: []error{nil, errors.New("value is out of range")},
+		},
+		{
+			name:         "Audit fails",
+			commit:       true,
+			auditErr:     errors.New("audit_log is full"),
+			expectedIDs:  []int64{7, 0},
+			expectedErrs: []error{nil, errors.New("audit_log is full")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery(`INSERT INTO films .*`+countriesParam).
+				WithArgs(films[0].Title, films[0].Year, films[0].Information, films[0].EditorialRating,
+					films[0].RuntimeMinutes, films[0].ReleaseDate, `{"RU"}`, films[0].OriginalLanguage).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+			mock.ExpectExec(`INSERT INTO audit_log`).
+				WithArgs(editor.UserID, editor.RequestID, domain.AuditCreate, domain.AuditFilm, int64(7), sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+			second := mock.ExpectQuery(`INSERT INTO films .*`+countriesParam).
+				WithArgs(films[1].Title, films[1].Year, films[1].Information, films[1].EditorialRating,
+					films[1].RuntimeMinutes, films[1].ReleaseDate, nil, films[1].OriginalLanguage)
+			if test.secondErr != nil {
+				second.WillReturnError(test.secondErr)
+				mock.ExpectExec(`ROLLBACK TO SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+			} else {
+				second.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+				audit := mock.ExpectExec(`INSERT INTO audit_log`).
+					WithArgs(editor.UserID, editor.RequestID, domain.AuditCreate, domain.AuditFilm, int64(8), sqlmock.AnyArg())
+				if test.auditErr != nil {
+					audit.WillReturnError(test.auditErr)
+					mock.ExpectExec(`ROLLBACK TO SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+				} else {
+					audit.WillReturnResult(sqlmock.NewResult(1, 1))
+					mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+				}
+			}
+			if test.commit && test.secondErr == nil && test.auditErr == nil {
+				mock.ExpectCommit()
+			} else {
+				mock.ExpectRollback()
+			}
+
+			ids, errs, err := NewImportStorage(db).ImportFilms(editor, films, test.commit)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedIDs, ids)
+			assert.Equal(t, test.expectedErrs, errs)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestImportStorage_ImportCredits(t *testing.T) {
+	credits := []domain.ImportCredit{
+		{FilmID: 3, Credit: domain.Credit{ActorID: 5, Part: domain.Part{Character: "Данила", Billing: 1, Type: "actor"}}},
+	}
+	castColumns := []string{"id", "name", "surname", "character_name", "billing_order", "credit_type"}
+
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(int64(3), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`FROM films_actors fa`).WithArgs(int64(3)).WillReturnRows(sqlmock.NewRows(castColumns))
+	mock.ExpectExec(`INSERT INTO films_actors`).WithArgs(int64(3), int64(5), "Данила", 1, "actor").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE films SET updated_at = now\(\) WHERE id = \$1`).WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM films_actors fa`).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(castColumns).AddRow(5, "Сергей", "Бодров", "Данила", 1, "actor"))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditCastAdd, domain.AuditFilm, int64(3), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT import_row`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	ids, errs, err := NewImportStorage(db).ImportCredits(editor, credits, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{0}, ids)
+	assert.Equal(t, []error{nil}, errs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
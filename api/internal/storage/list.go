@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type listStorage struct {
+	db *sqlx.DB
+}
+
+func NewListStorage(conn *sqlx.DB) ListStorage {
+	return &listStorage{
+		db: conn,
+	}
+}
+
+// listTables maps the personal lists to the tables they are kept in.
+var listTables = map[string]string{
+	domain.ListWatchlist: "watchlist",
+	domain.ListFavorites: "favorites",
+}
+
+// listSortKeys maps the columns list entries can be ordered by to the
+// expression used for ordering.
+var listSortKeys = map[string]string{
+	"addedAt": "l.added_at",
+	"title":   "f.title",
+	"year":    "f.year",
+	"rating":  "COALESCE(f.rating, -1)",
+}
+
+func listTable(list string) (string, error) {
+	table, ok := listTables[list]
+	if !ok {
+		return "", fmt.Errorf("there is no list %q", list)
+	}
+
+	return table, nil
+}
+
+const getList = `SELECT f.id, f.title, f.year, f.information, f.rating, f.votes, f.editorial_rating,
+    f.runtime_minutes, f.release_date, f.countries, f.original_language, f.poster_key,
+    l.added_at
+FROM %s l
+    JOIN films f ON f.id = l.film_id AND f.deleted_at IS NULL
+WHERE l.user_id = $1
+ORDER BY %s %s, f.id %[3]s`
+
+func (s *listStorage) GetList(userId int64, list, orderBy string, desc bool) ([]domain.ListEntry, error) {
+	table, err := listTable(list)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := listSortKeys[orderBy]
+	if !ok {
+		return nil, fmt.Errorf("can't order list by %q", orderBy)
+	}
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	var entries []domain.ListEntry
+	err = s.db.Select(&entries, fmt.Sprintf(getList, table, key, direction), userId)
+
+	return entries, err
+}
+
+// addToList keeps the time a film was first added at when it is added
+// again.
+const addToList = `INSERT INTO %s (user_id, film_id) VALUES ($1, $2)
+ON CONFLICT (user_id, film_id) DO NOTHING`
+
+func (s *listStorage) AddToList(userId, filmId int64, list string) error {
+	table, err := listTable(list)
+	if err != nil {
+		return err
+	}
+
+	var id int64
+	if err := s.db.Get(&id, liveFilm, filmId); err != nil {
+		return err
+	}
+	_, err = s.db.Exec(fmt.Sprintf(addToList, table), userId, filmId)
+
+	return err
+}
+
+const removeFromList = `DELETE FROM %s WHERE user_id = $1 AND film_id = $2`
+
+func (s *listStorage) RemoveFromList(userId, filmId int64, list string) error {
+	table, err := listTable(list)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(fmt.Sprintf(removeFromList, table), userId, filmId)
+
+	return err
+}
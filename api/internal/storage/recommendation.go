@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type recommendationStorage struct {
+	db *sqlx.DB
+}
+
+func NewRecommendationStorage(conn *sqlx.DB) RecommendationStorage {
+	return &recommendationStorage{
+		db: conn,
+	}
+}
+
+const getFilmSummaries = `SELECT id, title, year FROM films WHERE deleted_at IS NULL`
+
+func (s *recommendationStorage) GetFilmSummaries() ([]domain.FilmSummary, error) {
+	var films []domain.FilmSummary
+	err := s.db.Select(&films, getFilmSummaries)
+
+	return films, err
+}
+
+const getFilmSummary = `SELECT id, title, year FROM films WHERE id = $1 AND deleted_at IS NULL`
+
+func (s *recommendationStorage) GetFilmSummary(id int64) (domain.FilmSummary, error) {
+	var film domain.FilmSummary
+	err := s.db.Get(&film, getFilmSummary, id)
+
+	return film, err
+}
+
+// liveCastLinks are the links between films and actors which aren't in the
+// trash.
+const liveCastLinks = `SELECT fa.film_id, fa.actor_id FROM films_actors fa
+    JOIN films f ON f.id = fa.film_id AND f.deleted_at IS NULL
+    JOIN actors a ON a.id = fa.actor_id AND a.deleted_at IS NULL`
+
+const getCastLinks = liveCastLinks
+
+func (s *recommendationStorage) GetCastLinks() ([]domain.CastLink, error) {
+	var links []domain.CastLink
+	err := s.db.Select(&links, getCastLinks)
+
+	return links, err
+}
+
+const getFilmCastLinks = liveCastLinks + ` WHERE fa.film_id = $1`
+
+func (s *recommendationStorage) GetFilmCastLinks(filmId int64) ([]domain.CastLink, error) {
+	var links []domain.CastLink
+	err := s.db.Select(&links, getFilmCastLinks, filmId)
+
+	return links, err
+}
+
+const getVotes = `SELECT r.user_id, r.film_id, r.rating FROM ratings r
+    JOIN films f ON f.id = r.film_id AND f.deleted_at IS NULL`
+
+func (s *recommendationStorage) GetVotes() ([]domain.UserVote, error) {
+	var votes []domain.UserVote
+	err := s.db.Select(&votes, getVotes)
+
+	return votes, err
+}
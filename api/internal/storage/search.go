@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"kinoteka/internal/domain"
+)
+
+type searchStorage struct {
+	db *sqlx.DB
+}
+
+func NewSearchStorage(conn *sqlx.DB) SearchStorage {
+	return &searchStorage{
+		db: conn,
+	}
+}
+
+const search = `WITH q AS (SELECT websearch_to_tsquery('russian', $1) AS query)
+SELECT
+    'film' AS type,
+    f.id,
+    f.title,
+    ts_headline('russian', concat_ws(' — ', f.title, f.information), q.query) AS headline,
+    ts_rank(f.search, q.query) AS rank
+FROM films f, q
+WHERE f.search @@ q.query AND f.deleted_at IS NULL
+UNION ALL
+SELECT
+    'actor' AS type,
+    a.id,
+    concat_ws(' ', a.name, NULLIF(a.patronymic, ''), a.surname) AS title,
+    ts_headline('russian', concat_ws(' — ', concat_ws(' ', a.name, NULLIF(a.patronymic, ''), a.surname), a.information), q.query) AS headline,
+    ts_rank(a.search, q.query) AS rank
+FROM actors a, q
+WHERE a.search @@ q.query AND a.deleted_at IS NULL
+ORDER BY rank DESC, type, id
+LIMIT $2`
+
+func (s *searchStorage) Search(query string, limit int) ([]domain.SearchHit, error) {
+	var hits []domain.SearchHit
+	err := s.db.Select(&hits, search, query, limit)
+
+	return hits, err
+}
@@ -1,31 +1,56 @@
 package storage
 
 import (
+	"database/sql"
 	"github.com/jmoiron/sqlx"
+	"io"
 	"kinoteka/internal/domain"
+	"time"
 )
 
+// FilmStorage keeps the films. Changes of films and their casts are saved
+// with their audit entries by the editor e, in one transaction.
 type FilmStorage interface {
-	GetFilmsLike(title string) ([]domain.Film, error)
-	GetFilmsSort(orderBy string, desc bool) ([]domain.Film, error)
-	GetFilmsSortLike(orderBy, title string, desc bool) ([]domain.Film, error)
-	GetFilm(id int64) (domain.Film, error)
-	CreateFilm(a domain.Film) error
-	UpdateFilm(a domain.Film) error
-	DeleteFilm(id int64) error
-	SearchFilmsWithActor(substr string) ([]domain.ActorFilm, error)
-	DeleteFilmsActors(id int64) error
-	AddActorToFilm(filmId int64, actorId []int64) error
+	GetFilms(q domain.FilmQuery, limit int, after *domain.Cursor) ([]domain.Film, error)
+	CountFilms(q domain.FilmQuery) (int64, error)
+	FilmsUpdatedAt() (time.Time, error)
+	GetFilm(id, userId int64) (domain.Film, error)
+	CreateFilm(e domain.Editor, a domain.Film) (domain.Film, error)
+	UpdateFilm(e domain.Editor, a domain.Film, versions domain.Versions) error
+	PatchFilm(e domain.Editor, id int64, versions domain.Versions, apply func(domain.Film) (domain.Film, error)) error
+	GetFilmRevisions(filmId int64) ([]domain.FilmRevision, error)
+	RevertFilm(e domain.Editor, filmId, rev int64) error
+	SetFilmPoster(e domain.Editor, id int64, key string) (sql.NullString, error)
+	RateFilm(filmId, userId int64, rating int) (domain.FilmRating, error)
+	DeleteFilm(e domain.Editor, id int64, versions domain.Versions) error
+	RestoreFilm(e domain.Editor, id int64) error
+	AddActorToFilm(e domain.Editor, filmId int64, credits []domain.Credit) error
+	ReplaceFilmCast(e domain.Editor, filmId int64, credits []domain.Credit) error
+	RemoveActorFromFilm(e domain.Editor, filmId, actorId int64) error
+	GetFilmCast(id int64) ([]domain.CastMember, error)
+	GetFilmActors(filmId int64, q domain.CreditQuery, limit int, after *domain.Cursor) ([]domain.CastMember, error)
+	CountFilmActors(filmId int64) (int64, error)
+	GetFilmGenres(id int64) ([]domain.Genre, error)
+	AddGenreToFilm(e domain.Editor, filmId int64, genreId []int64) error
 }
 
+// ActorStorage keeps the actors. Their changes are saved with their audit
+// entries, as those of films are.
 type ActorStorage interface {
-	GetActors() ([]domain.Actor, error)
-	CreateActor(a domain.Actor) error
+	GetActorsPage(limit int, afterID int64) ([]domain.Actor, error)
+	CountActors() (int64, error)
+	ActorsUpdatedAt() (time.Time, error)
+	GetActorFilms(actorId int64, q domain.CreditQuery, limit int, after *domain.Cursor) ([]domain.FilmCredit, error)
+	CountActorFilms(actorId int64) (int64, error)
+	CreateActor(e domain.Editor, a domain.Actor) (domain.Actor, error)
 	GetActor(id int64) (domain.Actor, error)
-	UpdateActor(a domain.Actor) error
-	DeleteActor(id int64) error
+	UpdateActor(e domain.Editor, a domain.Actor, versions domain.Versions) error
+	PatchActor(e domain.Editor, id int64, versions domain.Versions, apply func(domain.Actor) (domain.Actor, error)) error
+	GetActorRevisions(actorId int64) ([]domain.ActorRevision, error)
+	SetActorPhoto(e domain.Editor, id int64, key string) (sql.NullString, error)
+	DeleteActor(e domain.Editor, id int64, versions domain.Versions) error
+	RestoreActor(e domain.Editor, id int64) error
 	GetActorsWithFilms() ([]domain.ActorFilm, error)
-	DeleteActorsFilms(id int64) error
 }
 
 type UserStorage interface {
@@ -34,16 +59,130 @@ type UserStorage interface {
 	GetRole(userId int64) ([]domain.Role, error)
 }
 
+type GenreStorage interface {
+	GetGenres() ([]domain.Genre, error)
+	GetGenre(id int64) (domain.Genre, error)
+	CreateGenre(g domain.Genre) error
+	UpdateGenre(g domain.Genre) error
+	DeleteGenre(id int64) error
+}
+
+type SearchStorage interface {
+	Search(query string, limit int) ([]domain.SearchHit, error)
+}
+
+type ListStorage interface {
+	GetList(userId int64, list, orderBy string, desc bool) ([]domain.ListEntry, error)
+	AddToList(userId, filmId int64, list string) error
+	RemoveFromList(userId, filmId int64, list string) error
+}
+
+type HistoryStorage interface {
+	GetHistory(userId int64, limit int, after *domain.Cursor) ([]domain.Viewing, error)
+	CountHistory(userId int64) (int64, error)
+	AddViewing(userId int64, v domain.Viewing) (domain.Viewing, error)
+}
+
+// ImportStorage saves imported rows with their audit entries in one
+// transaction. It returns for each row the ID it got and the error it failed
+// with.
+type ImportStorage interface {
+	ImportFilms(e domain.Editor, films []domain.Film, commit bool) ([]int64, []error, error)
+	ImportActors(e domain.Editor, actors []domain.Actor, commit bool) ([]int64, []error, error)
+	ImportCredits(e domain.Editor, credits []domain.ImportCredit, commit bool) ([]int64, []error, error)
+}
+
+// IMDbStorage saves batches of rows read from the IMDb datasets together with
+// how far each file was read.
+type IMDbStorage interface {
+	GetIMDbProgress(file string) (int64, error)
+	ResetIMDbProgress() error
+	SaveIMDbFilms(file string, line int64, films []domain.IMDbFilm) (int64, error)
+	SaveIMDbActors(file string, line int64, actors []domain.IMDbActor) (int64, error)
+	SaveIMDbCredits(file string, line int64, credits []domain.IMDbCredit) (int64, error)
+}
+
+// ExportStorage streams the catalog row by row.
+type ExportStorage interface {
+	ExportFilms(q domain.FilmQuery, withCredits bool, fn func(domain.ExportFilm) error) error
+	ExportActors(withCredits bool, fn func(domain.ExportActor) error) error
+}
+
+// TrashStorage lists and purges the films and actors which were deleted.
+// Purging saves an audit entry for each of them and returns the keys of
+// their images.
+type TrashStorage interface {
+	GetTrash() ([]domain.TrashItem, error)
+	PurgeTrash(e domain.Editor, deletedBefore time.Time) (domain.PurgeReport, []sql.NullString, error)
+}
+
+// AuditStorage keeps the audit log of the changes of films and actors.
+// Entries are added by the storages which make the changes, in the same
+// transactions. The latest are listed first.
+type AuditStorage interface {
+	GetAudit(q domain.AuditQuery, limit int, afterID int64) ([]domain.AuditEntry, error)
+	CountAudit(q domain.AuditQuery) (int64, error)
+}
+
+// GraphStorage reads the actors and films the collaboration graph of actors
+// is made of, and the credits linking them.
+type GraphStorage interface {
+	GetPathActors() ([]domain.PathActor, error)
+	GetPathFilms() ([]domain.PathFilm, error)
+	GetPathLinks() ([]domain.CastLink, error)
+}
+
+type RecommendationStorage interface {
+	GetFilmSummaries() ([]domain.FilmSummary, error)
+	GetFilmSummary(id int64) (domain.FilmSummary, error)
+	GetCastLinks() ([]domain.CastLink, error)
+	GetFilmCastLinks(filmId int64) ([]domain.CastLink, error)
+	GetVotes() ([]domain.UserVote, error)
+}
+
+// BlobStore keeps uploaded files, such as posters and photos, under
+// slash-separated keys.
+type BlobStore interface {
+	Put(key string, r io.Reader, contentType string) error
+	Delete(key string) error
+	// URL returns the address the blob under key is served from.
+	URL(key string) string
+}
+
 type Storage struct {
 	FilmStorage
 	ActorStorage
 	UserStorage
+	GenreStorage
+	SearchStorage
+	ListStorage
+	HistoryStorage
+	RecommendationStorage
+	GraphStorage
+	ImportStorage
+	ExportStorage
+	IMDbStorage
+	TrashStorage
+	AuditStorage
+	BlobStore
 }
 
-func NewStorage(db *sqlx.DB) *Storage {
+func NewStorage(db *sqlx.DB, blobs BlobStore) *Storage {
 	return &Storage{
-		FilmStorage:  NewFilmStorage(db),
-		ActorStorage: NewActorStorage(db),
-		UserStorage:  NewUserStorage(db),
+		FilmStorage:           NewFilmStorage(db),
+		ActorStorage:          NewActorStorage(db),
+		UserStorage:           NewUserStorage(db),
+		GenreStorage:          NewGenreStorage(db),
+		SearchStorage:         NewSearchStorage(db),
+		ListStorage:           NewListStorage(db),
+		HistoryStorage:        NewHistoryStorage(db),
+		RecommendationStorage: NewRecommendationStorage(db),
+		GraphStorage:          NewGraphStorage(db),
+		ImportStorage:         NewImportStorage(db),
+		ExportStorage:         NewExportStorage(db),
+		IMDbStorage:           NewIMDbStorage(db),
+		TrashStorage:          NewTrashStorage(db),
+		AuditStorage:          NewAuditStorage(db),
+		BlobStore:             blobs,
 	}
 }
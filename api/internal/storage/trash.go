@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"kinoteka/internal/domain"
+	"time"
+)
+
+type trashStorage struct {
+	db *sqlx.DB
+}
+
+func NewTrashStorage(conn *sqlx.DB) TrashStorage {
+	return &trashStorage{
+		db: conn,
+	}
+}
+
+const getTrash = `SELECT 'film' AS type, id, title, deleted_at
+FROM films WHERE deleted_at IS NOT NULL
+UNION ALL
+SELECT 'actor' AS type, id, concat_ws(' ', name, NULLIF(patronymic, ''), surname) AS title, deleted_at
+FROM actors WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, type, id`
+
+func (s *trashStorage) GetTrash() ([]domain.TrashItem, error) {
+	var items []domain.TrashItem
+	err := s.db.Select(&items, getTrash)
+
+	return items, err
+}
+
+const getPurgedFilms = `SELECT ` + filmColumns + ` FROM films
+WHERE deleted_at < $1 FOR UPDATE`
+
+const getPurgedActors = `SELECT ` + actorColumns + ` FROM actors
+WHERE deleted_at < $1 FOR UPDATE`
+
+// purgeFilms deletes the films with everything that refers to them.
+const purgeFilms = `WITH
+    credits AS (DELETE FROM films_actors WHERE film_id = ANY($1)),
+    genres AS (DELETE FROM films_genres WHERE film_id = ANY($1)),
+    votes AS (DELETE FROM ratings WHERE film_id = ANY($1)),
+    watchlist AS (DELETE FROM watchlist WHERE film_id = ANY($1)),
+    favorites AS (DELETE FROM favorites WHERE film_id = ANY($1)),
+    viewings AS (DELETE FROM viewings WHERE film_id = ANY($1)),
+    revisions AS (DELETE FROM film_revisions WHERE film_id = ANY($1))
+DELETE FROM films WHERE id = ANY($1)`
+
+// purgeActors deletes the actors with their credits and revisions.
+const purgeActors = `WITH
+    credits AS (DELETE FROM films_actors WHERE actor_id = ANY($1)),
+    revisions AS (DELETE FROM actor_revisions WHERE actor_id = ANY($1))
+DELETE FROM actors WHERE id = ANY($1)`
+
+// PurgeTrash deletes for good the films and actors which were deleted before
+// the time and saves their audit entries, in one transaction.
+func (s *trashStorage) PurgeTrash(e domain.Editor, deletedBefore time.Time) (domain.PurgeReport, []sql.NullString, error) {
+	report := domain.PurgeReport{DeletedBefore: deletedBefore}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return report, nil, err
+	}
+	defer tx.Rollback()
+
+	var films []domain.Film
+	var actors []domain.Actor
+	if err := tx.Select(&films, getPurgedFilms, deletedBefore); err != nil {
+		return report, nil, err
+	}
+	if err := tx.Select(&actors, getPurgedActors, deletedBefore); err != nil {
+		return report, nil, err
+	}
+
+	var keys []sql.NullString
+	filmIds := make([]int64, 0, len(films))
+	for _, film := range films {
+		filmIds = append(filmIds, film.ID)
+		keys = append(keys, film.PosterKey)
+		if err := addAudit(tx, e, domain.AuditPurge, domain.AuditFilm, film.ID, film, nil); err != nil {
+			return report, nil, err
+		}
+	}
+	actorIds := make([]int64, 0, len(actors))
+	for _, actor := range actors {
+		actorIds = append(actorIds, actor.ID)
+		keys = append(keys, actor.PhotoKey)
+		if err := addAudit(tx, e, domain.AuditPurge, domain.AuditActor, actor.ID, actor, nil); err != nil {
+			return report, nil, err
+		}
+	}
+
+	if _, err := tx.Exec(purgeFilms, pq.Array(filmIds)); err != nil {
+		return report, nil, err
+	}
+	if _, err := tx.Exec(purgeActors, pq.Array(actorIds)); err != nil {
+		return report, nil, err
+	}
+	report.Films, report.Actors = int64(len(films)), int64(len(actors))
+
+	return report, keys, tx.Commit()
+}
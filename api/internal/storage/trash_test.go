@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"kinoteka/internal/domain"
+)
+
+func TestTrashStorage_PurgeTrash(t *testing.T) {
+	deletedBefore := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, title, .* FROM films\s+WHERE deleted_at < \$1 FOR UPDATE`).WithArgs(deletedBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "year", "poster_key"}).AddRow(3, "Брат", 1997, "films/3/poster.jpg"))
+	mock.ExpectQuery(`SELECT id, name, .* FROM actors\s+WHERE deleted_at < \$1 FOR UPDATE`).WithArgs(deletedBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "surname", "photo_key"}).AddRow(5, "Сергей", "Бодров", nil))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditPurge, domain.AuditFilm, int64(3), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO audit_log`).
+		WithArgs(editor.UserID, editor.RequestID, domain.AuditPurge, domain.AuditActor, int64(5), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectExec(`DELETE FROM films WHERE id = ANY`).WithArgs(`{3}`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM actors WHERE id = ANY`).WithArgs(`{5}`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	report, keys, err := NewTrashStorage(db).PurgeTrash(editor, deletedBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PurgeReport{DeletedBefore: deletedBefore, Films: 1, Actors: 1}, report)
+	assert.Equal(t, []sql.NullString{{String: "films/3/poster.jpg", Valid: true}, {}}, keys)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,27 @@
+-- Adds the audit log of the changes of films and actors to databases created
+-- before it. Entries can only be added, never changed or removed.
+
+CREATE TABLE IF NOT EXISTS audit_log(
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    request_id varchar(128) NOT NULL DEFAULT '',
+    action varchar(16) NOT NULL,
+    entity_type varchar(16) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_user_id_idx ON audit_log (user_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
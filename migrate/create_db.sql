@@ -1,3 +1,13 @@
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS imdb_progress;
+DROP TABLE IF EXISTS film_revisions;
+DROP TABLE IF EXISTS actor_revisions;
+DROP TABLE IF EXISTS viewings;
+DROP TABLE IF EXISTS favorites;
+DROP TABLE IF EXISTS watchlist;
+DROP TABLE IF EXISTS ratings;
+DROP TABLE IF EXISTS films_genres;
+DROP TABLE IF EXISTS genres;
 DROP TABLE IF EXISTS users_roles;
 DROP TABLE IF EXISTS roles;
 DROP TABLE IF EXISTS users;
@@ -5,6 +15,8 @@ DROP TABLE IF EXISTS films_actors;
 DROP TABLE IF EXISTS actors;
 DROP TABLE IF EXISTS films;
 
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 CREATE TABLE users(
     id SERIAL PRIMARY KEY,
     login varchar(256) not null,
@@ -30,25 +42,192 @@ CREATE TABLE actors(
     patronymic varchar(256),
     birthday DATE not null,
     sex CHAR(1) not null,
-    information varchar(2048)
+    information varchar(2048),
+    photo_key varchar(512),
+    imdb_id varchar(16) UNIQUE,
+    deleted_at TIMESTAMPTZ,
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    search tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', name || ' ' || surname || ' ' || coalesce(patronymic, '')), 'A') ||
+        setweight(to_tsvector('russian', coalesce(information, '')), 'B')
+    ) STORED
 );
 
+CREATE INDEX actors_search_idx ON actors USING GIN (search);
+CREATE INDEX actors_full_name_trgm_idx ON actors USING GIN ((name || ' ' || surname) gin_trgm_ops);
+CREATE INDEX actors_deleted_at_idx ON actors (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX actors_updated_at_idx ON actors (updated_at);
+
 CREATE TABLE films(
     id SERIAL PRIMARY KEY,
     title varchar(150) not null,
     year INT not null,
     information varchar(1000),
-    rating DECIMAL(3,1) CHECK (rating BETWEEN 0 AND 10)
+    rating DECIMAL(3,1) CHECK (rating BETWEEN 0 AND 10),
+    votes INTEGER NOT NULL DEFAULT 0,
+    editorial_rating DECIMAL(3,1) CHECK (editorial_rating BETWEEN 0 AND 10),
+    runtime_minutes INTEGER CHECK (runtime_minutes > 0),
+    release_date DATE,
+    countries varchar(2)[] not null DEFAULT '{}',
+    original_language varchar(3),
+    poster_key varchar(512),
+    imdb_id varchar(16) UNIQUE,
+    deleted_at TIMESTAMPTZ,
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    search tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', title), 'A') ||
+        setweight(to_tsvector('russian', coalesce(information, '')), 'B')
+    ) STORED
 );
 
+CREATE INDEX films_search_idx ON films USING GIN (search);
+CREATE INDEX films_title_trgm_idx ON films USING GIN (title gin_trgm_ops);
+
+CREATE INDEX films_rating_id_idx ON films ((COALESCE(rating, -1)), id);
+CREATE INDEX films_title_id_idx ON films (title, id);
+CREATE INDEX films_year_id_idx ON films (year, id);
+CREATE INDEX films_runtime_id_idx ON films ((COALESCE(runtime_minutes, -1)), id);
+CREATE INDEX films_release_date_id_idx ON films ((COALESCE(release_date, '-infinity')), id);
+CREATE INDEX films_countries_idx ON films USING GIN (countries);
+CREATE INDEX films_deleted_at_idx ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX films_updated_at_idx ON films (updated_at);
+
 
 CREATE TABLE films_actors(
     film_id INTEGER NOT NULL REFERENCES films(id),
     actor_id INTEGER NOT NULL REFERENCES actors(id),
+    character_name varchar(256),
+    billing_order INTEGER CHECK (billing_order > 0),
+    credit_type varchar(16) NOT NULL DEFAULT 'supporting'
+        CHECK (credit_type IN ('lead', 'supporting', 'cameo', 'voice')),
     PRIMARY KEY(film_id, actor_id)
 );
 
+CREATE INDEX films_actors_actor_id_idx ON films_actors (actor_id);
+
+CREATE TABLE film_revisions(
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    rev INTEGER NOT NULL,
+    title varchar(150) not null,
+    year INT not null,
+    information varchar(1000),
+    editorial_rating DECIMAL(3,1),
+    runtime_minutes INTEGER,
+    release_date DATE,
+    countries varchar(2)[] not null DEFAULT '{}',
+    original_language varchar(3),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(film_id, rev)
+);
+
+CREATE TABLE actor_revisions(
+    actor_id INTEGER NOT NULL REFERENCES actors(id),
+    rev INTEGER NOT NULL,
+    name varchar(256) not null,
+    surname varchar(256) not null,
+    patronymic varchar(256),
+    birthday DATE not null,
+    sex CHAR(1) not null,
+    information varchar(2048),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(actor_id, rev)
+);
+
+CREATE TABLE genres(
+    id SERIAL PRIMARY KEY,
+    name varchar(64) not null UNIQUE
+);
+
+CREATE TABLE films_genres(
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    genre_id INTEGER NOT NULL REFERENCES genres(id),
+    PRIMARY KEY(film_id, genre_id)
+);
+
+CREATE INDEX films_genres_genre_id_idx ON films_genres (genre_id);
+
+CREATE TABLE ratings(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 0 AND 10),
+    rated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX ratings_film_id_idx ON ratings (film_id);
+
+CREATE TABLE watchlist(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX watchlist_film_id_idx ON watchlist (film_id);
+
+CREATE TABLE favorites(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX favorites_film_id_idx ON favorites (film_id);
+
+CREATE TABLE viewings(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    watched_on DATE NOT NULL,
+    note varchar(1000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX viewings_user_id_watched_on_idx ON viewings (user_id, watched_on, id);
+CREATE INDEX viewings_user_id_film_id_idx ON viewings (user_id, film_id, watched_on, id);
+CREATE INDEX viewings_film_id_idx ON viewings (film_id);
+
+CREATE TABLE imdb_progress(
+    file varchar(64) PRIMARY KEY,
+    line BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE audit_log(
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    request_id varchar(128) NOT NULL DEFAULT '',
+    action varchar(16) NOT NULL,
+    entity_type varchar(16) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    diff JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity_type, entity_id, id);
+CREATE INDEX audit_log_user_id_idx ON audit_log (user_id, id);
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log is append-only';
+END;
+$$ LANGUAGE plpgsql;
 
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
 
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
 
+CREATE TRIGGER films_touch_updated_at BEFORE UPDATE ON films
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
 
+CREATE TRIGGER actors_touch_updated_at BEFORE UPDATE ON actors
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
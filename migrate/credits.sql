@@ -0,0 +1,9 @@
+-- Adds the character, billing order and credit type of a role to databases
+-- created before credits had them. Existing roles become supporting ones.
+
+ALTER TABLE films_actors ADD COLUMN IF NOT EXISTS character_name varchar(256);
+ALTER TABLE films_actors ADD COLUMN IF NOT EXISTS billing_order INTEGER CHECK (billing_order > 0);
+ALTER TABLE films_actors ADD COLUMN IF NOT EXISTS credit_type varchar(16) NOT NULL DEFAULT 'supporting'
+    CHECK (credit_type IN ('lead', 'supporting', 'cameo', 'voice'));
+
+CREATE INDEX IF NOT EXISTS films_actors_actor_id_idx ON films_actors (actor_id);
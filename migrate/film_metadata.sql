@@ -0,0 +1,15 @@
+-- Adds the structured film metadata to databases created before it existed
+-- and moves the runtime, which used to be written into information as HH:MM,
+-- into runtime_minutes.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS runtime_minutes INTEGER CHECK (runtime_minutes > 0);
+ALTER TABLE films ADD COLUMN IF NOT EXISTS release_date DATE;
+ALTER TABLE films ADD COLUMN IF NOT EXISTS countries varchar(2)[] not null DEFAULT '{}';
+ALTER TABLE films ADD COLUMN IF NOT EXISTS original_language varchar(3);
+
+UPDATE films
+SET runtime_minutes = split_part(trim(information), ':', 1)::int * 60 + split_part(trim(information), ':', 2)::int,
+    information = NULL
+WHERE runtime_minutes IS NULL
+  AND trim(information) ~ '^[0-9]{1,2}:[0-5][0-9]$'
+  AND trim(information) !~ '^0+:0+$';
@@ -0,0 +1,7 @@
+-- Adds the trigram indexes behind fuzzy matching of film titles and actor
+-- names to databases created before them.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS actors_full_name_trgm_idx ON actors USING GIN ((name || ' ' || surname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS films_title_trgm_idx ON films USING GIN (title gin_trgm_ops);
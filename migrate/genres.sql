@@ -0,0 +1,14 @@
+-- Adds genres and the genres of films to databases created before them.
+
+CREATE TABLE IF NOT EXISTS genres(
+    id SERIAL PRIMARY KEY,
+    name varchar(64) not null UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS films_genres(
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    genre_id INTEGER NOT NULL REFERENCES genres(id),
+    PRIMARY KEY(film_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS films_genres_genre_id_idx ON films_genres (genre_id);
@@ -0,0 +1,14 @@
+-- Adds the watched history of users to databases created before it.
+
+CREATE TABLE IF NOT EXISTS viewings(
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    watched_on DATE NOT NULL,
+    note varchar(1000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS viewings_user_id_watched_on_idx ON viewings (user_id, watched_on, id);
+CREATE INDEX IF NOT EXISTS viewings_user_id_film_id_idx ON viewings (user_id, film_id, watched_on, id);
+CREATE INDEX IF NOT EXISTS viewings_film_id_idx ON viewings (film_id);
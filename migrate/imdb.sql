@@ -0,0 +1,11 @@
+-- Adds the IMDb IDs of films and actors, and the progress of the IMDb
+-- importer, to databases created before films could be imported from IMDb.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS imdb_id varchar(16) UNIQUE;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS imdb_id varchar(16) UNIQUE;
+
+CREATE TABLE IF NOT EXISTS imdb_progress(
+    file varchar(64) PRIMARY KEY,
+    line BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
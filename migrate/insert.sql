@@ -17,7 +17,7 @@ INSERT INTO actors (name, surname, patronymic, birthday, sex, information) VALUE
 ('Тоби',  'Магуайр', '', DATE('06/27/1975'), 'm', null);
 
 
-INSERT INTO films (title, year, information, rating) VALUES
+INSERT INTO films (title, year, information, editorial_rating) VALUES
 ('Оппенгеймер', 2023, '03:00', 9.0),
 ('Бойцовский клуб', 1999, '02:19', 9.1),
 ('Начало', 2010, '02:19', 8.9),
@@ -32,17 +32,42 @@ INSERT INTO films (title, year, information, rating) VALUES
 ('Бесславные ублюдки', 2009, '2:34', 8.9),
 ('Однажды в... Голливуде', 2019, '2:41', 8.7);
 
-INSERT INTO films_actors (film_id, actor_id) VALUES
-(1, 2),
-(2, 6),
-(3, 5),
-(4 ,9),
-(5, 11),
-(6, 12),
-(7, 12),
-(8, 13),
-(9, 13),
-(10, 13),
-(12, 6),
-(13, 6),
-(13, 5);
\ No newline at end of file
+INSERT INTO films_actors (film_id, actor_id, character_name, billing_order, credit_type) VALUES
+(1, 2, 'Роберт Оппенгеймер', 1, 'lead'),
+(2, 6, 'Тайлер Дёрден', 2, 'lead'),
+(3, 5, 'Кобб', 1, 'lead'),
+(4 ,9, 'Джулс Уиннфилд', 2, 'lead'),
+(5, 11, 'Микки Пирсон', 1, 'lead'),
+(6, 12, 'Данила Багров', 1, 'lead'),
+(7, 12, 'Данила Багров', 1, 'lead'),
+(8, 13, 'Питер Паркер', 1, 'lead'),
+(9, 13, 'Питер Паркер', 1, 'lead'),
+(10, 13, 'Питер Паркер', 1, 'lead'),
+(12, 6, 'Лейтенант Альдо Рейн', 1, 'lead'),
+(13, 6, 'Клифф Бут', 2, 'lead'),
+(13, 5, 'Рик Далтон', 1, 'lead');
+
+INSERT INTO genres (name) VALUES
+('драма'),
+('криминал'),
+('фантастика'),
+('боевик'),
+('комедия'),
+('приключения'),
+('аниме'),
+('биография');
+
+INSERT INTO films_genres (film_id, genre_id) VALUES
+(1, 1), (1, 8),
+(2, 1), (2, 2),
+(3, 3), (3, 4),
+(4, 2), (4, 5),
+(5, 2), (5, 5),
+(6, 1), (6, 2),
+(7, 2), (7, 4),
+(8, 3), (8, 4),
+(9, 3), (9, 4),
+(10, 3), (10, 4),
+(11, 6), (11, 7),
+(12, 1), (12, 4),
+(13, 1), (13, 5);
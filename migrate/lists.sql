@@ -0,0 +1,20 @@
+-- Adds the watchlists and favorites of users to databases created before
+-- them.
+
+CREATE TABLE IF NOT EXISTS watchlist(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_film_id_idx ON watchlist (film_id);
+
+CREATE TABLE IF NOT EXISTS favorites(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX IF NOT EXISTS favorites_film_id_idx ON favorites (film_id);
@@ -0,0 +1,5 @@
+-- Adds the keys of film posters and actor photos in the blob store to
+-- databases created before images could be uploaded.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS poster_key varchar(512);
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS photo_key varchar(512);
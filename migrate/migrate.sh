@@ -1,4 +1,19 @@
 #!/bin/bash
 
 psql -w -f migrate/create_db.sql
-psql -w -f migrate/insert.sql
\ No newline at end of file
+psql -w -f migrate/insert.sql
+psql -w -f migrate/search.sql
+psql -w -f migrate/fuzzy.sql
+psql -w -f migrate/genres.sql
+psql -w -f migrate/credits.sql
+psql -w -f migrate/film_metadata.sql
+psql -w -f migrate/media.sql
+psql -w -f migrate/ratings.sql
+psql -w -f migrate/lists.sql
+psql -w -f migrate/history.sql
+psql -w -f migrate/imdb.sql
+psql -w -f migrate/trash.sql
+psql -w -f migrate/audit.sql
+psql -w -f migrate/revisions.sql
+psql -w -f migrate/versions.sql
+psql -w -f migrate/updated_at.sql
@@ -0,0 +1,23 @@
+-- Adds user ratings to databases created before them. The rating admins
+-- used to set by hand becomes the editorial rating, films.rating is the
+-- average of user votes from now on.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS votes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE films ADD COLUMN IF NOT EXISTS editorial_rating DECIMAL(3,1) CHECK (editorial_rating BETWEEN 0 AND 10);
+
+CREATE TABLE IF NOT EXISTS ratings(
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 0 AND 10),
+    rated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(user_id, film_id)
+);
+
+CREATE INDEX IF NOT EXISTS ratings_film_id_idx ON ratings (film_id);
+
+UPDATE films
+SET editorial_rating = rating,
+    rating = NULL
+WHERE editorial_rating IS NULL
+  AND rating IS NOT NULL
+  AND votes = 0;
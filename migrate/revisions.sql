@@ -0,0 +1,31 @@
+-- Adds the revisions of films and actors to databases created before them.
+-- Every update saves the editable fields of the film or actor as a new
+-- revision, the first revision is how it was before its first update.
+
+CREATE TABLE IF NOT EXISTS film_revisions(
+    film_id INTEGER NOT NULL REFERENCES films(id),
+    rev INTEGER NOT NULL,
+    title varchar(150) not null,
+    year INT not null,
+    information varchar(1000),
+    editorial_rating DECIMAL(3,1),
+    runtime_minutes INTEGER,
+    release_date DATE,
+    countries varchar(2)[] not null DEFAULT '{}',
+    original_language varchar(3),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(film_id, rev)
+);
+
+CREATE TABLE IF NOT EXISTS actor_revisions(
+    actor_id INTEGER NOT NULL REFERENCES actors(id),
+    rev INTEGER NOT NULL,
+    name varchar(256) not null,
+    surname varchar(256) not null,
+    patronymic varchar(256),
+    birthday DATE not null,
+    sex CHAR(1) not null,
+    information varchar(2048),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY(actor_id, rev)
+);
@@ -0,0 +1,16 @@
+-- Adds the full-text search vectors of films and actors to databases
+-- created before search existed.
+
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', name || ' ' || surname || ' ' || coalesce(patronymic, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(information, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS actors_search_idx ON actors USING GIN (search);
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', title), 'A') ||
+    setweight(to_tsvector('russian', coalesce(information, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS films_search_idx ON films USING GIN (search);
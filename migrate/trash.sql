@@ -0,0 +1,8 @@
+-- Adds the trash to databases created before films and actors were deleted
+-- softly. Deleted films and actors keep their credits until they are purged.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS films_deleted_at_idx ON films (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS actors_deleted_at_idx ON actors (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Adds the time of the last change to films and actors in databases created
+-- before it. It is kept by a trigger, so every update of a row moves it, and
+-- is sent as Last-Modified by reads of the catalog.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS films_updated_at_idx ON films (updated_at);
+CREATE INDEX IF NOT EXISTS actors_updated_at_idx ON actors (updated_at);
+
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS films_touch_updated_at ON films;
+CREATE TRIGGER films_touch_updated_at BEFORE UPDATE ON films
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
+
+DROP TRIGGER IF EXISTS actors_touch_updated_at ON actors;
+CREATE TRIGGER actors_touch_updated_at BEFORE UPDATE ON actors
+    FOR EACH ROW EXECUTE FUNCTION touch_updated_at();
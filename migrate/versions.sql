@@ -0,0 +1,6 @@
+-- Adds versions to films and actors in databases created before them. The
+-- version grows with every update, so that an update made from a stale copy
+-- can be refused.
+
+ALTER TABLE films ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;